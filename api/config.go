@@ -0,0 +1,38 @@
+package api
+
+import "gopkg.in/yaml.v3"
+
+// Config represents the configuration of the query API server
+type Config struct {
+	Port uint `yaml:"port"`
+}
+
+// NewConfig allows to build a new Config instance
+func NewConfig(port uint) *Config {
+	return &Config{
+		Port: port,
+	}
+}
+
+// DefaultConfig returns the default Config instance
+func DefaultConfig() *Config {
+	return NewConfig(8080)
+}
+
+// ParseConfig allows to parse a byte array as a Config instance.
+// If the api section is missing, the default configuration is returned.
+func ParseConfig(bz []byte) (*Config, error) {
+	type T struct {
+		Config *Config `yaml:"api"`
+	}
+	var cfg T
+	err := yaml.Unmarshal(bz, &cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	if cfg.Config == nil {
+		return DefaultConfig(), nil
+	}
+	return cfg.Config, nil
+}
@@ -0,0 +1,900 @@
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/bnb-chain/greenfield/types/resource"
+	permissiontypes "github.com/bnb-chain/greenfield/x/permission/types"
+	"github.com/gorilla/mux"
+
+	"github.com/forbole/juno/v4/common"
+	"github.com/forbole/juno/v4/log"
+	"github.com/forbole/juno/v4/models"
+	"github.com/forbole/juno/v4/modules/permission/evaluator"
+)
+
+// ObjectsResponse represents the response of the paginated objects endpoints.
+// NextStartAfter is the value to be used as start_after to get the next page, or zero if there are no more objects.
+type ObjectsResponse struct {
+	Objects        []*models.Object `json:"objects"`
+	NextStartAfter uint64           `json:"next_start_after"`
+}
+
+// GroupMembersResponse represents the response of the paginated group members endpoint.
+// NextStartAfter is the value to be used as start_after to get the next page, or zero if there are no more members.
+type GroupMembersResponse struct {
+	Members        []*models.GroupMember `json:"members"`
+	NextStartAfter uint64                `json:"next_start_after"`
+}
+
+// GroupsResponse represents the response of the paginated groups endpoint.
+// NextStartAfter is the value to be used as start_after to get the next page, or zero if there are no more groups.
+type GroupsResponse struct {
+	Groups         []*models.Group `json:"groups"`
+	NextStartAfter uint64          `json:"next_start_after"`
+}
+
+// Policy represents a policy along with its statements
+type Policy struct {
+	*models.Permission
+	Statements []*models.Statements `json:"statements"`
+}
+
+// BalanceResponse represents the stream record of an account at a given height.
+// Amounts are returned as decimal strings, and Balance is the static balance settled up to the time of the block.
+type BalanceResponse struct {
+	Account         common.Address `json:"account"`
+	Height          int64          `json:"height"`
+	UpdateHeight    int64          `json:"update_height"`
+	Balance         string         `json:"balance"`
+	StaticBalance   string         `json:"static_balance"`
+	NetflowRate     string         `json:"netflow_rate"`
+	BufferBalance   string         `json:"buffer_balance"`
+	LockBalance     string         `json:"lock_balance"`
+	Status          string         `json:"status"`
+	CrudTimestamp   int64          `json:"crud_timestamp"`
+	SettleTimestamp int64          `json:"settle_timestamp"`
+}
+
+// PaymentTransfer represents an entry of the payment ledger of an account, having its amount as a decimal string
+type PaymentTransfer struct {
+	ID          uint64         `json:"id"`
+	Kind        string         `json:"kind"`
+	Direction   string         `json:"direction"`
+	FromAddress common.Address `json:"from_address"`
+	ToAddress   common.Address `json:"to_address"`
+	Amount      string         `json:"amount"`
+	Height      int64          `json:"height"`
+	TxHash      common.Hash    `json:"tx_hash"`
+}
+
+// PaymentTransfersResponse represents the response of the paginated payment ledger endpoint.
+// PaymentAccount is nil when the account is not a payment account.
+type PaymentTransfersResponse struct {
+	PaymentAccount *models.PaymentAccount `json:"payment_account"`
+	Transfers      []*PaymentTransfer     `json:"transfers"`
+	NextStartAfter uint64                 `json:"next_start_after"`
+}
+
+// BankBalance represents the balance of an account in a denom, having its amount as a decimal string.
+// Height is the one the balance last changed at.
+type BankBalance struct {
+	Denom  string `json:"denom"`
+	Amount string `json:"amount"`
+	Height int64  `json:"height"`
+}
+
+// BankBalancesResponse represents the balances of an account at a given height
+type BankBalancesResponse struct {
+	Height   uint64         `json:"height"`
+	Balances []*BankBalance `json:"balances"`
+}
+
+// BankTransfer represents a coin movement of the x/bank module, having its amount as a decimal string
+type BankTransfer struct {
+	ID          uint64         `json:"id"`
+	Kind        string         `json:"kind"`
+	FromAddress common.Address `json:"from_address"`
+	ToAddress   common.Address `json:"to_address"`
+	Denom       string         `json:"denom"`
+	Amount      string         `json:"amount"`
+	Height      int64          `json:"height"`
+	TxHash      common.Hash    `json:"tx_hash"`
+}
+
+// BankTransfersResponse represents the response of the paginated bank transfers endpoint
+type BankTransfersResponse struct {
+	Transfers      []*BankTransfer `json:"transfers"`
+	NextStartAfter uint64          `json:"next_start_after"`
+}
+
+// TxsResponse represents the response of the paginated transactions endpoint
+type TxsResponse struct {
+	Txs            []*models.Tx `json:"txs"`
+	NextStartAfter uint64       `json:"next_start_after"`
+}
+
+// MessagesResponse represents the response of the paginated messages endpoint
+type MessagesResponse struct {
+	Messages       []*models.Message `json:"messages"`
+	NextStartAfter uint64            `json:"next_start_after"`
+}
+
+// PermissionResponse represents the decision about whether a principal can perform an action on a resource.
+// Policy and Statement are the ones that determined the effect, if any.
+type PermissionResponse struct {
+	Effect    string             `json:"effect"`
+	Public    bool               `json:"public"`
+	Owner     bool               `json:"owner"`
+	Policy    *models.Permission `json:"policy"`
+	Statement *models.Statements `json:"statement"`
+}
+
+// ProposalResponse represents a proposal along with its deposits
+type ProposalResponse struct {
+	*models.Proposal
+	Deposits []*models.ProposalDeposit `json:"deposits"`
+}
+
+// ProposalVotesResponse represents the response of the paginated proposal votes endpoint.
+// NextStartAfter is the value to be used as start_after to get the next page, or zero if there are no more votes.
+type ProposalVotesResponse struct {
+	Votes          []*models.ProposalVote `json:"votes"`
+	NextStartAfter uint64                 `json:"next_start_after"`
+}
+
+// MirrorResponse represents the latest request to mirror a resource, along with the SYN package carrying it, if indexed
+type MirrorResponse struct {
+	*models.Mirror
+	Package *models.CrossChainPackage `json:"package"`
+}
+
+// ErrorResponse represents the response returned when a request cannot be served
+type ErrorResponse struct {
+	Error string `json:"error"`
+}
+
+func (s *Server) getBucket(w http.ResponseWriter, r *http.Request) {
+	bucket, err := s.db.GetBucketByName(r.Context(), mux.Vars(r)["bucket_name"])
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+	if bucket == nil {
+		writeError(w, http.StatusNotFound, fmt.Errorf("bucket not found"))
+		return
+	}
+
+	writeJSON(w, bucket)
+}
+
+func (s *Server) listObjectsByBucket(w http.ResponseWriter, r *http.Request) {
+	startAfter, limit, err := parsePagination(r)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	objects, err := s.db.ListObjectsByBucketName(r.Context(), mux.Vars(r)["bucket_name"], startAfter, limit)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+
+	writeJSON(w, newObjectsResponse(objects, limit))
+}
+
+func (s *Server) listObjectsByOwner(w http.ResponseWriter, r *http.Request) {
+	address := mux.Vars(r)["address"]
+	if !common.IsHexAddress(address) {
+		writeError(w, http.StatusBadRequest, fmt.Errorf("invalid address %s", address))
+		return
+	}
+
+	startAfter, limit, err := parsePagination(r)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	objects, err := s.db.ListObjectsByOwner(r.Context(), common.HexToAddress(address), startAfter, limit)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+
+	writeJSON(w, newObjectsResponse(objects, limit))
+}
+
+func (s *Server) listGroupMembers(w http.ResponseWriter, r *http.Request) {
+	groupID, err := parseID(mux.Vars(r)["group_id"])
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	startAfter, limit, err := parsePagination(r)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	members, err := s.db.ListGroupMembers(r.Context(), groupID, time.Now().Unix(), startAfter, limit)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+
+	res := &GroupMembersResponse{Members: members}
+	if res.Members == nil {
+		res.Members = []*models.GroupMember{}
+	}
+	if len(members) == limit {
+		res.NextStartAfter = members[len(members)-1].ID
+	}
+
+	writeJSON(w, res)
+}
+
+func (s *Server) listGroupsByMember(w http.ResponseWriter, r *http.Request) {
+	address := mux.Vars(r)["address"]
+	if !common.IsHexAddress(address) {
+		writeError(w, http.StatusBadRequest, fmt.Errorf("invalid address %s", address))
+		return
+	}
+
+	startAfter, limit, err := parsePagination(r)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	groups, err := s.db.ListGroupsByMember(r.Context(), common.HexToAddress(address), time.Now().Unix(), startAfter, limit)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+
+	res := &GroupsResponse{Groups: groups}
+	if res.Groups == nil {
+		res.Groups = []*models.Group{}
+	}
+	if len(groups) == limit {
+		res.NextStartAfter = groups[len(groups)-1].ID
+	}
+
+	writeJSON(w, res)
+}
+
+func (s *Server) listPolicies(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	resourceID, err := parseID(vars["resource_id"])
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	permissions, err := s.db.ListPoliciesByResource(r.Context(), vars["resource_type"], resourceID)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+
+	policyIDs := make([]common.Hash, len(permissions))
+	for i, permission := range permissions {
+		policyIDs[i] = permission.PolicyID
+	}
+
+	statements, err := s.db.GetStatements(r.Context(), policyIDs)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+
+	policies := make([]*Policy, len(permissions))
+	byPolicyID := make(map[common.Hash]*Policy, len(permissions))
+	for i, permission := range permissions {
+		policies[i] = &Policy{Permission: permission, Statements: []*models.Statements{}}
+		byPolicyID[permission.PolicyID] = policies[i]
+	}
+	for _, statement := range statements {
+		if policy, ok := byPolicyID[statement.PolicyID]; ok {
+			policy.Statements = append(policy.Statements, statement)
+		}
+	}
+
+	writeJSON(w, policies)
+}
+
+func (s *Server) getProposal(w http.ResponseWriter, r *http.Request) {
+	proposalID, err := strconv.ParseUint(mux.Vars(r)["proposal_id"], 10, 64)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, fmt.Errorf("invalid proposal id: %s", err))
+		return
+	}
+
+	proposal, err := s.db.GetProposal(r.Context(), proposalID)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+	if proposal == nil {
+		writeError(w, http.StatusNotFound, fmt.Errorf("proposal not found"))
+		return
+	}
+
+	deposits, err := s.db.GetProposalDeposits(r.Context(), proposalID)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+
+	res := &ProposalResponse{Proposal: proposal, Deposits: deposits}
+	if res.Deposits == nil {
+		res.Deposits = []*models.ProposalDeposit{}
+	}
+
+	writeJSON(w, res)
+}
+
+func (s *Server) listProposalVotes(w http.ResponseWriter, r *http.Request) {
+	proposalID, err := strconv.ParseUint(mux.Vars(r)["proposal_id"], 10, 64)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, fmt.Errorf("invalid proposal id: %s", err))
+		return
+	}
+
+	startAfter, limit, err := parsePagination(r)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	votes, err := s.db.ListProposalVotes(r.Context(), proposalID, startAfter, limit)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+
+	res := &ProposalVotesResponse{Votes: votes}
+	if res.Votes == nil {
+		res.Votes = []*models.ProposalVote{}
+	}
+	if len(votes) == limit {
+		res.NextStartAfter = votes[len(votes)-1].ID
+	}
+
+	writeJSON(w, res)
+}
+
+func (s *Server) getMirror(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	resourceID, err := parseID(vars["resource_id"])
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	mirror, err := s.db.GetMirror(r.Context(), vars["resource_type"], resourceID)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+	if mirror == nil {
+		writeError(w, http.StatusNotFound, fmt.Errorf("mirror not found"))
+		return
+	}
+
+	res := &MirrorResponse{Mirror: mirror}
+	if mirror.ChannelID != 0 {
+		res.Package, err = s.db.GetSentPackage(r.Context(), mirror.DestChainID, mirror.ChannelID, mirror.Sequence)
+		if err != nil {
+			writeError(w, http.StatusInternalServerError, err)
+			return
+		}
+	}
+
+	writeJSON(w, res)
+}
+
+func (s *Server) listCrossChainPackagesByTx(w http.ResponseWriter, r *http.Request) {
+	hash := mux.Vars(r)["hash"]
+	if !common.IsHexHash(hash) {
+		writeError(w, http.StatusBadRequest, fmt.Errorf("invalid tx hash %s", hash))
+		return
+	}
+
+	packages, err := s.db.GetCrossChainPackagesByTx(r.Context(), common.HexToHash(hash))
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+	if packages == nil {
+		packages = []*models.CrossChainPackage{}
+	}
+
+	writeJSON(w, packages)
+}
+
+func (s *Server) listMessagesByType(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	msgType := query.Get("type")
+	if msgType == "" {
+		writeError(w, http.StatusBadRequest, fmt.Errorf("missing type"))
+		return
+	}
+
+	var fromHeight, toHeight uint64
+	var err error
+	if value := query.Get("from_height"); value != "" {
+		fromHeight, err = strconv.ParseUint(value, 10, 64)
+		if err != nil {
+			writeError(w, http.StatusBadRequest, fmt.Errorf("invalid from_height: %s", err))
+			return
+		}
+	}
+	if value := query.Get("to_height"); value != "" {
+		toHeight, err = strconv.ParseUint(value, 10, 64)
+		if err != nil {
+			writeError(w, http.StatusBadRequest, fmt.Errorf("invalid to_height: %s", err))
+			return
+		}
+	} else {
+		toHeight, err = s.db.GetLastBlockHeight(r.Context())
+		if err != nil {
+			writeError(w, http.StatusInternalServerError, err)
+			return
+		}
+	}
+
+	startAfter, limit, err := parsePagination(r)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	msgs, err := s.db.ListMessagesByType(r.Context(), msgType, int64(fromHeight), int64(toHeight), startAfter, limit)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+
+	res := &MessagesResponse{Messages: msgs}
+	if res.Messages == nil {
+		res.Messages = []*models.Message{}
+	}
+	if len(msgs) == limit {
+		res.NextStartAfter = msgs[len(msgs)-1].ID
+	}
+
+	writeJSON(w, res)
+}
+
+func (s *Server) getTx(w http.ResponseWriter, r *http.Request) {
+	hash := mux.Vars(r)["hash"]
+	if !common.IsHexHash(hash) {
+		writeError(w, http.StatusBadRequest, fmt.Errorf("invalid tx hash %s", hash))
+		return
+	}
+
+	tx, err := s.db.GetTxByHash(r.Context(), common.HexToHash(hash))
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+	if tx == nil {
+		writeError(w, http.StatusNotFound, fmt.Errorf("tx not found"))
+		return
+	}
+
+	writeJSON(w, tx)
+}
+
+func (s *Server) listTxsByHeight(w http.ResponseWriter, r *http.Request) {
+	height, err := strconv.ParseUint(mux.Vars(r)["height"], 10, 64)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, fmt.Errorf("invalid height: %s", err))
+		return
+	}
+
+	txs, err := s.db.GetTxsByHeight(r.Context(), height)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+	if txs == nil {
+		txs = []*models.Tx{}
+	}
+
+	writeJSON(w, txs)
+}
+
+func (s *Server) listTxsByAddress(w http.ResponseWriter, r *http.Request) {
+	address := mux.Vars(r)["address"]
+	if !common.IsHexAddress(address) {
+		writeError(w, http.StatusBadRequest, fmt.Errorf("invalid address %s", address))
+		return
+	}
+
+	startAfter, limit, err := parsePagination(r)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	txs, err := s.db.GetTxsByAddress(r.Context(), common.HexToAddress(address), startAfter, limit)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+
+	res := &TxsResponse{Txs: txs}
+	if res.Txs == nil {
+		res.Txs = []*models.Tx{}
+	}
+	if len(txs) == limit {
+		res.NextStartAfter = txs[len(txs)-1].ID
+	}
+
+	writeJSON(w, res)
+}
+
+func (s *Server) getBalance(w http.ResponseWriter, r *http.Request) {
+	address := mux.Vars(r)["address"]
+	if !common.IsHexAddress(address) {
+		writeError(w, http.StatusBadRequest, fmt.Errorf("invalid address %s", address))
+		return
+	}
+
+	var height uint64
+	var err error
+	if value := r.URL.Query().Get("height"); value != "" {
+		height, err = strconv.ParseUint(value, 10, 64)
+		if err != nil {
+			writeError(w, http.StatusBadRequest, fmt.Errorf("invalid height: %s", err))
+			return
+		}
+	} else {
+		height, err = s.db.GetLastBlockHeight(r.Context())
+		if err != nil {
+			writeError(w, http.StatusInternalServerError, err)
+			return
+		}
+	}
+
+	block, err := s.db.GetBlock(r.Context(), height)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+	if block == nil {
+		writeError(w, http.StatusNotFound, fmt.Errorf("block %d not found", height))
+		return
+	}
+
+	history, err := s.db.GetStreamRecordAtHeight(r.Context(), common.HexToAddress(address), int64(height))
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+	if history == nil {
+		writeError(w, http.StatusNotFound, fmt.Errorf("stream record not found"))
+		return
+	}
+
+	writeJSON(w, newBalanceResponse(history, block))
+}
+
+func (s *Server) listPaymentTransfers(w http.ResponseWriter, r *http.Request) {
+	address := mux.Vars(r)["address"]
+	if !common.IsHexAddress(address) {
+		writeError(w, http.StatusBadRequest, fmt.Errorf("invalid address %s", address))
+		return
+	}
+
+	startAfter, limit, err := parsePagination(r)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	paymentAccount, err := s.db.GetPaymentAccount(r.Context(), common.HexToAddress(address))
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+
+	transfers, err := s.db.ListPaymentTransfers(r.Context(), common.HexToAddress(address), startAfter, limit)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+
+	res := &PaymentTransfersResponse{
+		PaymentAccount: paymentAccount,
+		Transfers:      make([]*PaymentTransfer, len(transfers)),
+	}
+	for i, transfer := range transfers {
+		res.Transfers[i] = &PaymentTransfer{
+			ID:          transfer.ID,
+			Kind:        transfer.Kind,
+			Direction:   transfer.Direction,
+			FromAddress: transfer.FromAddress,
+			ToAddress:   transfer.ToAddress,
+			Amount:      bigOrZero(transfer.Amount).String(),
+			Height:      transfer.Height,
+			TxHash:      transfer.TxHash,
+		}
+	}
+	if len(transfers) == limit {
+		res.NextStartAfter = transfers[len(transfers)-1].ID
+	}
+
+	writeJSON(w, res)
+}
+
+func (s *Server) listBankBalances(w http.ResponseWriter, r *http.Request) {
+	address := mux.Vars(r)["address"]
+	if !common.IsHexAddress(address) {
+		writeError(w, http.StatusBadRequest, fmt.Errorf("invalid address %s", address))
+		return
+	}
+
+	var height uint64
+	var err error
+	if value := r.URL.Query().Get("height"); value != "" {
+		height, err = strconv.ParseUint(value, 10, 64)
+		if err != nil {
+			writeError(w, http.StatusBadRequest, fmt.Errorf("invalid height: %s", err))
+			return
+		}
+	} else {
+		height, err = s.db.GetLastBlockHeight(r.Context())
+		if err != nil {
+			writeError(w, http.StatusInternalServerError, err)
+			return
+		}
+	}
+
+	balances, err := s.db.GetAccountBalances(r.Context(), common.HexToAddress(address), int64(height))
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+
+	res := &BankBalancesResponse{
+		Height:   height,
+		Balances: make([]*BankBalance, len(balances)),
+	}
+	for i, balance := range balances {
+		res.Balances[i] = &BankBalance{
+			Denom:  balance.Denom,
+			Amount: bigOrZero(balance.Amount).String(),
+			Height: balance.Height,
+		}
+	}
+
+	writeJSON(w, res)
+}
+
+func (s *Server) listBankTransfers(w http.ResponseWriter, r *http.Request) {
+	address := mux.Vars(r)["address"]
+	if !common.IsHexAddress(address) {
+		writeError(w, http.StatusBadRequest, fmt.Errorf("invalid address %s", address))
+		return
+	}
+
+	startAfter, limit, err := parsePagination(r)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	transfers, err := s.db.ListTransfers(r.Context(), common.HexToAddress(address), startAfter, limit)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+
+	res := &BankTransfersResponse{
+		Transfers: make([]*BankTransfer, len(transfers)),
+	}
+	for i, transfer := range transfers {
+		res.Transfers[i] = &BankTransfer{
+			ID:          transfer.ID,
+			Kind:        transfer.Kind,
+			FromAddress: transfer.FromAddress,
+			ToAddress:   transfer.ToAddress,
+			Denom:       transfer.Denom,
+			Amount:      bigOrZero(transfer.Amount).String(),
+			Height:      transfer.Height,
+			TxHash:      transfer.TxHash,
+		}
+	}
+	if len(transfers) == limit {
+		res.NextStartAfter = transfers[len(transfers)-1].ID
+	}
+
+	writeJSON(w, res)
+}
+
+func (s *Server) verifyPermission(w http.ResponseWriter, r *http.Request) {
+	req, err := parsePermissionRequest(r)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	decision, err := s.evaluator.Evaluate(r.Context(), req)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+
+	writeJSON(w, &PermissionResponse{
+		Effect:    decision.Effect.String(),
+		Public:    decision.Public,
+		Owner:     decision.Owner,
+		Policy:    decision.Permission,
+		Statement: decision.Statement,
+	})
+}
+
+// parsePermissionRequest parses the query parameters of the given permission verification request
+func parsePermissionRequest(r *http.Request) (*evaluator.Request, error) {
+	query := r.URL.Query()
+
+	principal := query.Get("principal")
+	if !common.IsHexAddress(principal) {
+		return nil, fmt.Errorf("invalid principal %s", principal)
+	}
+
+	action, ok := permissiontypes.ActionType_value[query.Get("action")]
+	if !ok {
+		return nil, fmt.Errorf("invalid action %s", query.Get("action"))
+	}
+
+	resourceType, ok := resource.ResourceType_value[query.Get("resource_type")]
+	if !ok {
+		return nil, fmt.Errorf("invalid resource_type %s", query.Get("resource_type"))
+	}
+
+	resourceID, err := parseID(query.Get("resource_id"))
+	if err != nil {
+		return nil, err
+	}
+
+	req := &evaluator.Request{
+		Principal:    common.HexToAddress(principal),
+		Action:       permissiontypes.ActionType(action),
+		ResourceType: resource.ResourceType(resourceType),
+		ResourceID:   resourceID,
+		Time:         time.Now(),
+	}
+
+	if value := query.Get("time"); value != "" {
+		timestamp, err := strconv.ParseInt(value, 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid time: %s", err)
+		}
+		req.Time = time.Unix(timestamp, 0)
+	}
+
+	if value := query.Get("size"); value != "" {
+		size, err := strconv.ParseUint(value, 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid size: %s", err)
+		}
+		req.WantedSize = &size
+	}
+
+	return req, nil
+}
+
+// newBalanceResponse builds the response for the given stream record state, settled up to the time of the given block
+func newBalanceResponse(history *models.StreamRecordHistory, block *models.Block) *BalanceResponse {
+	// the static balance only changes when the stream record is updated, while the netflow rate applies every second
+	balance := new(big.Int).SetInt64(int64(block.Timestamp) - history.CrudTimestamp)
+	balance.Mul(balance, bigOrZero(history.NetflowRate))
+	balance.Add(balance, bigOrZero(history.StaticBalance))
+
+	return &BalanceResponse{
+		Account:         history.Account,
+		Height:          int64(block.Height),
+		UpdateHeight:    history.Height,
+		Balance:         balance.String(),
+		StaticBalance:   bigOrZero(history.StaticBalance).String(),
+		NetflowRate:     bigOrZero(history.NetflowRate).String(),
+		BufferBalance:   bigOrZero(history.BufferBalance).String(),
+		LockBalance:     bigOrZero(history.LockBalance).String(),
+		Status:          history.Status,
+		CrudTimestamp:   history.CrudTimestamp,
+		SettleTimestamp: history.SettleTimestamp,
+	}
+}
+
+// bigOrZero returns the given value as a big.Int, or zero if it is nil
+func bigOrZero(value *common.Big) *big.Int {
+	if value == nil {
+		return new(big.Int)
+	}
+	return (*big.Int)(value)
+}
+
+// newObjectsResponse builds the response for the given page of objects, fetched using the given limit
+func newObjectsResponse(objects []*models.Object, limit int) *ObjectsResponse {
+	res := &ObjectsResponse{Objects: objects}
+	if res.Objects == nil {
+		res.Objects = []*models.Object{}
+	}
+	if len(objects) == limit {
+		res.NextStartAfter = objects[len(objects)-1].ID
+	}
+	return res
+}
+
+// parsePagination parses the start_after and limit query parameters of the given request
+func parsePagination(r *http.Request) (startAfter uint64, limit int, err error) {
+	query := r.URL.Query()
+
+	if value := query.Get("start_after"); value != "" {
+		startAfter, err = strconv.ParseUint(value, 10, 64)
+		if err != nil {
+			return 0, 0, fmt.Errorf("invalid start_after: %s", err)
+		}
+	}
+
+	limit = DefaultLimit
+	if value := query.Get("limit"); value != "" {
+		limit, err = strconv.Atoi(value)
+		if err != nil {
+			return 0, 0, fmt.Errorf("invalid limit: %s", err)
+		}
+		if limit <= 0 || limit > MaxLimit {
+			return 0, 0, fmt.Errorf("limit must be between 1 and %d", MaxLimit)
+		}
+	}
+
+	return startAfter, limit, nil
+}
+
+// parseID parses the given on-chain id, which can either be a decimal number or a 0x prefixed hash
+func parseID(value string) (common.Hash, error) {
+	if strings.HasPrefix(value, "0x") {
+		if !common.IsHexHash(value) {
+			return common.Hash{}, fmt.Errorf("invalid id %s", value)
+		}
+		return common.HexToHash(value), nil
+	}
+
+	id, ok := new(big.Int).SetString(value, 10)
+	if !ok || id.Sign() < 0 {
+		return common.Hash{}, fmt.Errorf("invalid id %s", value)
+	}
+	return common.BigToHash(id), nil
+}
+
+// writeJSON writes the given value as the JSON body of the response
+func writeJSON(w http.ResponseWriter, value interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	err := json.NewEncoder(w).Encode(value)
+	if err != nil {
+		log.Errorw("failed to write response", "err", err)
+	}
+}
+
+// writeError writes the given error as the JSON body of the response, using the given status code
+func writeError(w http.ResponseWriter, status int, err error) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	err = json.NewEncoder(w).Encode(ErrorResponse{Error: err.Error()})
+	if err != nil {
+		log.Errorw("failed to write response", "err", err)
+	}
+}
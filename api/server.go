@@ -0,0 +1,73 @@
+package api
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/gorilla/mux"
+
+	"github.com/forbole/juno/v4/database"
+	"github.com/forbole/juno/v4/modules/permission/evaluator"
+)
+
+const (
+	// DefaultLimit is the number of items returned by the paginated endpoints when no limit is given
+	DefaultLimit = 100
+
+	// MaxLimit is the max number of items that can be returned by the paginated endpoints
+	MaxLimit = 1000
+)
+
+// Server represents a read-only HTTP server exposing the indexed data as JSON
+type Server struct {
+	cfg       *Config
+	db        database.Database
+	evaluator *evaluator.Evaluator
+}
+
+// NewServer builds a new Server instance
+func NewServer(cfg *Config, db database.Database) *Server {
+	return &Server{
+		cfg:       cfg,
+		db:        db,
+		evaluator: evaluator.NewEvaluator(db),
+	}
+}
+
+// Router returns the router handling all the endpoints exposed by the server
+func (s *Server) Router() *mux.Router {
+	router := mux.NewRouter()
+	router.HandleFunc("/buckets/{bucket_name}", s.getBucket).Methods(http.MethodGet)
+	router.HandleFunc("/buckets/{bucket_name}/objects", s.listObjectsByBucket).Methods(http.MethodGet)
+	router.HandleFunc("/accounts/{address}/objects", s.listObjectsByOwner).Methods(http.MethodGet)
+	router.HandleFunc("/accounts/{address}/balance", s.getBalance).Methods(http.MethodGet)
+	router.HandleFunc("/accounts/{address}/payment_transfers", s.listPaymentTransfers).Methods(http.MethodGet)
+	router.HandleFunc("/accounts/{address}/bank_balances", s.listBankBalances).Methods(http.MethodGet)
+	router.HandleFunc("/accounts/{address}/bank_transfers", s.listBankTransfers).Methods(http.MethodGet)
+	router.HandleFunc("/accounts/{address}/groups", s.listGroupsByMember).Methods(http.MethodGet)
+	router.HandleFunc("/accounts/{address}/txs", s.listTxsByAddress).Methods(http.MethodGet)
+	router.HandleFunc("/groups/{group_id}/members", s.listGroupMembers).Methods(http.MethodGet)
+	router.HandleFunc("/policies/{resource_type}/{resource_id}", s.listPolicies).Methods(http.MethodGet)
+	router.HandleFunc("/permissions/verify", s.verifyPermission).Methods(http.MethodGet)
+	router.HandleFunc("/proposals/{proposal_id}", s.getProposal).Methods(http.MethodGet)
+	router.HandleFunc("/proposals/{proposal_id}/votes", s.listProposalVotes).Methods(http.MethodGet)
+	router.HandleFunc("/mirrors/{resource_type}/{resource_id}", s.getMirror).Methods(http.MethodGet)
+	router.HandleFunc("/messages", s.listMessagesByType).Methods(http.MethodGet)
+	router.HandleFunc("/txs/{hash}", s.getTx).Methods(http.MethodGet)
+	router.HandleFunc("/txs/{hash}/cross_chain_packages", s.listCrossChainPackagesByTx).Methods(http.MethodGet)
+	router.HandleFunc("/blocks/{height}/txs", s.listTxsByHeight).Methods(http.MethodGet)
+	return router
+}
+
+// Start starts the server, blocking until it fails
+func (s *Server) Start() error {
+	server := http.Server{
+		Addr:         fmt.Sprintf(":%d", s.cfg.Port),
+		Handler:      s.Router(),
+		ReadTimeout:  5 * time.Second,
+		WriteTimeout: 10 * time.Second,
+	}
+
+	return server.ListenAndServe()
+}
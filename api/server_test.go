@@ -0,0 +1,195 @@
+package api_test
+
+import (
+	"context"
+	"encoding/json"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/forbole/juno/v4/api"
+	"github.com/forbole/juno/v4/common"
+	"github.com/forbole/juno/v4/database"
+	"github.com/forbole/juno/v4/models"
+)
+
+// stubDatabase serves the rows read by the endpoints under test, any other method panicking
+type stubDatabase struct {
+	database.Database
+
+	bucket  *models.Bucket
+	objects []*models.Object
+	tx      *models.Tx
+
+	startAfter uint64
+	limit      int
+	groupID    common.Hash
+}
+
+func (db *stubDatabase) GetBucketByName(_ context.Context, bucketName string) (*models.Bucket, error) {
+	if db.bucket == nil || db.bucket.BucketName != bucketName {
+		return nil, nil
+	}
+	return db.bucket, nil
+}
+
+func (db *stubDatabase) ListObjectsByBucketName(_ context.Context, _ string, startAfter uint64, limit int) ([]*models.Object, error) {
+	db.startAfter, db.limit = startAfter, limit
+	if len(db.objects) > limit {
+		return db.objects[:limit], nil
+	}
+	return db.objects, nil
+}
+
+func (db *stubDatabase) ListObjectsByOwner(_ context.Context, _ common.Address, startAfter uint64, limit int) ([]*models.Object, error) {
+	return db.ListObjectsByBucketName(context.Background(), "", startAfter, limit)
+}
+
+func (db *stubDatabase) ListGroupMembers(_ context.Context, groupID common.Hash, _ int64, _ uint64, _ int) ([]*models.GroupMember, error) {
+	db.groupID = groupID
+	return nil, nil
+}
+
+func (db *stubDatabase) GetProposal(context.Context, uint64) (*models.Proposal, error) {
+	return nil, nil
+}
+
+func (db *stubDatabase) GetTxByHash(_ context.Context, hash common.Hash) (*models.Tx, error) {
+	if db.tx == nil || db.tx.Hash != hash {
+		return nil, nil
+	}
+	return db.tx, nil
+}
+
+func (db *stubDatabase) GetTxsByHeight(context.Context, uint64) ([]*models.Tx, error) {
+	return nil, nil
+}
+
+func (db *stubDatabase) GetTxsByAddress(context.Context, common.Address, uint64, int) ([]*models.Tx, error) {
+	return nil, nil
+}
+
+func (db *stubDatabase) ListMessagesByType(context.Context, string, int64, int64, uint64, int) ([]*models.Message, error) {
+	return nil, nil
+}
+
+// get performs a GET request on the given path, returning the recorded response
+func get(t *testing.T, db database.Database, path string) *httptest.ResponseRecorder {
+	t.Helper()
+
+	recorder := httptest.NewRecorder()
+	api.NewServer(&api.Config{}, db).Router().ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, path, nil))
+	require.Equal(t, "application/json", recorder.Header().Get("Content-Type"))
+	return recorder
+}
+
+func TestPagination(t *testing.T) {
+	db := &stubDatabase{objects: []*models.Object{{ID: 3}, {ID: 5}, {ID: 8}}}
+
+	testCases := []struct {
+		name           string
+		query          string
+		status         int
+		startAfter     uint64
+		limit          int
+		nextStartAfter uint64
+	}{
+		{name: "default limit", query: "", status: http.StatusOK, limit: api.DefaultLimit},
+		{name: "full page", query: "?start_after=2&limit=2", status: http.StatusOK, startAfter: 2, limit: 2, nextStartAfter: 5},
+		{name: "max limit", query: "?limit=1000", status: http.StatusOK, limit: api.MaxLimit},
+		{name: "zero limit", query: "?limit=0", status: http.StatusBadRequest},
+		{name: "limit above max", query: "?limit=1001", status: http.StatusBadRequest},
+		{name: "invalid limit", query: "?limit=ten", status: http.StatusBadRequest},
+		{name: "negative start after", query: "?start_after=-1", status: http.StatusBadRequest},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			db.startAfter, db.limit = 0, 0
+
+			res := get(t, db, "/buckets/bucket/objects"+tc.query)
+			require.Equal(t, tc.status, res.Code)
+			if tc.status != http.StatusOK {
+				var errRes api.ErrorResponse
+				require.NoError(t, json.Unmarshal(res.Body.Bytes(), &errRes))
+				require.NotEmpty(t, errRes.Error)
+				return
+			}
+
+			var objectsRes api.ObjectsResponse
+			require.NoError(t, json.Unmarshal(res.Body.Bytes(), &objectsRes))
+			require.Equal(t, tc.startAfter, db.startAfter)
+			require.Equal(t, tc.limit, db.limit)
+			require.Equal(t, tc.nextStartAfter, objectsRes.NextStartAfter)
+		})
+	}
+}
+
+func TestBadRequests(t *testing.T) {
+	for _, path := range []string{
+		"/accounts/0x1234/objects",
+		"/accounts/not-an-address/txs",
+		"/groups/abc/members",
+		"/groups/-1/members",
+		"/groups/0xzz/members",
+		"/proposals/first",
+		"/txs/0x1234",
+		"/blocks/latest/txs",
+		"/messages",
+	} {
+		res := get(t, &stubDatabase{}, path)
+		require.Equal(t, http.StatusBadRequest, res.Code, path)
+	}
+}
+
+func TestNotFound(t *testing.T) {
+	db := &stubDatabase{
+		bucket: &models.Bucket{BucketName: "bucket"},
+		tx:     &models.Tx{Hash: common.HexToHash("0x01")},
+	}
+
+	for _, path := range []string{
+		"/buckets/missing",
+		"/proposals/7",
+		"/txs/" + common.HexToHash("0x02").Hex(),
+	} {
+		res := get(t, db, path)
+		require.Equal(t, http.StatusNotFound, res.Code, path)
+	}
+
+	res := get(t, db, "/buckets/bucket")
+	require.Equal(t, http.StatusOK, res.Code)
+	var bucket models.Bucket
+	require.NoError(t, json.Unmarshal(res.Body.Bytes(), &bucket))
+	require.Equal(t, "bucket", bucket.BucketName)
+
+	res = get(t, db, "/txs/"+common.HexToHash("0x01").Hex())
+	require.Equal(t, http.StatusOK, res.Code)
+}
+
+func TestParseID(t *testing.T) {
+	db := &stubDatabase{}
+
+	res := get(t, db, "/groups/256/members")
+	require.Equal(t, http.StatusOK, res.Code)
+	require.Equal(t, common.BigToHash(big.NewInt(256)), db.groupID)
+
+	res = get(t, db, "/groups/"+common.HexToHash("0x0100").Hex()+"/members")
+	require.Equal(t, http.StatusOK, res.Code)
+	require.Equal(t, common.HexToHash("0x0100"), db.groupID)
+}
+
+func TestEmptyPages(t *testing.T) {
+	for _, path := range []string{
+		"/blocks/10/txs",
+		"/accounts/" + common.HexToAddress("0x01").Hex() + "/txs",
+		"/messages?type=/cosmos.bank.v1beta1.MsgSend&to_height=10",
+	} {
+		res := get(t, &stubDatabase{}, path)
+		require.Equal(t, http.StatusOK, res.Code, path)
+		require.NotContains(t, res.Body.String(), "null", path)
+	}
+}
@@ -8,6 +8,8 @@ import (
 	initcmd "github.com/forbole/juno/v4/cmd/init"
 	migratecmd "github.com/forbole/juno/v4/cmd/migrate"
 	parsecmd "github.com/forbole/juno/v4/cmd/parse"
+	rollbackcmd "github.com/forbole/juno/v4/cmd/rollback"
+	servecmd "github.com/forbole/juno/v4/cmd/serve"
 	startcmd "github.com/forbole/juno/v4/cmd/start"
 	"github.com/forbole/juno/v4/types"
 	"github.com/forbole/juno/v4/types/config"
@@ -42,6 +44,8 @@ func BuildDefaultExecutor(config *Config) cli.Executor {
 		parsecmd.NewParseCmd(config.GetParseConfig()),
 		startcmd.NewStartCmd(config.GetParseConfig()),
 		migratecmd.NewMigrateCmd(config.GetName(), config.GetParseConfig()),
+		rollbackcmd.NewRollbackCmd(config.GetParseConfig()),
+		servecmd.NewServeCmd(config.GetParseConfig()),
 	)
 
 	return PrepareRootCmd(config.GetName(), rootCmd)
@@ -36,8 +36,8 @@ will be replaced with the data downloaded from the node.
 				return err
 			}
 
-			workerCtx := parser.NewContext(parseCtx.EncodingConfig, parseCtx.Node, parseCtx.Database, parseCtx.Modules, nil)
-			worker := parser.NewWorker(workerCtx, nil, 0, false)
+			workerCtx := parser.NewContext(parseCtx.EncodingConfig, parseCtx.Node, parseCtx.Database, parseCtx.Sink, parseCtx.Modules, nil)
+			worker := parser.NewWorker(workerCtx, 0)
 
 			// Get the flag values
 			start, _ := cmd.Flags().GetInt64(flagStart)
@@ -0,0 +1,128 @@
+package blocks
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	parsecmdtypes "github.com/forbole/juno/v4/cmd/parse/types"
+	"github.com/forbole/juno/v4/log"
+	"github.com/forbole/juno/v4/models"
+	"github.com/forbole/juno/v4/parser"
+	"github.com/forbole/juno/v4/types/config"
+)
+
+// newFailedCmd returns a Cobra command that allows to handle the heights that exhausted their retries
+func newFailedCmd(parseConfig *parsecmdtypes.Config) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "failed",
+		Short: "List or retry the heights that could not be processed after exhausting their retries",
+	}
+
+	cmd.AddCommand(
+		newFailedListCmd(parseConfig),
+		newFailedRetryCmd(parseConfig),
+	)
+
+	return cmd
+}
+
+// newFailedListCmd returns a Cobra command that lists the failed heights stored inside the database
+func newFailedListCmd(parseConfig *parsecmdtypes.Config) *cobra.Command {
+	return &cobra.Command{
+		Use:   "list",
+		Short: "List the failed heights along with their last error",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			parseCtx, err := parsecmdtypes.GetParserContext(config.Cfg, parseConfig)
+			if err != nil {
+				return err
+			}
+
+			failedHeights, err := parseCtx.Database.GetFailedHeights(context.Background())
+			if err != nil {
+				return fmt.Errorf("error while getting failed heights: %s", err)
+			}
+
+			for _, failedHeight := range failedHeights {
+				cmd.Printf("%d\tattempts: %d\tfailed at: %s\terror: %s\n",
+					failedHeight.Height, failedHeight.Attempts,
+					time.Unix(failedHeight.UpdateAt, 0).UTC().Format(time.RFC3339), failedHeight.LastError)
+			}
+
+			return nil
+		},
+	}
+}
+
+// newFailedRetryCmd returns a Cobra command that processes again the failed heights
+func newFailedRetryCmd(parseConfig *parsecmdtypes.Config) *cobra.Command {
+	return &cobra.Command{
+		Use:   "retry [[height]]",
+		Short: "Process again the given failed height, or all of them if no height is given",
+		Args:  cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			parseCtx, err := parsecmdtypes.GetParserContext(config.Cfg, parseConfig)
+			if err != nil {
+				return err
+			}
+
+			workerCtx := parser.NewContext(parseCtx.EncodingConfig, parseCtx.Node, parseCtx.Database, parseCtx.Sink, parseCtx.Modules, nil)
+			worker := parser.NewWorker(workerCtx, 0)
+
+			ctx := context.Background()
+			var failedHeights []*models.FailedHeight
+			if len(args) == 1 {
+				height, err := strconv.ParseUint(args[0], 10, 64)
+				if err != nil {
+					return fmt.Errorf("make sure the given height is a positive integer")
+				}
+
+				failedHeight, err := parseCtx.Database.GetFailedHeight(ctx, height)
+				if err != nil {
+					return fmt.Errorf("error while getting failed height %d: %s", height, err)
+				}
+				if failedHeight == nil {
+					return fmt.Errorf("height %d is not marked as failed", height)
+				}
+				failedHeights = append(failedHeights, failedHeight)
+			} else {
+				failedHeights, err = parseCtx.Database.GetFailedHeights(ctx)
+				if err != nil {
+					return fmt.Errorf("error while getting failed heights: %s", err)
+				}
+			}
+
+			var failed int
+			for _, failedHeight := range failedHeights {
+				err = worker.ProcessIfNotExists(failedHeight.Height)
+				if err != nil {
+					log.Errorw("error while retrying failed height", "height", failedHeight.Height, "err", err)
+					failed++
+
+					failedHeight.Attempts++
+					failedHeight.LastError = err.Error()
+					failedHeight.UpdateAt = time.Now().UTC().Unix()
+					err = parseCtx.Database.SaveFailedHeight(ctx, failedHeight)
+					if err != nil {
+						return fmt.Errorf("error while updating failed height %d: %s", failedHeight.Height, err)
+					}
+					continue
+				}
+
+				err = parseCtx.Database.DeleteFailedHeight(ctx, failedHeight.Height)
+				if err != nil {
+					return fmt.Errorf("error while deleting failed height %d: %s", failedHeight.Height, err)
+				}
+				log.Infow("retried failed height", "height", failedHeight.Height)
+			}
+
+			if failed > 0 {
+				return fmt.Errorf("%d out of %d failed heights could not be processed", failed, len(failedHeights))
+			}
+			return nil
+		},
+	}
+}
@@ -29,8 +29,8 @@ func newMissingCmd(parseConfig *parsecmdtypes.Config) *cobra.Command {
 				return err
 			}
 
-			workerCtx := parser.NewContext(parseCtx.EncodingConfig, parseCtx.Node, parseCtx.Database, parseCtx.Modules, nil)
-			worker := parser.NewWorker(workerCtx, nil, 0, false)
+			workerCtx := parser.NewContext(parseCtx.EncodingConfig, parseCtx.Node, parseCtx.Database, parseCtx.Sink, parseCtx.Modules, nil)
+			worker := parser.NewWorker(workerCtx, 0)
 
 			ctx := context.Background()
 			dbLastHeight, err := parseCtx.Database.GetLastBlockHeight(ctx)
@@ -38,10 +38,17 @@ func newMissingCmd(parseConfig *parsecmdtypes.Config) *cobra.Command {
 				return fmt.Errorf("error while getting db last block height: %s", err)
 			}
 
-			for _, k := range parseCtx.Database.GetMissingHeights(ctx, startHeight, dbLastHeight) {
-				err = worker.Process(k)
-				if err != nil {
-					return fmt.Errorf("error while re-fetching block %d: %s", k, err)
+			missing, err := parseCtx.Database.GetMissingHeights(ctx, startHeight, dbLastHeight)
+			if err != nil {
+				return fmt.Errorf("error while getting missing heights: %s", err)
+			}
+
+			for _, gap := range missing {
+				for k := gap.StartHeight; k <= gap.EndHeight; k++ {
+					err = worker.Process(k)
+					if err != nil {
+						return fmt.Errorf("error while re-fetching block %d: %s", k, err)
+					}
 				}
 			}
 
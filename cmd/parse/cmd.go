@@ -6,8 +6,11 @@ import (
 	parsecmdtypes "github.com/forbole/juno/v4/cmd/parse/types"
 
 	parseblocks "github.com/forbole/juno/v4/cmd/parse/blocks"
+	parseevents "github.com/forbole/juno/v4/cmd/parse/events"
 	parsegenesis "github.com/forbole/juno/v4/cmd/parse/genesis"
+	parsemodules "github.com/forbole/juno/v4/cmd/parse/modules"
 	parsetransactions "github.com/forbole/juno/v4/cmd/parse/transactions"
+	parseusage "github.com/forbole/juno/v4/cmd/parse/usage"
 )
 
 // NewParseCmd returns the Cobra command allowing to parse some chain data without having to re-sync the whole database
@@ -20,8 +23,11 @@ func NewParseCmd(parseCfg *parsecmdtypes.Config) *cobra.Command {
 
 	cmd.AddCommand(
 		parseblocks.NewBlocksCmd(parseCfg),
+		parseevents.NewEventsCmd(parseCfg),
 		parsegenesis.NewGenesisCmd(parseCfg),
+		parsemodules.NewModulesCmd(parseCfg),
 		parsetransactions.NewTransactionsCmd(parseCfg),
+		parseusage.NewUsageCmd(parseCfg),
 	)
 
 	return cmd
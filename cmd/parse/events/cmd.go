@@ -0,0 +1,22 @@
+package events
+
+import (
+	"github.com/spf13/cobra"
+
+	parsecmdtypes "github.com/forbole/juno/v4/cmd/parse/types"
+)
+
+// NewEventsCmd returns the Cobra command that allows to handle the quarantined events
+func NewEventsCmd(parseConfig *parsecmdtypes.Config) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "events",
+		Short: "List or replay the events quarantined after their module failed to handle them",
+	}
+
+	cmd.AddCommand(
+		newListCmd(parseConfig),
+		newReplayCmd(parseConfig),
+	)
+
+	return cmd
+}
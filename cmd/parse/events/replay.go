@@ -0,0 +1,138 @@
+package events
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	parsecmdtypes "github.com/forbole/juno/v4/cmd/parse/types"
+	"github.com/forbole/juno/v4/log"
+	"github.com/forbole/juno/v4/models"
+	"github.com/forbole/juno/v4/modules"
+	"github.com/forbole/juno/v4/parser"
+	"github.com/forbole/juno/v4/types/config"
+)
+
+const (
+	flagModule = "module"
+)
+
+// newListCmd returns a Cobra command that lists the quarantined events stored inside the database
+func newListCmd(parseConfig *parsecmdtypes.Config) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "list",
+		Short: "List the quarantined events along with their last error",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			parseCtx, err := parsecmdtypes.GetParserContext(config.Cfg, parseConfig)
+			if err != nil {
+				return err
+			}
+
+			module, _ := cmd.Flags().GetString(flagModule)
+			events, err := parseCtx.Database.GetQuarantinedEvents(context.Background(), module)
+			if err != nil {
+				return fmt.Errorf("error while getting quarantined events: %s", err)
+			}
+
+			for _, event := range events {
+				cmd.Printf("%d\tmodule: %s\theight: %d\ttx: %s\tevent: %s\tattempts: %d\tfailed at: %s\terror: %s\n",
+					event.ID, event.Module, event.Height, event.TxHash.Hex(), event.EventType, event.Attempts,
+					time.Unix(event.UpdateTime, 0).UTC().Format(time.RFC3339), event.LastError)
+			}
+
+			return nil
+		},
+	}
+
+	cmd.Flags().String(flagModule, "", "Only list the events quarantined by the given module")
+
+	return cmd
+}
+
+// newReplayCmd returns a Cobra command that handles again the quarantined events
+func newReplayCmd(parseConfig *parsecmdtypes.Config) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "replay [[id]]",
+		Short: "Handle again the quarantined event having the given id, or all of them if no id is given",
+		Args:  cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			parseCtx, err := parsecmdtypes.GetParserContext(config.Cfg, parseConfig)
+			if err != nil {
+				return err
+			}
+
+			ctx := context.Background()
+			var events []*models.QuarantinedEvent
+			if len(args) == 1 {
+				id, err := strconv.ParseUint(args[0], 10, 64)
+				if err != nil {
+					return fmt.Errorf("make sure the given id is a positive integer")
+				}
+
+				event, err := parseCtx.Database.GetQuarantinedEvent(ctx, id)
+				if err != nil {
+					return fmt.Errorf("error while getting quarantined event %d: %s", id, err)
+				}
+				if event == nil {
+					return fmt.Errorf("event %d is not quarantined", id)
+				}
+				events = append(events, event)
+			} else {
+				module, _ := cmd.Flags().GetString(flagModule)
+				events, err = parseCtx.Database.GetQuarantinedEvents(ctx, module)
+				if err != nil {
+					return fmt.Errorf("error while getting quarantined events: %s", err)
+				}
+			}
+
+			var failed int
+			for _, event := range events {
+				err = replay(parseCtx, event)
+				if err != nil {
+					log.Errorw("error while replaying quarantined event", "id", event.ID, "module", event.Module, "err", err)
+					failed++
+
+					event.Attempts++
+					event.LastError = err.Error()
+					event.UpdateTime = time.Now().UTC().Unix()
+					err = parseCtx.Database.SaveQuarantinedEvent(ctx, event)
+					if err != nil {
+						return fmt.Errorf("error while updating quarantined event %d: %s", event.ID, err)
+					}
+					continue
+				}
+
+				log.Infow("replayed quarantined event", "id", event.ID, "module", event.Module, "height", event.Height)
+			}
+
+			if failed > 0 {
+				return fmt.Errorf("%d out of %d quarantined events could not be replayed", failed, len(events))
+			}
+			return nil
+		},
+	}
+
+	cmd.Flags().String(flagModule, "", "Only replay the events quarantined by the given module")
+
+	return cmd
+}
+
+// replay handles again the given event using the enabled module that quarantined it
+func replay(parseCtx *parser.Context, event *models.QuarantinedEvent) error {
+	for _, module := range parseCtx.Modules {
+		if module.Name() != event.Module {
+			continue
+		}
+
+		eventModule, ok := module.(modules.EventModule)
+		if !ok {
+			return fmt.Errorf("module %s does not handle events", event.Module)
+		}
+		return parser.ReplayEvent(parseCtx, eventModule, event)
+	}
+
+	return fmt.Errorf("module %s is not enabled", event.Module)
+}
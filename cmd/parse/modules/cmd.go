@@ -0,0 +1,123 @@
+package modules
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	parsecmdtypes "github.com/forbole/juno/v4/cmd/parse/types"
+	"github.com/forbole/juno/v4/log"
+	"github.com/forbole/juno/v4/modules"
+	"github.com/forbole/juno/v4/modules/block"
+	"github.com/forbole/juno/v4/parser"
+	"github.com/forbole/juno/v4/types/config"
+)
+
+const (
+	flagStart          = "start"
+	flagEnd            = "end"
+	flagRecreateTables = "recreate-tables"
+)
+
+// NewModulesCmd returns the Cobra command that allows to reprocess a single module over a range of heights
+func NewModulesCmd(parseConfig *parsecmdtypes.Config) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "modules [module name]",
+		Short: "Reprocess the given module over the blocks ranged from the given start height to the given end height",
+		Long: fmt.Sprintf(`Refetch all the blocks in the specified range from the node and call only the block, transaction, 
+message and event handlers of the given module on them. Blocks and transactions already stored inside the database 
+are left untouched. The module must be enabled inside the config file.
+You can specify a custom blocks range by using the %s and %s flags. 
+If the %s flag is set, the tables of the module are dropped and created again before reprocessing the blocks.
+As the module may build its state on the previous heights, the %s flag must then be set to a height not greater 
+than the first one stored inside the database. The tables of the block module, which hold the blocks and 
+transactions, cannot be recreated.
+`, flagStart, flagEnd, flagRecreateTables, flagStart),
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			parseCtx, err := parsecmdtypes.GetParserContext(config.Cfg, parseConfig)
+			if err != nil {
+				return err
+			}
+
+			module, found := modules.Modules(parseCtx.Modules).FindByName(args[0])
+			if !found {
+				return fmt.Errorf("module %s is not enabled", args[0])
+			}
+
+			// Get the flag values
+			start, _ := cmd.Flags().GetUint64(flagStart)
+			end, _ := cmd.Flags().GetUint64(flagEnd)
+			recreateTables, _ := cmd.Flags().GetBool(flagRecreateTables)
+
+			// Get the start height, default to the config's height; use flagStart if set
+			startHeight := config.Cfg.Parser.StartHeight
+			if start > 0 {
+				startHeight = start
+			}
+
+			// Get the end height, default to the node latest height; use flagEnd if set
+			latestHeight, err := parseCtx.Node.LatestHeight()
+			if err != nil {
+				return fmt.Errorf("error while getting chain latest block height: %s", err)
+			}
+
+			endHeight := uint64(latestHeight)
+			if end > 0 {
+				endHeight = end
+			}
+
+			if recreateTables {
+				tablesModule, ok := module.(modules.PrepareTablesModule)
+				if !ok {
+					return fmt.Errorf("module %s does not have any table", module.Name())
+				}
+				if _, ok := module.(*block.Module); ok {
+					return fmt.Errorf("the tables of module %s cannot be recreated, as they are not reprocessed", module.Name())
+				}
+
+				if start == 0 {
+					return fmt.Errorf("make sure the %s flag is set when recreating the tables", flagStart)
+				}
+				firstHeight, err := parseCtx.Database.GetFirstBlockHeight(context.Background())
+				if err != nil {
+					return fmt.Errorf("error while getting the first stored block height: %s", err)
+				}
+				if firstHeight > 0 && start > firstHeight {
+					return fmt.Errorf("make sure the %s flag is not greater than the first stored height %d when recreating "+
+						"the tables, so that no state of module %s is lost", flagStart, firstHeight, module.Name())
+				}
+
+				log.Infow("recreating module tables", "module", module.Name())
+				err = tablesModule.RecreateTables()
+				if err != nil {
+					return fmt.Errorf("error while recreating tables of module %s: %s", module.Name(), err)
+				}
+			}
+
+			indexer := parser.DefaultIndexer(parseCtx.EncodingConfig.Marshaler, parseCtx.Node, parseCtx.Database, []modules.Module{module})
+
+			log.Infow("reprocessing module", "module", module.Name(), "start height", startHeight, "end height", endHeight)
+			for k := startHeight; k <= endHeight; k++ {
+				data, err := indexer.Fetch(k)
+				if err != nil {
+					return fmt.Errorf("error while fetching block %d: %s", k, err)
+				}
+
+				err = indexer.Reprocess(data)
+				if err != nil {
+					return fmt.Errorf("error while reprocessing block %d: %s", k, err)
+				}
+			}
+
+			return nil
+		},
+	}
+
+	cmd.Flags().Uint64(flagStart, 0, "Height from which to start reprocessing the module. If 0, the start height inside the config file will be used instead")
+	cmd.Flags().Uint64(flagEnd, 0, "Height at which to finish reprocessing the module. If 0, the latest height available inside the node will be used instead")
+	cmd.Flags().Bool(flagRecreateTables, false, "Whether to drop and create again the tables of the module before reprocessing it (default false)")
+
+	return cmd
+}
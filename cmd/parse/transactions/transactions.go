@@ -30,8 +30,8 @@ You can specify a custom height range by using the %s and %s flags.
 				return err
 			}
 
-			workerCtx := parser.NewContext(parseCtx.EncodingConfig, parseCtx.Node, parseCtx.Database, parseCtx.Modules, nil)
-			worker := parser.NewWorker(workerCtx, nil, 0, false)
+			workerCtx := parser.NewContext(parseCtx.EncodingConfig, parseCtx.Node, parseCtx.Database, parseCtx.Sink, parseCtx.Modules, nil)
+			worker := parser.NewWorker(workerCtx, 0)
 
 			// Get the flag values
 			start, _ := cmd.Flags().GetUint64(flagStart)
@@ -8,9 +8,11 @@ import (
 
 	"github.com/forbole/juno/v4/database"
 	"github.com/forbole/juno/v4/log"
+	"github.com/forbole/juno/v4/modules"
 	modsregistrar "github.com/forbole/juno/v4/modules/registrar"
 	nodebuilder "github.com/forbole/juno/v4/node/builder"
 	"github.com/forbole/juno/v4/parser"
+	"github.com/forbole/juno/v4/sink"
 	"github.com/forbole/juno/v4/types/config"
 )
 
@@ -33,6 +35,22 @@ func GetParserContext(cfg config.Config, parseConfig *Config) (*parser.Context,
 		return nil, err
 	}
 
+	// Build the sink the modules emit their changes to
+	bz, err := cfg.GetBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	sinkCfg, err := sink.ParseConfig(bz)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse sink config: %s", err)
+	}
+
+	moduleSink, err := sink.Build(sinkCfg, db)
+	if err != nil {
+		return nil, fmt.Errorf("failed to build sink: %s", err)
+	}
+
 	// Init the client
 	cp, err := nodebuilder.BuildNode(cfg.Node, &encodingConfig)
 	if err != nil {
@@ -44,11 +62,21 @@ func GetParserContext(cfg config.Config, parseConfig *Config) (*parser.Context,
 	log.Init(lvl, log.StandardizePath(cfg.Logging.RootDir, cfg.Logging.ServiceName))
 
 	// Get the modules
-	context := modsregistrar.NewContext(cfg, sdkConfig, &encodingConfig, db, cp)
+	context := modsregistrar.NewContext(cfg, sdkConfig, &encodingConfig, db, moduleSink, cp)
 	mods := parseConfig.GetRegistrar().BuildModules(context)
 	registeredModules := modsregistrar.GetModules(mods, cfg.Chain.Modules)
 
-	return parser.NewContext(&encodingConfig, cp, db, registeredModules, nil), nil
+	// The modules reading back the records they emit can't work without them being stored inside the database
+	if !sinkCfg.HasType(sink.TypeSQL) {
+		for _, module := range registeredModules {
+			if module, ok := module.(modules.ReadBackModule); ok && module.ReadsBack() {
+				return nil, fmt.Errorf("module %s reads back the records it emits, so it requires the %s sink",
+					module.(modules.Module).Name(), sink.TypeSQL)
+			}
+		}
+	}
+
+	return parser.NewContext(&encodingConfig, cp, db, moduleSink, registeredModules, nil), nil
 }
 
 // getConfig returns the SDK Config instance as well as if it's sealed or not
@@ -0,0 +1,60 @@
+package usage
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/spf13/cobra"
+	"gorm.io/gorm/schema"
+
+	parsecmdtypes "github.com/forbole/juno/v4/cmd/parse/types"
+	"github.com/forbole/juno/v4/database"
+	"github.com/forbole/juno/v4/log"
+	"github.com/forbole/juno/v4/models"
+	"github.com/forbole/juno/v4/types/config"
+)
+
+// NewUsageCmd returns the Cobra command that allows to rebuild the storage usage aggregates
+func NewUsageCmd(parseConfig *parsecmdtypes.Config) *cobra.Command {
+	return &cobra.Command{
+		Use:   "usage",
+		Short: "Rebuild the storage usage of buckets, owners and primary storage providers from the stored objects",
+		Long: `Compute again the number and size of the sealed, pending and removed objects of each bucket, owner and 
+primary storage provider from the objects table, replacing the stored aggregates within a single database transaction.
+Make sure the parser is stopped before running this command.
+`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			parseCtx, err := parsecmdtypes.GetParserContext(config.Cfg, parseConfig)
+			if err != nil {
+				return err
+			}
+
+			err = parseCtx.Database.PrepareTables(context.Background(), []schema.Tabler{
+				&models.BucketUsage{}, &models.OwnerUsage{}, &models.SpUsage{},
+			})
+			if err != nil {
+				return fmt.Errorf("error while preparing usage tables: %s", err)
+			}
+
+			dbTx := parseCtx.Database.Begin(context.Background())
+			if dbTx.Db.Error != nil {
+				return fmt.Errorf("failed to begin database transaction: %s", dbTx.Db.Error)
+			}
+			ctx := database.WithTx(context.Background(), dbTx)
+
+			err = parseCtx.Database.RebuildStorageUsage(ctx)
+			if err != nil {
+				dbTx.Rollback()
+				return fmt.Errorf("error while rebuilding storage usage: %s", err)
+			}
+
+			err = dbTx.Commit()
+			if err != nil {
+				return fmt.Errorf("error while committing storage usage: %s", err)
+			}
+
+			log.Infow("rebuilt storage usage")
+			return nil
+		},
+	}
+}
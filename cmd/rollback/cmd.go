@@ -0,0 +1,156 @@
+package rollback
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	parsecmdtypes "github.com/forbole/juno/v4/cmd/parse/types"
+	"github.com/forbole/juno/v4/common"
+	"github.com/forbole/juno/v4/database"
+	"github.com/forbole/juno/v4/log"
+	"github.com/forbole/juno/v4/modules"
+	"github.com/forbole/juno/v4/sink"
+	"github.com/forbole/juno/v4/types/config"
+)
+
+const (
+	flagToHeight       = "to-height"
+	flagRecreateTables = "recreate-tables"
+)
+
+// NewRollbackCmd returns the Cobra command that allows to roll the database back to a given height
+func NewRollbackCmd(parseConfig *parsecmdtypes.Config) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "rollback",
+		Short: "Remove all the data indexed after the given height",
+		Long: fmt.Sprintf(`Remove the blocks, transactions and module data indexed after the height specified using the %s flag.
+This should be used after a chain fork has been detected, using the last common height reported by the parser.
+Make sure the parser is stopped before running this command.
+
+The rows updated in place after the height are restored when the module keeps their history, otherwise the rollback
+is refused. If the %s flag is set, the tables of those modules are dropped and created again instead: the modules
+supporting the fast sync download their state at the height, while the others must be reprocessed using the
+parse modules command.
+
+A rollback record carrying the height is emitted to the sinks for each module rolled back, so that their consumers
+can remove the changes they received after it.
+`, flagToHeight, flagRecreateTables),
+		PreRunE: parsecmdtypes.ReadConfigPreRunE(parseConfig),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			toHeight, _ := cmd.Flags().GetUint64(flagToHeight)
+			recreateTables, _ := cmd.Flags().GetBool(flagRecreateTables)
+			if toHeight == 0 {
+				return fmt.Errorf("make sure the %s flag is set to a positive height", flagToHeight)
+			}
+
+			parseCtx, err := parsecmdtypes.GetParserContext(config.Cfg, parseConfig)
+			if err != nil {
+				return err
+			}
+
+			dbTx := parseCtx.Database.Begin(context.Background())
+			if dbTx.Db.Error != nil {
+				return fmt.Errorf("failed to begin database transaction: %s", dbTx.Db.Error)
+			}
+			buffer := sink.NewBuffer()
+			ctx := sink.WithBuffer(database.WithTx(context.Background(), dbTx), buffer)
+
+			var stale []modules.Module
+			for _, module := range parseCtx.Modules {
+				if rollbackModule, ok := module.(modules.RollbackModule); ok {
+					err = rollbackModule.Rollback(ctx, toHeight)
+
+					var staleErr *modules.StaleRowsError
+					if errors.As(err, &staleErr) && recreateTables {
+						stale = append(stale, module)
+						continue
+					}
+					if staleErr != nil {
+						dbTx.Rollback()
+						return fmt.Errorf("error while rolling back module %s: %s, use the %s flag to recreate its tables",
+							module.Name(), err, flagRecreateTables)
+					}
+					if err != nil {
+						dbTx.Rollback()
+						return fmt.Errorf("error while rolling back module %s: %s", module.Name(), err)
+					}
+				}
+			}
+
+			// the records are delivered to the sinks not backed by the database once the rollback is committed
+			for _, module := range parseCtx.Modules {
+				if _, ok := module.(modules.RollbackModule); !ok {
+					continue
+				}
+
+				err = parseCtx.Sink.Emit(ctx, sink.NewRecord(module.Name(), int64(toHeight), common.Hash{},
+					sink.OperationRollback, &sink.Rollback{}))
+				if err != nil {
+					dbTx.Rollback()
+					return fmt.Errorf("error while emitting the rollback of module %s: %s", module.Name(), err)
+				}
+			}
+
+			err = parseCtx.Database.DeleteBlocksAfter(ctx, toHeight)
+			if err != nil {
+				dbTx.Rollback()
+				return fmt.Errorf("error while deleting blocks: %s", err)
+			}
+
+			err = dbTx.Commit()
+			if err != nil {
+				return fmt.Errorf("error while committing rollback: %s", err)
+			}
+
+			log.Infow("rolled back database", "height", toHeight)
+
+			err = buffer.Flush(context.Background())
+			if err != nil {
+				return fmt.Errorf("error while emitting the rollback records: %s", err)
+			}
+
+			for _, module := range stale {
+				err = recreateModuleTables(module, toHeight)
+				if err != nil {
+					return fmt.Errorf("error while recreating the tables of module %s: %s", module.Name(), err)
+				}
+			}
+			return nil
+		},
+	}
+
+	cmd.Flags().Uint64(flagToHeight, 0, "Last height to keep inside the database")
+	cmd.Flags().Bool(flagRecreateTables, false, "Recreate the tables of the modules whose rows cannot be restored")
+
+	return cmd
+}
+
+// recreateModuleTables drops and creates again the tables of the given module, whose rows updated after the given
+// height cannot be restored, downloading its state at that height when the module supports the fast sync
+func recreateModuleTables(module modules.Module, height uint64) error {
+	tablesModule, ok := module.(modules.PrepareTablesModule)
+	if !ok {
+		return fmt.Errorf("the module does not manage its tables")
+	}
+
+	err := tablesModule.RecreateTables()
+	if err != nil {
+		return err
+	}
+
+	if fastSyncModule, ok := module.(modules.FastSyncModule); ok {
+		err = fastSyncModule.DownloadState(int64(height))
+		if err != nil {
+			return err
+		}
+		log.Infow("recreated module tables", "module", module.Name(), "height", height)
+		return nil
+	}
+
+	log.Warnw("recreated module tables, reprocess the module using the parse modules command",
+		"module", module.Name(), "height", height)
+	return nil
+}
@@ -0,0 +1,48 @@
+package serve
+
+import (
+	"github.com/spf13/cobra"
+
+	"github.com/forbole/juno/v4/api"
+	parsecmdtypes "github.com/forbole/juno/v4/cmd/parse/types"
+	"github.com/forbole/juno/v4/database"
+	"github.com/forbole/juno/v4/log"
+	"github.com/forbole/juno/v4/types/config"
+)
+
+// NewServeCmd returns the command that should be run to start the read-only query API server
+func NewServeCmd(cmdCfg *parsecmdtypes.Config) *cobra.Command {
+	return &cobra.Command{
+		Use:     "serve",
+		Short:   "Start the read-only HTTP/JSON API serving the indexed data",
+		PreRunE: parsecmdtypes.ReadConfigPreRunE(cmdCfg),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cfg := config.Cfg
+
+			bz, err := cfg.GetBytes()
+			if err != nil {
+				return err
+			}
+
+			apiCfg, err := api.ParseConfig(bz)
+			if err != nil {
+				return err
+			}
+
+			// Setup the logging
+			lvl, _ := log.ParseLevel(cfg.Logging.Level)
+			log.Init(lvl, log.StandardizePath(cfg.Logging.RootDir, cfg.Logging.ServiceName))
+
+			// Get the db
+			encodingConfig := cmdCfg.GetEncodingConfigBuilder()()
+			db, err := cmdCfg.GetDBBuilder()(database.NewContext(cfg.Database, &encodingConfig))
+			if err != nil {
+				return err
+			}
+			defer db.Close()
+
+			log.Infow("starting API server", "port", apiCfg.Port)
+			return api.NewServer(apiCfg, db).Start()
+		},
+	}
+}
@@ -81,13 +81,16 @@ func Parsing(ctx *parser.Context) error {
 	// Create a queue that will collect, aggregate, and export blocks and metadata
 	exportQueue := types.NewQueue(25)
 
-	// Create workers
-	workers := make([]*parser.Worker, cfg.Workers)
-	for i := range workers {
-		workers[i] = parser.NewWorker(ctx, exportQueue, i, cfg.ConcurrentSync)
+	// Create the workers. A single pipeline is used, so that blocks are fetched concurrently by the configured
+	// number of workers but still applied in height order, even when the missing and new blocks are enqueued
+	// concurrently
+	var workers []func()
+	if cfg.Workers > 0 {
+		pipeline := parser.NewPipeline(ctx, exportQueue, int(cfg.Workers), getStartHeight(ctx))
 		if ctx.Indexer != nil {
-			workers[i].SetIndexer(ctx.Indexer)
+			pipeline.SetIndexer(ctx.Indexer)
 		}
+		workers = append(workers, pipeline.Start)
 	}
 
 	waitGroup.Add(1)
@@ -101,9 +104,9 @@ func Parsing(ctx *parser.Context) error {
 
 	// Start each blocking worker in a go-routine where the worker consumes jobs
 	// off of the export queue.
-	for i, w := range workers {
+	for i, start := range workers {
 		log.Debugw("starting worker...", "number", i+1)
-		go w.Start()
+		go start()
 	}
 
 	// Listen for and trap any OS signal to gracefully shutdown and exit
@@ -126,6 +129,25 @@ func Parsing(ctx *parser.Context) error {
 	return nil
 }
 
+// getStartHeight returns the first height to be enqueued, from which the heights are applied in order
+func getStartHeight(ctx *parser.Context) uint64 {
+	cfg := config.Cfg.Parser
+
+	lastDbBlockHeight, err := ctx.Database.GetLastBlockHeight(context.TODO())
+	if err != nil {
+		log.Errorw("failed to get last block height from database", "error", err)
+	}
+
+	// the missing blocks are enqueued starting from the configured height, or the latest one inside the database
+	if cfg.ParseOldBlocks && !cfg.FastSync {
+		if cfg.StartHeight != 0 {
+			return cfg.StartHeight
+		}
+		return lastDbBlockHeight
+	}
+	return lastDbBlockHeight + 1
+}
+
 // enqueueMissingBlocks enqueues jobs (block heights) for missed blocks starting
 // at the startHeight up until the latest known height.
 func enqueueMissingBlocks(exportQueue types.HeightQueue, ctx *parser.Context) {
@@ -165,9 +187,17 @@ func enqueueMissingBlocks(exportQueue types.HeightQueue, ctx *parser.Context) {
 		}
 	} else {
 		log.Infow("syncing missing blocks...", "latest_block_height", latestBlockHeight)
-		for _, i := range ctx.Database.GetMissingHeights(context.TODO(), startHeight, latestBlockHeight) {
-			log.Debugw("enqueueing missing block", "height", i)
-			exportQueue <- i
+		missing, err := ctx.Database.GetMissingHeights(context.TODO(), startHeight, latestBlockHeight)
+		if err != nil {
+			log.Errorw("failed to get missing heights from database", "error", err)
+			return
+		}
+
+		for _, gap := range missing {
+			for i := gap.StartHeight; i <= gap.EndHeight; i++ {
+				log.Debugw("enqueueing missing block", "height", i)
+				exportQueue <- i
+			}
 		}
 	}
 }
@@ -4,6 +4,7 @@ import (
 	"context"
 	"database/sql"
 	"encoding/base64"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"strings"
@@ -28,6 +29,9 @@ type Database interface {
 	// RecreateTables recreate tables when given table exists
 	RecreateTables(ctx context.Context, tables []schema.Tabler) error
 
+	// HasColumn tells whether the given table exists and has the given column
+	HasColumn(ctx context.Context, table schema.Tabler, column string) bool
+
 	// HasBlock tells whether the database has already stored the block having the given height.
 	// An error is returned if the operation fails.
 	HasBlock(ctx context.Context, height uint64) (bool, error)
@@ -36,8 +40,20 @@ type Database interface {
 	// An error is returned if the operation fails.
 	GetLastBlockHeight(ctx context.Context) (uint64, error)
 
-	// GetMissingHeights returns a slice of missing block heights between startHeight and endHeight
-	GetMissingHeights(ctx context.Context, startHeight, endHeight uint64) []uint64
+	// GetFirstBlockHeight returns the first block height stored in database, or 0 if no block has been stored yet.
+	// An error is returned if the operation fails.
+	GetFirstBlockHeight(ctx context.Context) (uint64, error)
+
+	// GetMissingHeights returns the ranges of missing block heights between startHeight and endHeight, sorted by height.
+	// Missing heights are computed from the sync progress, without scanning the stored blocks, so that the
+	// result size only depends on the number of gaps.
+	// An error is returned if the operation fails.
+	GetMissingHeights(ctx context.Context, startHeight, endHeight uint64) ([]*models.SyncGap, error)
+
+	// UpdateSyncProgress marks the given height as processed inside the sync progress.
+	// It must be called within the same transaction that saved the block having the given height.
+	// An error is returned if the operation fails.
+	UpdateSyncProgress(ctx context.Context, height uint64) error
 
 	// SaveBlock will be called when a new block is parsed, passing the block itself
 	// and the transactions contained inside that block.
@@ -48,6 +64,64 @@ type Database interface {
 	// GetTotalBlocks returns total number of blocks stored in database.
 	GetTotalBlocks(ctx context.Context) int64
 
+	// GetBlock returns the block stored at the given height, or nil if the height has not been stored yet.
+	// An error is returned if the operation fails.
+	GetBlock(ctx context.Context, height uint64) (*models.Block, error)
+
+	// DeleteBlocksAfter deletes all the blocks, transactions, commit signatures and quarantined events having a height
+	// greater than the given one, removing them from the sync progress as well.
+	// An error is returned if the operation fails.
+	DeleteBlocksAfter(ctx context.Context, height uint64) error
+
+	// DeleteAfter deletes all the rows of the given table having the given column greater than value,
+	// returning the number of deleted rows.
+	// An error is returned if the operation fails.
+	DeleteAfter(ctx context.Context, table schema.Tabler, column string, value int64) (int64, error)
+
+	// DeleteAt deletes all the rows of the given table having the given column equal to value,
+	// returning the number of deleted rows.
+	// An error is returned if the operation fails.
+	DeleteAt(ctx context.Context, table schema.Tabler, column string, value int64) (int64, error)
+
+	// CountAfter returns the number of rows of the given table having the given column greater than value.
+	// An error is returned if the operation fails.
+	CountAfter(ctx context.Context, table schema.Tabler, column string, value int64) (int64, error)
+
+	// ListAfter stores inside dest all the rows of the given table having the given column greater than value.
+	// An error is returned if the operation fails.
+	ListAfter(ctx context.Context, table schema.Tabler, column string, value int64, dest interface{}) error
+
+	// SaveFailedHeight stores the given height as failed, replacing any previous record of it.
+	// An error is returned if the operation fails.
+	SaveFailedHeight(ctx context.Context, failedHeight *models.FailedHeight) error
+
+	// GetFailedHeights returns all the heights stored as failed, sorted by height.
+	GetFailedHeights(ctx context.Context) ([]*models.FailedHeight, error)
+
+	// GetFailedHeight returns the failed height record of the given height.
+	// If the height is not stored as failed, nil is returned instead.
+	GetFailedHeight(ctx context.Context, height uint64) (*models.FailedHeight, error)
+
+	// DeleteFailedHeight removes the given height from the failed ones.
+	// An error is returned if the operation fails.
+	DeleteFailedHeight(ctx context.Context, height uint64) error
+
+	// SaveQuarantinedEvent stores the given quarantined event, updating it if it already exists.
+	// An error is returned if the operation fails.
+	SaveQuarantinedEvent(ctx context.Context, event *models.QuarantinedEvent) error
+
+	// GetQuarantinedEvents returns the quarantined events of the given module, or of all the modules if module is empty,
+	// sorted by id.
+	GetQuarantinedEvents(ctx context.Context, module string) ([]*models.QuarantinedEvent, error)
+
+	// GetQuarantinedEvent returns the quarantined event having the given id.
+	// If the event does not exist, nil is returned instead.
+	GetQuarantinedEvent(ctx context.Context, id uint64) (*models.QuarantinedEvent, error)
+
+	// DeleteQuarantinedEvent removes the quarantined event having the given id.
+	// An error is returned if the operation fails.
+	DeleteQuarantinedEvent(ctx context.Context, id uint64) error
+
 	// SaveTx will be called to save each transaction contained inside a block.
 	// An error is returned if the operation fails.
 	SaveTx(ctx context.Context, blockTimestamp uint64, index int, tx *types.Tx) error
@@ -60,9 +134,17 @@ type Database interface {
 	// An error is returned if the operation fails.
 	SaveValidators(ctx context.Context, validators []*models.Validator) error
 
-	// SaveCommitSignatures stores a  slice of validator commit signatures.
+	// SaveCommitSignatures stores a  slice of validator commit signatures, skipping the ones already stored.
+	// An error is returned if the operation fails.
+	SaveCommitSignatures(ctx context.Context, signatures []*models.PreCommit) error
+
+	// GetValidatorUptimes returns the uptimes of the validators with the given consensus addresses.
+	// Validators without any recorded uptime are omitted.
+	GetValidatorUptimes(ctx context.Context, validatorAddresses []common.Address) ([]*models.ValidatorUptime, error)
+
+	// SaveValidatorUptimes stores the given uptimes, replacing the ones of the same validators.
 	// An error is returned if the operation fails.
-	SaveCommitSignatures(ctx context.Context, signatures []*types.CommitSig) error
+	SaveValidatorUptimes(ctx context.Context, uptimes []*models.ValidatorUptime) error
 
 	// SaveBucket will be called to save each bucket contained inside a block.
 	// An error is returned if the operation fails.
@@ -72,6 +154,10 @@ type Database interface {
 	// An error is returned if the operation fails.
 	UpdateBucket(ctx context.Context, bucket *models.Bucket) error
 
+	// SaveBucketQuotaChange appends the given change to the charged read quota history of its bucket.
+	// An error is returned if the operation fails.
+	SaveBucketQuotaChange(ctx context.Context, change *models.BucketQuotaChange) error
+
 	// SaveObject will be called to save each object contained inside a block.
 	// An error is returned if the operation fails.
 	SaveObject(ctx context.Context, object *models.Object) error
@@ -84,14 +170,136 @@ type Database interface {
 	// It should return only one record
 	GetObject(ctx context.Context, objectId common.Hash) (*models.Object, error)
 
+	// GetBucketByID returns the bucket with the given id, including removed ones.
+	// If the bucket does not exist, nil is returned instead.
+	GetBucketByID(ctx context.Context, bucketID common.Hash) (*models.Bucket, error)
+
+	// GetObjectByID returns the object with the given id, including removed ones.
+	// If the object does not exist, nil is returned instead.
+	GetObjectByID(ctx context.Context, objectID common.Hash) (*models.Object, error)
+
+	// GetGroupMembers returns all the members of the group with the given id, including removed ones.
+	GetGroupMembers(ctx context.Context, groupID common.Hash) ([]*models.GroupMember, error)
+
+	// SaveBucketVersion appends the given version to the bucket history.
+	// An error is returned if the operation fails.
+	SaveBucketVersion(ctx context.Context, version *models.BucketVersion) error
+
+	// SaveObjectVersion appends the given version to the object history.
+	// An error is returned if the operation fails.
+	SaveObjectVersion(ctx context.Context, version *models.ObjectVersion) error
+
+	// SaveGroupVersions appends the given versions to the group history.
+	// An error is returned if the operation fails.
+	SaveGroupVersions(ctx context.Context, versions []*models.GroupVersion) error
+
+	// GetBucketAtHeight returns the bucket with the given id as it was at the given height,
+	// based on the bucket history. If the bucket did not exist at that height, nil is returned instead.
+	GetBucketAtHeight(ctx context.Context, bucketID common.Hash, height uint64) (*models.Bucket, error)
+
+	// GetObjectAtHeight returns the object with the given id as it was at the given height,
+	// based on the object history. If the object did not exist at that height, nil is returned instead.
+	GetObjectAtHeight(ctx context.Context, objectID common.Hash, height uint64) (*models.Object, error)
+
+	// GetGroupMemberAtHeight returns the given member of the group with the given id as it was at the given height,
+	// based on the group history. If the member did not exist at that height, nil is returned instead.
+	GetGroupMemberAtHeight(ctx context.Context, groupID common.Hash, member common.Address, height uint64) (*models.GroupMember, error)
+
 	SaveEpoch(ctx context.Context, epoch *models.Epoch) error
 
 	GetEpoch(ctx context.Context) (*models.Epoch, error)
 
+	// GetPaymentAccount returns the payment account with the given address.
+	// If no payment account is found, nil is returned instead.
+	GetPaymentAccount(ctx context.Context, addr common.Address) (*models.PaymentAccount, error)
+
+	// SavePaymentTransfer appends the given entry to the payment ledger.
+	// An error is returned if the operation fails.
+	SavePaymentTransfer(ctx context.Context, transfer *models.PaymentTransfer) error
+
+	// ListPaymentTransfers returns the ledger entries of the given account having an id greater than startAfter,
+	// sorted by id, returning at most limit entries.
+	ListPaymentTransfers(ctx context.Context, account common.Address, startAfter uint64, limit int) ([]*models.PaymentTransfer, error)
+
 	// SavePaymentAccount will be called to save PaymentAccount.
 	// An error is returned if the operation fails.
 	SavePaymentAccount(ctx context.Context, paymentAccount *models.PaymentAccount) error
 
+	// AddBucketUsage adds the counts of the given usage to the ones of its bucket.
+	// An error is returned if the operation fails.
+	AddBucketUsage(ctx context.Context, usage *models.BucketUsage) error
+
+	// AddOwnerUsage adds the counts of the given usage to the ones of its owner.
+	// An error is returned if the operation fails.
+	AddOwnerUsage(ctx context.Context, usage *models.OwnerUsage) error
+
+	// AddSpUsage adds the counts of the given usage to the ones of its primary storage provider.
+	// An error is returned if the operation fails.
+	AddSpUsage(ctx context.Context, usage *models.SpUsage) error
+
+	// GetBucketUsage returns the storage usage of the bucket with the given id.
+	// If the bucket does not contain any object, an empty usage is returned.
+	GetBucketUsage(ctx context.Context, bucketID common.Hash) (*models.BucketUsage, error)
+
+	// GetOwnerUsage returns the storage usage of the objects owned by the given address.
+	// If the address does not own any object, an empty usage is returned.
+	GetOwnerUsage(ctx context.Context, owner common.Address) (*models.OwnerUsage, error)
+
+	// GetSpUsage returns the storage usage of the objects stored by the given primary storage provider.
+	// If the storage provider does not store any object, an empty usage is returned.
+	GetSpUsage(ctx context.Context, spAddress common.Address) (*models.SpUsage, error)
+
+	// RebuildStorageUsage computes again all the storage usages from the objects table, replacing the stored ones.
+	// An error is returned if the operation fails.
+	RebuildStorageUsage(ctx context.Context) error
+
+	// SaveStorageProvider will be called to save StorageProvider, replacing any previous record of it.
+	// An error is returned if the operation fails.
+	SaveStorageProvider(ctx context.Context, sp *models.StorageProvider) error
+
+	// UpdateStorageProvider updates the non-zero fields of the storage provider having the same operator address.
+	// An error is returned if the operation fails.
+	UpdateStorageProvider(ctx context.Context, sp *models.StorageProvider) error
+
+	// GetStorageProviderByEndpoint returns the storage provider serving the given endpoint.
+	// If the storage provider does not exist, nil is returned instead.
+	GetStorageProviderByEndpoint(ctx context.Context, endpoint string) (*models.StorageProvider, error)
+
+	// GetStorageProviderByFundingAddress returns the storage provider having the given funding address.
+	// If the storage provider does not exist, nil is returned instead.
+	GetStorageProviderByFundingAddress(ctx context.Context, address common.Address) (*models.StorageProvider, error)
+
+	// SaveStoragePrice stores the given storage price update.
+	// An error is returned if the operation fails.
+	SaveStoragePrice(ctx context.Context, price *models.StoragePrice) error
+
+	// SaveStreamRecordHistory stores the state of a stream record at a height, replacing any previous state
+	// stored for the same account and height.
+	// An error is returned if the operation fails.
+	SaveStreamRecordHistory(ctx context.Context, history *models.StreamRecordHistory) error
+
+	// GetStreamRecordAtHeight returns the state of the stream record of the given account at the given height.
+	// If the stream record did not exist at that height, nil is returned instead.
+	GetStreamRecordAtHeight(ctx context.Context, account common.Address, height int64) (*models.StreamRecordHistory, error)
+
+	// ListStreamRecordHistory returns the states of the stream record of the given account between the given heights,
+	// both included, sorted by height.
+	ListStreamRecordHistory(ctx context.Context, account common.Address, fromHeight, toHeight int64) ([]*models.StreamRecordHistory, error)
+
+	// SaveStreamOutflows stores the given outflows.
+	// An error is returned if the operation fails.
+	SaveStreamOutflows(ctx context.Context, outflows []*models.StreamOutflow) error
+
+	// DeleteStreamOutflows removes all the outflows of the given account.
+	// An error is returned if the operation fails.
+	DeleteStreamOutflows(ctx context.Context, from common.Address) error
+
+	// GetStreamOutflows returns the outflows of the given account, sorted by receiver.
+	GetStreamOutflows(ctx context.Context, from common.Address) ([]*models.StreamOutflow, error)
+
+	// GetStreamInflows returns the outflows of all the accounts towards the given one, sorted by sender.
+	GetStreamInflows(ctx context.Context, to common.Address) ([]*models.StreamOutflow, error)
+
 	// SaveStreamRecord will be called to save SaveStreamRecord.
 	// An error is returned if the operation fails.
 	SaveStreamRecord(ctx context.Context, streamRecord *models.StreamRecord) error
@@ -104,17 +312,88 @@ type Database interface {
 	// An error is returned if the operation fails.
 	UpdatePermission(ctx context.Context, permission *models.Permission) error
 
-	// CreateGroup will be called to save each group contained inside an event.
+	// SaveGroup creates the given group, replacing it if it already exists.
 	// An error is returned if the operation fails.
-	CreateGroup(ctx context.Context, groupMembers []*models.Group) error
+	SaveGroup(ctx context.Context, group *models.Group) error
 
-	// UpdateGroup will be called to update each group
+	// UpdateGroup updates the non-zero fields of the given group.
 	// An error is returned if the operation fails.
 	UpdateGroup(ctx context.Context, group *models.Group) error
 
-	// DeleteGroup will be called to delete each group
+	// SaveGroupMembers creates the given group members, replacing the ones that already exist.
+	// An error is returned if the operation fails.
+	SaveGroupMembers(ctx context.Context, members []*models.GroupMember) error
+
+	// UpdateGroupMember updates the non-zero fields of the given group member.
+	// An error is returned if the operation fails.
+	UpdateGroupMember(ctx context.Context, member *models.GroupMember) error
+
+	// SaveGroupMemberChanges appends the given entries to the membership change log.
+	// An error is returned if the operation fails.
+	SaveGroupMemberChanges(ctx context.Context, changes []*models.GroupMemberChange) error
+
+	// SaveProposal stores the given proposal. If the proposal already exists, only its state is replaced,
+	// keeping its proposer, messages, metadata and create fields.
+	// An error is returned if the operation fails.
+	SaveProposal(ctx context.Context, proposal *models.Proposal) error
+
+	// SaveProposalDeposit stores the given proposal deposit.
+	// An error is returned if the operation fails.
+	SaveProposalDeposit(ctx context.Context, deposit *models.ProposalDeposit) error
+
+	// SaveProposalVote stores the given proposal vote, replacing any previous vote of the same voter on the same proposal.
+	// An error is returned if the operation fails.
+	SaveProposalVote(ctx context.Context, vote *models.ProposalVote) error
+
+	// SaveCrossChainPackage stores the given cross-chain package, replacing any package with the same direction,
+	// chains, channel and sequence.
+	// An error is returned if the operation fails.
+	SaveCrossChainPackage(ctx context.Context, pkg *models.CrossChainPackage) error
+
+	// SaveMirror stores the given mirror request, replacing any previous request for the same resource.
+	// An error is returned if the operation fails.
+	SaveMirror(ctx context.Context, mirror *models.Mirror) error
+
+	// UpdateMirror updates the non-zero fields of the mirror request for the same resource.
 	// An error is returned if the operation fails.
-	DeleteGroup(ctx context.Context, group *models.Group) error
+	UpdateMirror(ctx context.Context, mirror *models.Mirror) error
+
+	// SaveValidatorInfo stores the given validator info, replacing the one of the same validator.
+	// An error is returned if the operation fails.
+	SaveValidatorInfo(ctx context.Context, info *models.ValidatorInfo) error
+
+	// SaveValidatorDescription stores the given validator description, replacing the one of the same validator.
+	// An error is returned if the operation fails.
+	SaveValidatorDescription(ctx context.Context, description *models.ValidatorDescription) error
+
+	// SaveValidatorCommission stores the given validator commission, replacing the one of the same validator.
+	// An error is returned if the operation fails.
+	SaveValidatorCommission(ctx context.Context, commission *models.ValidatorCommission) error
+
+	// SaveValidatorStatus stores the given validator status, replacing the one of the same validator.
+	// An error is returned if the operation fails.
+	SaveValidatorStatus(ctx context.Context, status *models.ValidatorStatus) error
+
+	// SaveValidatorSigningInfo stores the given validator signing info, replacing the one of the same validator.
+	// An error is returned if the operation fails.
+	SaveValidatorSigningInfo(ctx context.Context, info *models.ValidatorSigningInfo) error
+
+	// SaveValidatorVotingPowers stores the given voting powers, replacing the ones of the same validators.
+	// An error is returned if the operation fails.
+	SaveValidatorVotingPowers(ctx context.Context, powers []*models.ValidatorVotingPower) error
+
+	// SaveTransfers stores the given bank transfers.
+	// An error is returned if the operation fails.
+	SaveTransfers(ctx context.Context, transfers []*models.Transfer) error
+
+	// SaveAccountBalances stores the given balance snapshots, replacing the ones of the same account, denom and height.
+	// An error is returned if the operation fails.
+	SaveAccountBalances(ctx context.Context, balances []*models.AccountBalance) error
+
+	// DeletePoliciesCreatedAfter deletes the policies created after the given timestamp along with their
+	// statements, returning the number of deleted policies.
+	// An error is returned if the operation fails.
+	DeletePoliciesCreatedAfter(ctx context.Context, timestamp int64) (int64, error)
 
 	// MultiSaveStatement will be called to save each statement contained inside a policy.
 	// An error is returned if the operation fails.
@@ -122,12 +401,133 @@ type Database interface {
 
 	RemoveStatements(ctx context.Context, policyID common.Hash) error
 
-	// Begin begins a transaction with any transaction options opts
+	// GetBucketByName returns the bucket with the given name, excluding removed ones.
+	// If the bucket does not exist, nil is returned instead.
+	GetBucketByName(ctx context.Context, bucketName string) (*models.Bucket, error)
+
+	// ListObjectsByBucketName returns at most limit objects of the bucket with the given name, excluding removed ones.
+	// Objects are sorted by id, and only the ones having an id greater than startAfter are returned.
+	ListObjectsByBucketName(ctx context.Context, bucketName string, startAfter uint64, limit int) ([]*models.Object, error)
+
+	// ListObjectsByOwner returns at most limit objects owned by the given address, excluding removed ones.
+	// Objects are sorted by id, and only the ones having an id greater than startAfter are returned.
+	ListObjectsByOwner(ctx context.Context, owner common.Address, startAfter uint64, limit int) ([]*models.Object, error)
+
+	// ListPoliciesByResource returns the policies attached to the given resource, excluding removed ones.
+	ListPoliciesByResource(ctx context.Context, resourceType string, resourceID common.Hash) ([]*models.Permission, error)
+
+	// GetGroup returns the group with the given id, including removed ones.
+	// If the group does not exist, nil is returned instead.
+	GetGroup(ctx context.Context, groupID common.Hash) (*models.Group, error)
+
+	// GetGroupByName returns the group of the given owner having the given name, excluding removed ones.
+	// If the group does not exist, nil is returned instead.
+	GetGroupByName(ctx context.Context, owner common.Address, groupName string) (*models.Group, error)
+
+	// GetGroupMember returns the membership of the given account in the given group, including removed ones.
+	// If the account has never been a member of the group, nil is returned instead.
+	GetGroupMember(ctx context.Context, groupID common.Hash, member common.Address) (*models.GroupMember, error)
+
+	// ListGroupMembers returns at most limit members of the given group that are active at the given time.
+	// Members are sorted by id, and only the ones having an id greater than startAfter are returned.
+	ListGroupMembers(ctx context.Context, groupID common.Hash, time int64, startAfter uint64, limit int) ([]*models.GroupMember, error)
+
+	// ListGroupsByMember returns at most limit groups, excluding removed ones, of which the given account
+	// is a member active at the given time.
+	// Groups are sorted by id, and only the ones having an id greater than startAfter are returned.
+	ListGroupsByMember(ctx context.Context, member common.Address, time int64, startAfter uint64, limit int) ([]*models.Group, error)
+
+	// GetGroupMemberChanges returns the membership change log of the given group, sorted by id.
+	GetGroupMemberChanges(ctx context.Context, groupID common.Hash) ([]*models.GroupMemberChange, error)
+
+	// GetGroupMemberChangesByTx returns the entries of the membership change log written by the given transaction.
+	GetGroupMemberChangesByTx(ctx context.Context, txHash common.Hash) ([]*models.GroupMemberChange, error)
+
+	// GetProposal returns the proposal with the given id.
+	// If the proposal does not exist, nil is returned instead.
+	GetProposal(ctx context.Context, proposalID uint64) (*models.Proposal, error)
+
+	// GetProposalDeposits returns the deposits made to the given proposal, sorted by id.
+	GetProposalDeposits(ctx context.Context, proposalID uint64) ([]*models.ProposalDeposit, error)
+
+	// ListProposalVotes returns at most limit votes on the given proposal having an id greater than startAfter, sorted by id.
+	ListProposalVotes(ctx context.Context, proposalID uint64, startAfter uint64, limit int) ([]*models.ProposalVote, error)
+
+	// GetLastSentPackage returns the package with the highest sequence sent on the given channel by the given
+	// transaction. If the transaction did not send any package on the channel, nil is returned instead.
+	GetLastSentPackage(ctx context.Context, txHash common.Hash, channelID uint32) (*models.CrossChainPackage, error)
+
+	// GetSentPackage returns the package sent to the given chain on the given channel with the given sequence.
+	// If the package does not exist, nil is returned instead.
+	GetSentPackage(ctx context.Context, destChainID, channelID uint32, sequence uint64) (*models.CrossChainPackage, error)
+
+	// GetCrossChainPackagesByTx returns the packages sent or received by the given transaction, sorted by id.
+	GetCrossChainPackagesByTx(ctx context.Context, txHash common.Hash) ([]*models.CrossChainPackage, error)
+
+	// GetMirror returns the latest mirror request of the resource with the given type and id.
+	// If the resource was never mirrored, nil is returned instead.
+	GetMirror(ctx context.Context, resourceType string, resourceID common.Hash) (*models.Mirror, error)
+
+	// GetValidatorInfo returns the info of the validator with the given consensus address.
+	// If the validator is unknown, nil is returned instead.
+	GetValidatorInfo(ctx context.Context, validatorAddress common.Address) (*models.ValidatorInfo, error)
+
+	// GetActiveVotingPowers returns the voting powers of the validators having a positive voting power.
+	GetActiveVotingPowers(ctx context.Context) ([]*models.ValidatorVotingPower, error)
+
+	// GetLatestBalance returns the latest balance snapshot of the given account in the given denom at or before
+	// the given height. If the balance never changed, nil is returned instead.
+	GetLatestBalance(ctx context.Context, account common.Address, denom string, height int64) (*models.AccountBalance, error)
+
+	// GetAccountBalances returns the latest balance snapshots of the given account in each denom at or before
+	// the given height, sorted by denom.
+	GetAccountBalances(ctx context.Context, account common.Address, height int64) ([]*models.AccountBalance, error)
+
+	// ListTransfers returns at most limit bank transfers from or to the given account having an id greater than
+	// startAfter, sorted by id.
+	ListTransfers(ctx context.Context, account common.Address, startAfter uint64, limit int) ([]*models.Transfer, error)
+
+	// GetStatements returns the statements of the policies with the given ids, excluding removed ones.
+	GetStatements(ctx context.Context, policyIDs []common.Hash) ([]*models.Statements, error)
+
+	// GetTxByHash returns the transaction with the given hash.
+	// If the transaction does not exist, nil is returned instead.
+	GetTxByHash(ctx context.Context, hash common.Hash) (*models.Tx, error)
+
+	// GetTxsByHeight returns the transactions included inside the block at the given height, sorted by index.
+	GetTxsByHeight(ctx context.Context, height uint64) ([]*models.Tx, error)
+
+	// SaveAccountTxs stores the given involvements of addresses in messages, ignoring the ones already stored.
+	// An error is returned if the operation fails.
+	SaveAccountTxs(ctx context.Context, accountTxs []*models.AccountTx) error
+
+	// GetTxsByAddress returns at most limit transactions having a message involving the given address and an id
+	// greater than startAfter, sorted by id.
+	GetTxsByAddress(ctx context.Context, address common.Address, startAfter uint64, limit int) ([]*models.Tx, error)
+
+	// SaveMessages stores the given messages, replacing the ones of the same transaction and index.
+	// An error is returned if the operation fails.
+	SaveMessages(ctx context.Context, msgs []*models.Message) error
+
+	// ListMessagesByType returns at most limit messages of the given type included between the given heights,
+	// inclusive, having an id greater than startAfter, sorted by id.
+	ListMessagesByType(ctx context.Context, msgType string, fromHeight, toHeight int64, startAfter uint64, limit int) ([]*models.Message, error)
+
+	// Begin begins a transaction with any transaction options opts.
+	// Use WithTx to make the calls receiving the resulting context join the transaction.
 	Begin(ctx context.Context) *Impl
 
 	// Rollback rollbacks the changes in a transaction
 	Rollback()
 
+	// SavePoint sets a savepoint with the given name inside the transaction carried by ctx.
+	// An error is returned if the operation fails.
+	SavePoint(ctx context.Context, name string) error
+
+	// RollbackTo rollbacks the changes made after the given savepoint inside the transaction carried by ctx.
+	// An error is returned if the operation fails.
+	RollbackTo(ctx context.Context, name string) error
+
 	// Commit commits the changes in a transaction
 	// An error is returned if the operation fails.
 	Commit() error
@@ -136,16 +536,17 @@ type Database interface {
 	Close()
 }
 
-// PruningDb represents a database that supports pruning properly
+// PruningDb represents a database that supports pruning properly.
+// The methods use the database transaction carried by ctx, if any.
 type PruningDb interface {
 	// Prune prunes the data for the given height, returning any error
-	Prune(height int64) error
+	Prune(ctx context.Context, height int64) error
 
 	// StoreLastPruned saves the last height at which the database was pruned
-	StoreLastPruned(height int64) error
+	StoreLastPruned(ctx context.Context, height int64) error
 
 	// GetLastPruned returns the last height at which the database was pruned
-	GetLastPruned() (int64, error)
+	GetLastPruned(ctx context.Context) (int64, error)
 }
 
 // Context contains the data that might be used to build a Database instance
@@ -170,6 +571,29 @@ type Impl struct {
 	EncodingConfig *params.EncodingConfig
 }
 
+type txKey struct{}
+
+// WithTx returns a copy of ctx carrying the given transaction. Every Impl method called with the
+// returned context writes through tx instead of the plain connection, so that all the data
+// of a single height can be committed or rolled back as one unit.
+func WithTx(ctx context.Context, tx *Impl) context.Context {
+	return context.WithValue(ctx, txKey{}, tx)
+}
+
+// TxFromContext returns the transaction carried by ctx, if any.
+func TxFromContext(ctx context.Context) (*Impl, bool) {
+	tx, ok := ctx.Value(txKey{}).(*Impl)
+	return tx, ok
+}
+
+// session returns the transaction carried by ctx if present, or the plain connection otherwise
+func (db *Impl) session(ctx context.Context) *gorm.DB {
+	if tx, ok := TxFromContext(ctx); ok {
+		return tx.Db.WithContext(ctx)
+	}
+	return db.Db.WithContext(ctx)
+}
+
 // createPartitionIfNotExists creates a new partition having the given partition id if not existing
 func (db *Impl) createPartitionIfNotExists(table string, partitionID int64) error {
 	partitionTable := fmt.Sprintf("%s_%d", table, partitionID)
@@ -209,6 +633,11 @@ func (db *Impl) PrepareTables(ctx context.Context, tables []schema.Tabler) error
 	return nil
 }
 
+// HasColumn implements database.Database
+func (db *Impl) HasColumn(ctx context.Context, table schema.Tabler, column string) bool {
+	return db.Db.WithContext(ctx).Migrator().HasColumn(table, column)
+}
+
 func (db *Impl) RecreateTables(ctx context.Context, tables []schema.Tabler) error {
 	m := db.Db.Migrator()
 	for _, t := range tables {
@@ -229,7 +658,7 @@ func (db *Impl) RecreateTables(ctx context.Context, tables []schema.Tabler) erro
 // HasBlock implements database.Database
 func (db *Impl) HasBlock(ctx context.Context, height uint64) (bool, error) {
 	var res bool
-	err := db.Db.Raw(`SELECT EXISTS(SELECT 1 FROM blocks WHERE height = ?);`, height).Scan(&res).Error
+	err := db.session(ctx).Raw(`SELECT EXISTS(SELECT 1 FROM blocks WHERE height = ?);`, height).Scan(&res).Error
 	return res, err
 }
 
@@ -237,7 +666,19 @@ func (db *Impl) HasBlock(ctx context.Context, height uint64) (bool, error) {
 func (db *Impl) GetLastBlockHeight(ctx context.Context) (uint64, error) {
 	var height uint64
 
-	err := db.Db.Table((&models.Block{}).TableName()).Select("height").Order("height DESC").Take(&height).Error
+	err := db.session(ctx).Table((&models.Block{}).TableName()).Select("height").Order("height DESC").Take(&height).Error
+	if errIsNotFound(err) {
+		return 0, nil
+	}
+
+	return height, err
+}
+
+// GetFirstBlockHeight implements database.Database
+func (db *Impl) GetFirstBlockHeight(ctx context.Context) (uint64, error) {
+	var height uint64
+
+	err := db.session(ctx).Table((&models.Block{}).TableName()).Select("height").Order("height ASC").Take(&height).Error
 	if errIsNotFound(err) {
 		return 0, nil
 	}
@@ -247,7 +688,7 @@ func (db *Impl) GetLastBlockHeight(ctx context.Context) (uint64, error) {
 
 // SaveBlock implements database.Database
 func (db *Impl) SaveBlock(ctx context.Context, block *models.Block) error {
-	err := db.Db.Table((&models.Block{}).TableName()).Clauses(clause.OnConflict{
+	err := db.session(ctx).Table((&models.Block{}).TableName()).Clauses(clause.OnConflict{
 		Columns:   []clause.Column{{Name: "hash"}},
 		UpdateAll: true,
 	}, clause.OnConflict{
@@ -260,7 +701,7 @@ func (db *Impl) SaveBlock(ctx context.Context, block *models.Block) error {
 // GetTotalBlocks implements database.Database
 func (db *Impl) GetTotalBlocks(ctx context.Context) int64 {
 	var blockCount int64
-	err := db.Db.Table((&models.Block{}).TableName()).Count(&blockCount).Error
+	err := db.session(ctx).Table((&models.Block{}).TableName()).Count(&blockCount).Error
 	if err != nil {
 		return 0
 	}
@@ -268,6 +709,140 @@ func (db *Impl) GetTotalBlocks(ctx context.Context) int64 {
 	return blockCount
 }
 
+// GetBlock implements database.Database
+func (db *Impl) GetBlock(ctx context.Context, height uint64) (*models.Block, error) {
+	var block models.Block
+
+	err := db.session(ctx).Table((&models.Block{}).TableName()).Where("height = ?", height).Take(&block).Error
+	if errIsNotFound(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &block, nil
+}
+
+// DeleteBlocksAfter implements database.Database
+func (db *Impl) DeleteBlocksAfter(ctx context.Context, height uint64) error {
+	err := db.session(ctx).Table((&models.Tx{}).TableName()).Where("height > ?", height).Delete(&models.Tx{}).Error
+	if err != nil {
+		return err
+	}
+
+	err = db.session(ctx).Table((&models.Block{}).TableName()).Where("height > ?", height).Delete(&models.Block{}).Error
+	if err != nil {
+		return err
+	}
+
+	err = db.session(ctx).Table((&models.PreCommit{}).TableName()).Where("height > ?", height).Delete(&models.PreCommit{}).Error
+	if err != nil {
+		return err
+	}
+
+	err = db.session(ctx).Table((&models.QuarantinedEvent{}).TableName()).Where("height > ?", height).Delete(&models.QuarantinedEvent{}).Error
+	if err != nil {
+		return err
+	}
+
+	return db.truncateSyncProgress(ctx, height)
+}
+
+// DeleteAfter implements database.Database
+func (db *Impl) DeleteAfter(ctx context.Context, table schema.Tabler, column string, value int64) (int64, error) {
+	res := db.session(ctx).Table(table.TableName()).Where(fmt.Sprintf("%s > ?", column), value).Delete(table)
+	return res.RowsAffected, res.Error
+}
+
+// DeleteAt implements database.Database
+func (db *Impl) DeleteAt(ctx context.Context, table schema.Tabler, column string, value int64) (int64, error) {
+	res := db.session(ctx).Table(table.TableName()).Where(fmt.Sprintf("%s = ?", column), value).Delete(table)
+	return res.RowsAffected, res.Error
+}
+
+// CountAfter implements database.Database
+func (db *Impl) CountAfter(ctx context.Context, table schema.Tabler, column string, value int64) (int64, error) {
+	var count int64
+	err := db.session(ctx).Table(table.TableName()).Where(fmt.Sprintf("%s > ?", column), value).Count(&count).Error
+	return count, err
+}
+
+// ListAfter implements database.Database
+func (db *Impl) ListAfter(ctx context.Context, table schema.Tabler, column string, value int64, dest interface{}) error {
+	return db.session(ctx).Table(table.TableName()).Where(fmt.Sprintf("%s > ?", column), value).Find(dest).Error
+}
+
+// SaveFailedHeight implements database.Database
+func (db *Impl) SaveFailedHeight(ctx context.Context, failedHeight *models.FailedHeight) error {
+	return db.session(ctx).Table((&models.FailedHeight{}).TableName()).Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "height"}},
+		UpdateAll: true,
+	}).Create(failedHeight).Error
+}
+
+// GetFailedHeights implements database.Database
+func (db *Impl) GetFailedHeights(ctx context.Context) ([]*models.FailedHeight, error) {
+	var failedHeights []*models.FailedHeight
+
+	err := db.session(ctx).Table((&models.FailedHeight{}).TableName()).Order("height").Find(&failedHeights).Error
+	return failedHeights, err
+}
+
+// GetFailedHeight implements database.Database
+func (db *Impl) GetFailedHeight(ctx context.Context, height uint64) (*models.FailedHeight, error) {
+	var failedHeight models.FailedHeight
+
+	err := db.session(ctx).Table((&models.FailedHeight{}).TableName()).Where("height = ?", height).Take(&failedHeight).Error
+	if errIsNotFound(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &failedHeight, nil
+}
+
+// DeleteFailedHeight implements database.Database
+func (db *Impl) DeleteFailedHeight(ctx context.Context, height uint64) error {
+	return db.session(ctx).Table((&models.FailedHeight{}).TableName()).Where("height = ?", height).Delete(&models.FailedHeight{}).Error
+}
+
+// SaveQuarantinedEvent implements database.Database
+func (db *Impl) SaveQuarantinedEvent(ctx context.Context, event *models.QuarantinedEvent) error {
+	return db.session(ctx).Table((&models.QuarantinedEvent{}).TableName()).Save(event).Error
+}
+
+// GetQuarantinedEvents implements database.Database
+func (db *Impl) GetQuarantinedEvents(ctx context.Context, module string) ([]*models.QuarantinedEvent, error) {
+	var events []*models.QuarantinedEvent
+
+	query := db.session(ctx).Table((&models.QuarantinedEvent{}).TableName())
+	if module != "" {
+		query = query.Where("module = ?", module)
+	}
+	err := query.Order("id").Find(&events).Error
+	return events, err
+}
+
+// GetQuarantinedEvent implements database.Database
+func (db *Impl) GetQuarantinedEvent(ctx context.Context, id uint64) (*models.QuarantinedEvent, error) {
+	var event models.QuarantinedEvent
+
+	err := db.session(ctx).Table((&models.QuarantinedEvent{}).TableName()).Where("id = ?", id).Take(&event).Error
+	if errIsNotFound(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &event, nil
+}
+
+// DeleteQuarantinedEvent implements database.Database
+func (db *Impl) DeleteQuarantinedEvent(ctx context.Context, id uint64) error {
+	return db.session(ctx).Table((&models.QuarantinedEvent{}).TableName()).Where("id = ?", id).Delete(&models.QuarantinedEvent{}).Error
+}
+
 // SaveTx implements database.Database
 func (db *Impl) SaveTx(ctx context.Context, blockTimestamp uint64, index int, tx *types.Tx) error {
 	var sigs = make([]string, len(tx.Signatures))
@@ -322,7 +897,7 @@ func (db *Impl) SaveTx(ctx context.Context, blockTimestamp uint64, index int, tx
 		Timestamp:   blockTimestamp,
 	}
 
-	err = db.Db.Table((&models.Tx{}).TableName()).Clauses(clause.OnConflict{
+	err = db.session(ctx).Table((&models.Tx{}).TableName()).Clauses(clause.OnConflict{
 		Columns:   []clause.Column{{Name: "hash"}},
 		UpdateAll: true,
 	}, clause.OnConflict{
@@ -336,7 +911,7 @@ func (db *Impl) SaveTx(ctx context.Context, blockTimestamp uint64, index int, tx
 func (db *Impl) HasValidator(ctx context.Context, addr common.Address) (bool, error) {
 	var res bool
 	stmt := `SELECT EXISTS(SELECT 1 FROM validators WHERE consensus_address = ?);`
-	err := db.Db.Raw(stmt, addr).WithContext(ctx).Take(&res).Error
+	err := db.session(ctx).Raw(stmt, addr).Take(&res).Error
 	return res, err
 }
 
@@ -346,36 +921,50 @@ func (db *Impl) SaveValidators(ctx context.Context, validators []*models.Validat
 		return nil
 	}
 
-	err := db.Db.Table((&models.Validator{}).TableName()).WithContext(ctx).
+	err := db.session(ctx).Table((&models.Validator{}).TableName()).
 		Clauses(clause.OnConflict{DoNothing: true}).Save(validators).Error
 
 	return err
 }
 
 // SaveCommitSignatures implements database.Database
-func (db *Impl) SaveCommitSignatures(ctx context.Context, signatures []*types.CommitSig) error {
+func (db *Impl) SaveCommitSignatures(ctx context.Context, signatures []*models.PreCommit) error {
 	if len(signatures) == 0 {
 		return nil
 	}
 
-	stmt := `INSERT INTO pre_commit (validator_address, height, timestamp, voting_power, proposer_priority) VALUES `
+	return db.session(ctx).Table((&models.PreCommit{}).TableName()).Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "validator_address"}, {Name: "height"}},
+		DoNothing: true,
+	}).Create(signatures).Error
+}
+
+// GetValidatorUptimes implements database.Database
+func (db *Impl) GetValidatorUptimes(ctx context.Context, validatorAddresses []common.Address) ([]*models.ValidatorUptime, error) {
+	var uptimes []*models.ValidatorUptime
+	if len(validatorAddresses) == 0 {
+		return uptimes, nil
+	}
 
-	var sparams []interface{}
-	for i, sig := range signatures {
-		si := i * 5
+	err := db.session(ctx).Table((&models.ValidatorUptime{}).TableName()).
+		Where("validator_address IN ?", validatorAddresses).Find(&uptimes).Error
+	return uptimes, err
+}
 
-		stmt += fmt.Sprintf("($%d, $%d, $%d, $%d, $%d),", si+1, si+2, si+3, si+4, si+5)
-		sparams = append(sparams, sig.ValidatorAddress, sig.Height, sig.Timestamp, sig.VotingPower, sig.ProposerPriority)
+// SaveValidatorUptimes implements database.Database
+func (db *Impl) SaveValidatorUptimes(ctx context.Context, uptimes []*models.ValidatorUptime) error {
+	if len(uptimes) == 0 {
+		return nil
 	}
 
-	stmt = stmt[:len(stmt)-1]
-	stmt += " ON CONFLICT (validator_address, timestamp) DO NOTHING"
-	err := db.Db.WithContext(ctx).Exec(stmt, sparams...).Error
-	return err
+	return db.session(ctx).Table((&models.ValidatorUptime{}).TableName()).Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "validator_address"}},
+		UpdateAll: true,
+	}).Create(uptimes).Error
 }
 
 func (db *Impl) SaveBucket(ctx context.Context, bucket *models.Bucket) error {
-	err := db.Db.WithContext(ctx).Table((&models.Bucket{}).TableName()).Clauses(clause.OnConflict{
+	err := db.session(ctx).Table((&models.Bucket{}).TableName()).Clauses(clause.OnConflict{
 		Columns:   []clause.Column{{Name: "bucket_id"}},
 		UpdateAll: true,
 	}).Create(bucket).Error
@@ -383,12 +972,17 @@ func (db *Impl) SaveBucket(ctx context.Context, bucket *models.Bucket) error {
 }
 
 func (db *Impl) UpdateBucket(ctx context.Context, bucket *models.Bucket) error {
-	err := db.Db.WithContext(ctx).Table((&models.Bucket{}).TableName()).Where("bucket_id = ?", bucket.BucketID).Updates(bucket).Error
+	err := db.session(ctx).Table((&models.Bucket{}).TableName()).Where("bucket_id = ?", bucket.BucketID).Updates(bucket).Error
 	return err
 }
 
+// SaveBucketQuotaChange implements database.Database
+func (db *Impl) SaveBucketQuotaChange(ctx context.Context, change *models.BucketQuotaChange) error {
+	return db.session(ctx).Table((&models.BucketQuotaChange{}).TableName()).Create(change).Error
+}
+
 func (db *Impl) SaveObject(ctx context.Context, object *models.Object) error {
-	err := db.Db.WithContext(ctx).Table((&models.Object{}).TableName()).Clauses(clause.OnConflict{
+	err := db.session(ctx).Table((&models.Object{}).TableName()).Clauses(clause.OnConflict{
 		Columns:   []clause.Column{{Name: "object_id"}},
 		UpdateAll: true,
 	}).Create(object).Error
@@ -396,14 +990,14 @@ func (db *Impl) SaveObject(ctx context.Context, object *models.Object) error {
 }
 
 func (db *Impl) UpdateObject(ctx context.Context, object *models.Object) error {
-	err := db.Db.WithContext(ctx).Table((&models.Object{}).TableName()).Where("object_id = ?", object.ObjectID).Updates(object).Error
+	err := db.session(ctx).Table((&models.Object{}).TableName()).Where("object_id = ?", object.ObjectID).Updates(object).Error
 	return err
 }
 
 func (db *Impl) GetObject(ctx context.Context, objectId common.Hash) (*models.Object, error) {
 	var object models.Object
 
-	err := db.Db.WithContext(ctx).Where(
+	err := db.session(ctx).Where(
 		"object_id = ? AND removed IS NOT TRUE", objectId).Find(&object).Error
 	if err != nil {
 		return nil, err
@@ -411,24 +1005,275 @@ func (db *Impl) GetObject(ctx context.Context, objectId common.Hash) (*models.Ob
 	return &object, nil
 }
 
-func (db *Impl) SaveStreamRecord(ctx context.Context, streamRecord *models.StreamRecord) error {
-	err := db.Db.WithContext(ctx).Table((&models.StreamRecord{}).TableName()).Clauses(clause.OnConflict{
-		Columns:   []clause.Column{{Name: "account"}},
-		UpdateAll: true,
-	}).Create(streamRecord).Error
-	return err
-}
+// GetGroupMemberAtHeight implements database.Database
+func (db *Impl) GetGroupMemberAtHeight(ctx context.Context, groupID common.Hash, member common.Address, height uint64) (*models.GroupMember, error) {
+	var version models.GroupVersion
 
-func (db *Impl) SavePaymentAccount(ctx context.Context, paymentAccount *models.PaymentAccount) error {
-	err := db.Db.WithContext(ctx).Table((&models.PaymentAccount{}).TableName()).Clauses(clause.OnConflict{
-		Columns:   []clause.Column{{Name: "addr"}},
-		UpdateAll: true,
-	}).Create(paymentAccount).Error
-	return err
+	err := db.session(ctx).Table((&models.GroupVersion{}).TableName()).
+		Where("group_id = ? AND member = ? AND height <= ?", groupID, member, height).
+		Order("height DESC, id DESC").
+		Take(&version).Error
+	if errIsNotFound(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var groupMember models.GroupMember
+	err = json.Unmarshal([]byte(version.State), &groupMember)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode group version %d: %s", version.ID, err)
+	}
+	return &groupMember, nil
+}
+
+// GetBucketByID implements database.Database
+func (db *Impl) GetBucketByID(ctx context.Context, bucketID common.Hash) (*models.Bucket, error) {
+	var bucket models.Bucket
+
+	err := db.session(ctx).Table((&models.Bucket{}).TableName()).Where("bucket_id = ?", bucketID).Take(&bucket).Error
+	if errIsNotFound(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &bucket, nil
+}
+
+// GetObjectByID implements database.Database
+func (db *Impl) GetObjectByID(ctx context.Context, objectID common.Hash) (*models.Object, error) {
+	var object models.Object
+
+	err := db.session(ctx).Table((&models.Object{}).TableName()).Where("object_id = ?", objectID).Take(&object).Error
+	if errIsNotFound(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &object, nil
+}
+
+// GetGroupMembers implements database.Database
+func (db *Impl) GetGroupMembers(ctx context.Context, groupID common.Hash) ([]*models.GroupMember, error) {
+	var members []*models.GroupMember
+
+	err := db.session(ctx).Table((&models.GroupMember{}).TableName()).Where("group_id = ?", groupID).Find(&members).Error
+	return members, err
+}
+
+// SaveBucketVersion implements database.Database
+func (db *Impl) SaveBucketVersion(ctx context.Context, version *models.BucketVersion) error {
+	return db.session(ctx).Table((&models.BucketVersion{}).TableName()).Create(version).Error
+}
+
+// SaveObjectVersion implements database.Database
+func (db *Impl) SaveObjectVersion(ctx context.Context, version *models.ObjectVersion) error {
+	return db.session(ctx).Table((&models.ObjectVersion{}).TableName()).Create(version).Error
+}
+
+// SaveGroupVersions implements database.Database
+func (db *Impl) SaveGroupVersions(ctx context.Context, versions []*models.GroupVersion) error {
+	return db.session(ctx).Table((&models.GroupVersion{}).TableName()).Create(versions).Error
+}
+
+// GetBucketAtHeight implements database.Database
+func (db *Impl) GetBucketAtHeight(ctx context.Context, bucketID common.Hash, height uint64) (*models.Bucket, error) {
+	var version models.BucketVersion
+
+	err := db.session(ctx).Table((&models.BucketVersion{}).TableName()).
+		Where("bucket_id = ? AND height <= ?", bucketID, height).
+		Order("height DESC, id DESC").
+		Take(&version).Error
+	if errIsNotFound(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var bucket models.Bucket
+	err = json.Unmarshal([]byte(version.State), &bucket)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode bucket version %d: %s", version.ID, err)
+	}
+	return &bucket, nil
+}
+
+// GetObjectAtHeight implements database.Database
+func (db *Impl) GetObjectAtHeight(ctx context.Context, objectID common.Hash, height uint64) (*models.Object, error) {
+	var version models.ObjectVersion
+
+	err := db.session(ctx).Table((&models.ObjectVersion{}).TableName()).
+		Where("object_id = ? AND height <= ?", objectID, height).
+		Order("height DESC, id DESC").
+		Take(&version).Error
+	if errIsNotFound(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var object models.Object
+	err = json.Unmarshal([]byte(version.State), &object)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode object version %d: %s", version.ID, err)
+	}
+	return &object, nil
+}
+
+func (db *Impl) SaveStreamRecord(ctx context.Context, streamRecord *models.StreamRecord) error {
+	err := db.session(ctx).Table((&models.StreamRecord{}).TableName()).Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "account"}},
+		UpdateAll: true,
+	}).Create(streamRecord).Error
+	return err
+}
+
+// SaveStreamRecordHistory implements database.Database
+func (db *Impl) SaveStreamRecordHistory(ctx context.Context, history *models.StreamRecordHistory) error {
+	return db.session(ctx).Table((&models.StreamRecordHistory{}).TableName()).Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "account"}, {Name: "height"}},
+		UpdateAll: true,
+	}).Create(history).Error
+}
+
+// GetStreamRecordAtHeight implements database.Database
+func (db *Impl) GetStreamRecordAtHeight(ctx context.Context, account common.Address, height int64) (*models.StreamRecordHistory, error) {
+	var history models.StreamRecordHistory
+
+	err := db.session(ctx).Table((&models.StreamRecordHistory{}).TableName()).
+		Where("account = ? AND height <= ?", account, height).Order("height DESC").Take(&history).Error
+	if errIsNotFound(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &history, nil
+}
+
+// ListStreamRecordHistory implements database.Database
+func (db *Impl) ListStreamRecordHistory(ctx context.Context, account common.Address, fromHeight, toHeight int64) ([]*models.StreamRecordHistory, error) {
+	var histories []*models.StreamRecordHistory
+
+	err := db.session(ctx).Table((&models.StreamRecordHistory{}).TableName()).
+		Where("account = ? AND height >= ? AND height <= ?", account, fromHeight, toHeight).Order("height").Find(&histories).Error
+	return histories, err
+}
+
+// SaveStreamOutflows implements database.Database
+func (db *Impl) SaveStreamOutflows(ctx context.Context, outflows []*models.StreamOutflow) error {
+	if len(outflows) == 0 {
+		return nil
+	}
+	return db.session(ctx).Table((&models.StreamOutflow{}).TableName()).Create(outflows).Error
+}
+
+// DeleteStreamOutflows implements database.Database
+func (db *Impl) DeleteStreamOutflows(ctx context.Context, from common.Address) error {
+	return db.session(ctx).Table((&models.StreamOutflow{}).TableName()).Where("from_address = ?", from).Delete(&models.StreamOutflow{}).Error
+}
+
+// GetStreamOutflows implements database.Database
+func (db *Impl) GetStreamOutflows(ctx context.Context, from common.Address) ([]*models.StreamOutflow, error) {
+	var outflows []*models.StreamOutflow
+
+	err := db.session(ctx).Table((&models.StreamOutflow{}).TableName()).Where("from_address = ?", from).Order("to_address").Find(&outflows).Error
+	return outflows, err
+}
+
+// GetStreamInflows implements database.Database
+func (db *Impl) GetStreamInflows(ctx context.Context, to common.Address) ([]*models.StreamOutflow, error) {
+	var inflows []*models.StreamOutflow
+
+	err := db.session(ctx).Table((&models.StreamOutflow{}).TableName()).Where("to_address = ?", to).Order("from_address").Find(&inflows).Error
+	return inflows, err
+}
+
+func (db *Impl) SavePaymentAccount(ctx context.Context, paymentAccount *models.PaymentAccount) error {
+	err := db.session(ctx).Table((&models.PaymentAccount{}).TableName()).Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "addr"}},
+		UpdateAll: true,
+	}).Create(paymentAccount).Error
+	return err
+}
+
+// GetPaymentAccount implements database.Database
+func (db *Impl) GetPaymentAccount(ctx context.Context, addr common.Address) (*models.PaymentAccount, error) {
+	var paymentAccount models.PaymentAccount
+
+	err := db.session(ctx).Table((&models.PaymentAccount{}).TableName()).Where("addr = ?", addr).Take(&paymentAccount).Error
+	if errIsNotFound(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &paymentAccount, nil
+}
+
+// SavePaymentTransfer implements database.Database
+func (db *Impl) SavePaymentTransfer(ctx context.Context, transfer *models.PaymentTransfer) error {
+	return db.session(ctx).Table((&models.PaymentTransfer{}).TableName()).Create(transfer).Error
+}
+
+// ListPaymentTransfers implements database.Database
+func (db *Impl) ListPaymentTransfers(ctx context.Context, account common.Address, startAfter uint64, limit int) ([]*models.PaymentTransfer, error) {
+	var transfers []*models.PaymentTransfer
+
+	err := db.session(ctx).Table((&models.PaymentTransfer{}).TableName()).
+		Where("account = ? AND id > ?", account, startAfter).Order("id").Limit(limit).Find(&transfers).Error
+	return transfers, err
+}
+
+// SaveStorageProvider implements database.Database
+func (db *Impl) SaveStorageProvider(ctx context.Context, sp *models.StorageProvider) error {
+	return db.session(ctx).Table((&models.StorageProvider{}).TableName()).Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "operator_address"}},
+		UpdateAll: true,
+	}).Create(sp).Error
+}
+
+// UpdateStorageProvider implements database.Database
+func (db *Impl) UpdateStorageProvider(ctx context.Context, sp *models.StorageProvider) error {
+	return db.session(ctx).Table((&models.StorageProvider{}).TableName()).Where("operator_address = ?", sp.OperatorAddress).Updates(sp).Error
+}
+
+// GetStorageProviderByEndpoint implements database.Database
+func (db *Impl) GetStorageProviderByEndpoint(ctx context.Context, endpoint string) (*models.StorageProvider, error) {
+	return db.getStorageProvider(ctx, "endpoint = ?", endpoint)
+}
+
+// GetStorageProviderByFundingAddress implements database.Database
+func (db *Impl) GetStorageProviderByFundingAddress(ctx context.Context, address common.Address) (*models.StorageProvider, error) {
+	return db.getStorageProvider(ctx, "funding_address = ?", address)
+}
+
+// getStorageProvider returns the storage provider matching the given condition, or nil if it does not exist
+func (db *Impl) getStorageProvider(ctx context.Context, query string, args ...interface{}) (*models.StorageProvider, error) {
+	var sp models.StorageProvider
+
+	err := db.session(ctx).Table((&models.StorageProvider{}).TableName()).Where(query, args...).Take(&sp).Error
+	if errIsNotFound(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &sp, nil
+}
+
+// SaveStoragePrice implements database.Database
+func (db *Impl) SaveStoragePrice(ctx context.Context, price *models.StoragePrice) error {
+	return db.session(ctx).Table((&models.StoragePrice{}).TableName()).Create(price).Error
 }
 
 func (db *Impl) SaveEpoch(ctx context.Context, epoch *models.Epoch) error {
-	err := db.Db.Table((&models.Epoch{}).TableName()).Clauses(clause.OnConflict{
+	err := db.session(ctx).Table((&models.Epoch{}).TableName()).Clauses(clause.OnConflict{
 		Columns:   []clause.Column{{Name: "one_row_id"}},
 		DoUpdates: clause.AssignmentColumns([]string{"block_height", "block_hash", "update_time"}),
 	}).Create(epoch).Error
@@ -438,7 +1283,7 @@ func (db *Impl) SaveEpoch(ctx context.Context, epoch *models.Epoch) error {
 func (db *Impl) GetEpoch(ctx context.Context) (*models.Epoch, error) {
 	var epoch models.Epoch
 
-	err := db.Db.Find(&epoch).Error
+	err := db.session(ctx).Find(&epoch).Error
 	if err != nil && !errIsNotFound(err) {
 		return nil, err
 	}
@@ -446,43 +1291,547 @@ func (db *Impl) GetEpoch(ctx context.Context) (*models.Epoch, error) {
 }
 
 func (db *Impl) SavePermission(ctx context.Context, permission *models.Permission) error {
-	return db.Db.WithContext(ctx).Table((&models.Permission{}).TableName()).Clauses(clause.OnConflict{
+	return db.session(ctx).Table((&models.Permission{}).TableName()).Clauses(clause.OnConflict{
 		Columns:   []clause.Column{{Name: "principal_type"}, {Name: "principal_value"}, {Name: "resource_type"}, {Name: "resource_id"}},
 		UpdateAll: true,
 	}).Create(permission).Error
 }
 
 func (db *Impl) UpdatePermission(ctx context.Context, permission *models.Permission) error {
-	return db.Db.WithContext(ctx).Table((&models.Permission{}).TableName()).Where("policy_id = ?", permission.PolicyID).Updates(permission).Error
+	return db.session(ctx).Table((&models.Permission{}).TableName()).Where("policy_id = ?", permission.PolicyID).Updates(permission).Error
 }
 
-func (db *Impl) CreateGroup(ctx context.Context, groupMembers []*models.Group) error {
-	err := db.Db.WithContext(ctx).Table((&models.Group{}).TableName()).Clauses(clause.OnConflict{
-		Columns:   []clause.Column{{Name: "group_id"}, {Name: "account_id"}},
+// SaveGroup implements database.Database
+func (db *Impl) SaveGroup(ctx context.Context, group *models.Group) error {
+	return db.session(ctx).Table((&models.Group{}).TableName()).Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "group_id"}},
 		UpdateAll: true,
-	}).Create(groupMembers).Error
-	return err
+	}).Create(group).Error
 }
 
+// UpdateGroup implements database.Database
 func (db *Impl) UpdateGroup(ctx context.Context, group *models.Group) error {
-	return db.Db.WithContext(ctx).Table((&models.Group{}).TableName()).Where("group_id = ? AND account_id = ?", group.GroupID, group.AccountID).Updates(group).Error
+	return db.session(ctx).Table((&models.Group{}).TableName()).Where("group_id = ?", group.GroupID).Updates(group).Error
+}
+
+// SaveGroupMembers implements database.Database
+func (db *Impl) SaveGroupMembers(ctx context.Context, members []*models.GroupMember) error {
+	if len(members) == 0 {
+		return nil
+	}
+	return db.session(ctx).Table((&models.GroupMember{}).TableName()).Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "group_id"}, {Name: "member"}},
+		UpdateAll: true,
+	}).Create(members).Error
+}
+
+// UpdateGroupMember implements database.Database
+func (db *Impl) UpdateGroupMember(ctx context.Context, member *models.GroupMember) error {
+	return db.session(ctx).Table((&models.GroupMember{}).TableName()).
+		Where("group_id = ? AND member = ?", member.GroupID, member.Member).Updates(member).Error
+}
+
+// SaveGroupMemberChanges implements database.Database
+func (db *Impl) SaveGroupMemberChanges(ctx context.Context, changes []*models.GroupMemberChange) error {
+	if len(changes) == 0 {
+		return nil
+	}
+	return db.session(ctx).Table((&models.GroupMemberChange{}).TableName()).Create(changes).Error
+}
+
+// SaveProposal implements database.Database
+func (db *Impl) SaveProposal(ctx context.Context, proposal *models.Proposal) error {
+	return db.session(ctx).Table((&models.Proposal{}).TableName()).Clauses(clause.OnConflict{
+		Columns: []clause.Column{{Name: "proposal_id"}},
+		DoUpdates: clause.AssignmentColumns([]string{
+			"status", "submit_time", "deposit_end_time", "voting_start_time", "voting_end_time", "total_deposit",
+			"yes_count", "abstain_count", "no_count", "no_with_veto_count", "update_at", "update_tx_hash",
+		}),
+	}).Create(proposal).Error
+}
+
+// SaveProposalDeposit implements database.Database
+func (db *Impl) SaveProposalDeposit(ctx context.Context, deposit *models.ProposalDeposit) error {
+	return db.session(ctx).Table((&models.ProposalDeposit{}).TableName()).Create(deposit).Error
+}
+
+// SaveProposalVote implements database.Database
+func (db *Impl) SaveProposalVote(ctx context.Context, vote *models.ProposalVote) error {
+	return db.session(ctx).Table((&models.ProposalVote{}).TableName()).Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "proposal_id"}, {Name: "voter"}},
+		UpdateAll: true,
+	}).Create(vote).Error
 }
 
-func (db *Impl) DeleteGroup(ctx context.Context, group *models.Group) error {
-	return db.Db.WithContext(ctx).Table((&models.Group{}).TableName()).Where("group_id = ?", group.GroupID).Updates(group).Error
+// SaveCrossChainPackage implements database.Database
+func (db *Impl) SaveCrossChainPackage(ctx context.Context, pkg *models.CrossChainPackage) error {
+	return db.session(ctx).Table((&models.CrossChainPackage{}).TableName()).Clauses(clause.OnConflict{
+		Columns: []clause.Column{
+			{Name: "direction"}, {Name: "src_chain_id"}, {Name: "dest_chain_id"}, {Name: "channel_id"}, {Name: "sequence"},
+		},
+		UpdateAll: true,
+	}).Create(pkg).Error
+}
+
+// SaveMirror implements database.Database
+func (db *Impl) SaveMirror(ctx context.Context, mirror *models.Mirror) error {
+	return db.session(ctx).Table((&models.Mirror{}).TableName()).Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "resource_type"}, {Name: "resource_id"}},
+		UpdateAll: true,
+	}).Create(mirror).Error
+}
+
+// UpdateMirror implements database.Database
+func (db *Impl) UpdateMirror(ctx context.Context, mirror *models.Mirror) error {
+	return db.session(ctx).Table((&models.Mirror{}).TableName()).
+		Where("resource_type = ? AND resource_id = ?", mirror.ResourceType, mirror.ResourceID).Updates(mirror).Error
+}
+
+// SaveValidatorInfo implements database.Database
+func (db *Impl) SaveValidatorInfo(ctx context.Context, info *models.ValidatorInfo) error {
+	return db.session(ctx).Table((&models.ValidatorInfo{}).TableName()).Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "validator_address"}},
+		UpdateAll: true,
+	}).Create(info).Error
+}
+
+// SaveValidatorDescription implements database.Database
+func (db *Impl) SaveValidatorDescription(ctx context.Context, description *models.ValidatorDescription) error {
+	return db.session(ctx).Table((&models.ValidatorDescription{}).TableName()).Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "validator_address"}},
+		UpdateAll: true,
+	}).Create(description).Error
+}
+
+// SaveValidatorCommission implements database.Database
+func (db *Impl) SaveValidatorCommission(ctx context.Context, commission *models.ValidatorCommission) error {
+	return db.session(ctx).Table((&models.ValidatorCommission{}).TableName()).Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "validator_address"}},
+		UpdateAll: true,
+	}).Create(commission).Error
+}
+
+// SaveValidatorStatus implements database.Database
+func (db *Impl) SaveValidatorStatus(ctx context.Context, status *models.ValidatorStatus) error {
+	return db.session(ctx).Table((&models.ValidatorStatus{}).TableName()).Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "validator_address"}},
+		UpdateAll: true,
+	}).Create(status).Error
+}
+
+// SaveValidatorSigningInfo implements database.Database
+func (db *Impl) SaveValidatorSigningInfo(ctx context.Context, info *models.ValidatorSigningInfo) error {
+	return db.session(ctx).Table((&models.ValidatorSigningInfo{}).TableName()).Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "validator_address"}},
+		UpdateAll: true,
+	}).Create(info).Error
+}
+
+// SaveValidatorVotingPowers implements database.Database
+func (db *Impl) SaveValidatorVotingPowers(ctx context.Context, powers []*models.ValidatorVotingPower) error {
+	if len(powers) == 0 {
+		return nil
+	}
+	return db.session(ctx).Table((&models.ValidatorVotingPower{}).TableName()).Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "validator_address"}},
+		UpdateAll: true,
+	}).Create(powers).Error
+}
+
+// SaveTransfers implements database.Database
+func (db *Impl) SaveTransfers(ctx context.Context, transfers []*models.Transfer) error {
+	if len(transfers) == 0 {
+		return nil
+	}
+	return db.session(ctx).Table((&models.Transfer{}).TableName()).Create(transfers).Error
+}
+
+// SaveAccountBalances implements database.Database
+func (db *Impl) SaveAccountBalances(ctx context.Context, balances []*models.AccountBalance) error {
+	if len(balances) == 0 {
+		return nil
+	}
+	return db.session(ctx).Table((&models.AccountBalance{}).TableName()).Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "account"}, {Name: "denom"}, {Name: "height"}},
+		UpdateAll: true,
+	}).Create(balances).Error
+}
+
+func (db *Impl) DeletePoliciesCreatedAfter(ctx context.Context, timestamp int64) (int64, error) {
+	policies := db.session(ctx).Table((&models.Permission{}).TableName()).Select("policy_id").Where("create_timestamp > ?", timestamp)
+	err := db.session(ctx).Table((&models.Statements{}).TableName()).Where("policy_id IN (?)", policies).Delete(&models.Statements{}).Error
+	if err != nil {
+		return 0, err
+	}
+
+	res := db.session(ctx).Table((&models.Permission{}).TableName()).Where("create_timestamp > ?", timestamp).Delete(&models.Permission{})
+	return res.RowsAffected, res.Error
 }
 
 func (db *Impl) MultiSaveStatement(ctx context.Context, statements []*models.Statements) error {
-	return db.Db.WithContext(ctx).Table((&models.Statements{}).TableName()).Create(statements).Error
+	return db.session(ctx).Table((&models.Statements{}).TableName()).Create(statements).Error
 }
 
 func (db *Impl) RemoveStatements(ctx context.Context, policyID common.Hash) error {
-	return db.Db.WithContext(ctx).Table((&models.Statements{}).TableName()).Where("policy_id = ?", policyID).Update("removed", true).Error
+	return db.session(ctx).Table((&models.Statements{}).TableName()).Where("policy_id = ?", policyID).Update("removed", true).Error
+}
+
+// GetBucketByName implements database.Database
+func (db *Impl) GetBucketByName(ctx context.Context, bucketName string) (*models.Bucket, error) {
+	var bucket models.Bucket
+
+	err := db.session(ctx).Table((&models.Bucket{}).TableName()).
+		Where("bucket_name = ? AND removed IS NOT TRUE", bucketName).
+		Take(&bucket).Error
+	if errIsNotFound(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &bucket, nil
+}
+
+// ListObjectsByBucketName implements database.Database
+func (db *Impl) ListObjectsByBucketName(ctx context.Context, bucketName string, startAfter uint64, limit int) ([]*models.Object, error) {
+	var objects []*models.Object
+
+	err := db.session(ctx).Table((&models.Object{}).TableName()).
+		Where("bucket_name = ? AND id > ? AND removed IS NOT TRUE", bucketName, startAfter).
+		Order("id").
+		Limit(limit).
+		Find(&objects).Error
+	return objects, err
+}
+
+// ListObjectsByOwner implements database.Database
+func (db *Impl) ListObjectsByOwner(ctx context.Context, owner common.Address, startAfter uint64, limit int) ([]*models.Object, error) {
+	var objects []*models.Object
+
+	err := db.session(ctx).Table((&models.Object{}).TableName()).
+		Where("owner_address = ? AND id > ? AND removed IS NOT TRUE", owner, startAfter).
+		Order("id").
+		Limit(limit).
+		Find(&objects).Error
+	return objects, err
+}
+
+// ListPoliciesByResource implements database.Database
+func (db *Impl) ListPoliciesByResource(ctx context.Context, resourceType string, resourceID common.Hash) ([]*models.Permission, error) {
+	var policies []*models.Permission
+
+	err := db.session(ctx).Table((&models.Permission{}).TableName()).
+		Where("resource_type = ? AND resource_id = ? AND removed IS NOT TRUE", resourceType, resourceID).
+		Find(&policies).Error
+	return policies, err
+}
+
+// GetGroup implements database.Database
+func (db *Impl) GetGroup(ctx context.Context, groupID common.Hash) (*models.Group, error) {
+	var group models.Group
+
+	err := db.session(ctx).Table((&models.Group{}).TableName()).Where("group_id = ?", groupID).Take(&group).Error
+	if errIsNotFound(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &group, nil
+}
+
+// GetGroupByName implements database.Database
+func (db *Impl) GetGroupByName(ctx context.Context, owner common.Address, groupName string) (*models.Group, error) {
+	var group models.Group
+
+	err := db.session(ctx).Table((&models.Group{}).TableName()).
+		Where("owner = ? AND group_name = ? AND removed IS NOT TRUE", owner, groupName).Take(&group).Error
+	if errIsNotFound(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &group, nil
+}
+
+// GetGroupMember implements database.Database
+func (db *Impl) GetGroupMember(ctx context.Context, groupID common.Hash, member common.Address) (*models.GroupMember, error) {
+	var groupMember models.GroupMember
+
+	err := db.session(ctx).Table((&models.GroupMember{}).TableName()).
+		Where("group_id = ? AND member = ?", groupID, member).Take(&groupMember).Error
+	if errIsNotFound(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &groupMember, nil
+}
+
+// ListGroupMembers implements database.Database
+func (db *Impl) ListGroupMembers(ctx context.Context, groupID common.Hash, time int64, startAfter uint64, limit int) ([]*models.GroupMember, error) {
+	var members []*models.GroupMember
+
+	err := db.session(ctx).Table((&models.GroupMember{}).TableName()).
+		Where("group_id = ? AND id > ? AND removed IS NOT TRUE AND (expiration_time = 0 OR expiration_time > ?)", groupID, startAfter, time).
+		Order("id").
+		Limit(limit).
+		Find(&members).Error
+	return members, err
+}
+
+// ListGroupsByMember implements database.Database
+func (db *Impl) ListGroupsByMember(ctx context.Context, member common.Address, time int64, startAfter uint64, limit int) ([]*models.Group, error) {
+	var groups []*models.Group
+
+	memberships := db.session(ctx).Table((&models.GroupMember{}).TableName()).Select("group_id").
+		Where("member = ? AND removed IS NOT TRUE AND (expiration_time = 0 OR expiration_time > ?)", member, time)
+	err := db.session(ctx).Table((&models.Group{}).TableName()).
+		Where("group_id IN (?) AND id > ? AND removed IS NOT TRUE", memberships, startAfter).
+		Order("id").
+		Limit(limit).
+		Find(&groups).Error
+	return groups, err
+}
+
+// GetGroupMemberChanges implements database.Database
+func (db *Impl) GetGroupMemberChanges(ctx context.Context, groupID common.Hash) ([]*models.GroupMemberChange, error) {
+	var changes []*models.GroupMemberChange
+
+	err := db.session(ctx).Table((&models.GroupMemberChange{}).TableName()).Where("group_id = ?", groupID).Order("id").Find(&changes).Error
+	return changes, err
+}
+
+// GetGroupMemberChangesByTx implements database.Database
+func (db *Impl) GetGroupMemberChangesByTx(ctx context.Context, txHash common.Hash) ([]*models.GroupMemberChange, error) {
+	var changes []*models.GroupMemberChange
+
+	err := db.session(ctx).Table((&models.GroupMemberChange{}).TableName()).Where("tx_hash = ?", txHash).Order("id").Find(&changes).Error
+	return changes, err
+}
+
+// GetProposal implements database.Database
+func (db *Impl) GetProposal(ctx context.Context, proposalID uint64) (*models.Proposal, error) {
+	var proposal models.Proposal
+
+	err := db.session(ctx).Table((&models.Proposal{}).TableName()).Where("proposal_id = ?", proposalID).Take(&proposal).Error
+	if errIsNotFound(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &proposal, nil
+}
+
+// GetProposalDeposits implements database.Database
+func (db *Impl) GetProposalDeposits(ctx context.Context, proposalID uint64) ([]*models.ProposalDeposit, error) {
+	var deposits []*models.ProposalDeposit
+
+	err := db.session(ctx).Table((&models.ProposalDeposit{}).TableName()).Where("proposal_id = ?", proposalID).Order("id").Find(&deposits).Error
+	return deposits, err
+}
+
+// ListProposalVotes implements database.Database
+func (db *Impl) ListProposalVotes(ctx context.Context, proposalID uint64, startAfter uint64, limit int) ([]*models.ProposalVote, error) {
+	var votes []*models.ProposalVote
+
+	err := db.session(ctx).Table((&models.ProposalVote{}).TableName()).
+		Where("proposal_id = ? AND id > ?", proposalID, startAfter).Order("id").Limit(limit).Find(&votes).Error
+	return votes, err
+}
+
+// GetLastSentPackage implements database.Database
+func (db *Impl) GetLastSentPackage(ctx context.Context, txHash common.Hash, channelID uint32) (*models.CrossChainPackage, error) {
+	return db.getCrossChainPackage(ctx, "direction = ? AND tx_hash = ? AND channel_id = ?", models.CrossChainPackageSend, txHash, channelID)
+}
+
+// GetSentPackage implements database.Database
+func (db *Impl) GetSentPackage(ctx context.Context, destChainID, channelID uint32, sequence uint64) (*models.CrossChainPackage, error) {
+	return db.getCrossChainPackage(ctx, "direction = ? AND dest_chain_id = ? AND channel_id = ? AND sequence = ?",
+		models.CrossChainPackageSend, destChainID, channelID, sequence)
+}
+
+// getCrossChainPackage returns the package with the highest sequence matching the given condition,
+// or nil if there is none
+func (db *Impl) getCrossChainPackage(ctx context.Context, query string, args ...interface{}) (*models.CrossChainPackage, error) {
+	var pkg models.CrossChainPackage
+
+	err := db.session(ctx).Table((&models.CrossChainPackage{}).TableName()).Where(query, args...).Order("sequence DESC").Take(&pkg).Error
+	if errIsNotFound(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &pkg, nil
+}
+
+// GetCrossChainPackagesByTx implements database.Database
+func (db *Impl) GetCrossChainPackagesByTx(ctx context.Context, txHash common.Hash) ([]*models.CrossChainPackage, error) {
+	var packages []*models.CrossChainPackage
+
+	err := db.session(ctx).Table((&models.CrossChainPackage{}).TableName()).Where("tx_hash = ?", txHash).Order("id").Find(&packages).Error
+	return packages, err
+}
+
+// GetMirror implements database.Database
+func (db *Impl) GetMirror(ctx context.Context, resourceType string, resourceID common.Hash) (*models.Mirror, error) {
+	var mirror models.Mirror
+
+	err := db.session(ctx).Table((&models.Mirror{}).TableName()).
+		Where("resource_type = ? AND resource_id = ?", resourceType, resourceID).Take(&mirror).Error
+	if errIsNotFound(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &mirror, nil
+}
+
+// GetValidatorInfo implements database.Database
+func (db *Impl) GetValidatorInfo(ctx context.Context, validatorAddress common.Address) (*models.ValidatorInfo, error) {
+	var info models.ValidatorInfo
+
+	err := db.session(ctx).Table((&models.ValidatorInfo{}).TableName()).Where("validator_address = ?", validatorAddress).Take(&info).Error
+	if errIsNotFound(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &info, nil
+}
+
+// GetActiveVotingPowers implements database.Database
+func (db *Impl) GetActiveVotingPowers(ctx context.Context) ([]*models.ValidatorVotingPower, error) {
+	var powers []*models.ValidatorVotingPower
+
+	err := db.session(ctx).Table((&models.ValidatorVotingPower{}).TableName()).Where("voting_power > 0").Find(&powers).Error
+	return powers, err
+}
+
+// GetLatestBalance implements database.Database
+func (db *Impl) GetLatestBalance(ctx context.Context, account common.Address, denom string, height int64) (*models.AccountBalance, error) {
+	var balance models.AccountBalance
+
+	err := db.session(ctx).Table((&models.AccountBalance{}).TableName()).
+		Where("account = ? AND denom = ? AND height <= ?", account, denom, height).Order("height DESC").Take(&balance).Error
+	if errIsNotFound(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &balance, nil
+}
+
+// GetAccountBalances implements database.Database
+func (db *Impl) GetAccountBalances(ctx context.Context, account common.Address, height int64) ([]*models.AccountBalance, error) {
+	var balances []*models.AccountBalance
+
+	latest := db.session(ctx).Table((&models.AccountBalance{}).TableName()).Select("denom, MAX(height)").
+		Where("account = ? AND height <= ?", account, height).Group("denom")
+	err := db.session(ctx).Table((&models.AccountBalance{}).TableName()).
+		Where("account = ? AND (denom, height) IN (?)", account, latest).Order("denom").Find(&balances).Error
+	return balances, err
+}
+
+// ListTransfers implements database.Database
+func (db *Impl) ListTransfers(ctx context.Context, account common.Address, startAfter uint64, limit int) ([]*models.Transfer, error) {
+	var transfers []*models.Transfer
+
+	err := db.session(ctx).Table((&models.Transfer{}).TableName()).
+		Where("(from_address = ? OR to_address = ?) AND id > ?", account, account, startAfter).Order("id").Limit(limit).Find(&transfers).Error
+	return transfers, err
+}
+
+// GetStatements implements database.Database
+func (db *Impl) GetStatements(ctx context.Context, policyIDs []common.Hash) ([]*models.Statements, error) {
+	var statements []*models.Statements
+	if len(policyIDs) == 0 {
+		return statements, nil
+	}
+
+	err := db.session(ctx).Table((&models.Statements{}).TableName()).
+		Where("policy_id IN ? AND removed IS NOT TRUE", policyIDs).
+		Find(&statements).Error
+	return statements, err
+}
+
+// GetTxByHash implements database.Database
+func (db *Impl) GetTxByHash(ctx context.Context, hash common.Hash) (*models.Tx, error) {
+	var tx models.Tx
+
+	err := db.session(ctx).Table((&models.Tx{}).TableName()).Where("hash = ?", hash).Take(&tx).Error
+	if errIsNotFound(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &tx, nil
+}
+
+// GetTxsByHeight implements database.Database
+func (db *Impl) GetTxsByHeight(ctx context.Context, height uint64) ([]*models.Tx, error) {
+	var txs []*models.Tx
+
+	err := db.session(ctx).Table((&models.Tx{}).TableName()).Where("height = ?", height).Order("tx_index").Find(&txs).Error
+	return txs, err
+}
+
+// SaveAccountTxs implements database.Database
+func (db *Impl) SaveAccountTxs(ctx context.Context, accountTxs []*models.AccountTx) error {
+	if len(accountTxs) == 0 {
+		return nil
+	}
+	return db.session(ctx).Table((&models.AccountTx{}).TableName()).Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "address"}, {Name: "tx_hash"}, {Name: "msg_index"}},
+		DoNothing: true,
+	}).Create(accountTxs).Error
+}
+
+// GetTxsByAddress implements database.Database
+func (db *Impl) GetTxsByAddress(ctx context.Context, address common.Address, startAfter uint64, limit int) ([]*models.Tx, error) {
+	var txs []*models.Tx
+
+	hashes := db.session(ctx).Table((&models.AccountTx{}).TableName()).Select("tx_hash").Where("address = ?", address)
+	err := db.session(ctx).Table((&models.Tx{}).TableName()).
+		Where("id > ? AND hash IN (?)", startAfter, hashes).Order("id").Limit(limit).Find(&txs).Error
+	return txs, err
+}
+
+// SaveMessages implements database.Database
+func (db *Impl) SaveMessages(ctx context.Context, msgs []*models.Message) error {
+	if len(msgs) == 0 {
+		return nil
+	}
+	return db.session(ctx).Table((&models.Message{}).TableName()).Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "tx_hash"}, {Name: "msg_index"}},
+		UpdateAll: true,
+	}).Create(msgs).Error
+}
+
+// ListMessagesByType implements database.Database
+func (db *Impl) ListMessagesByType(
+	ctx context.Context, msgType string, fromHeight, toHeight int64, startAfter uint64, limit int,
+) ([]*models.Message, error) {
+	var msgs []*models.Message
+
+	err := db.session(ctx).Table((&models.Message{}).TableName()).
+		Where("type = ? AND height BETWEEN ? AND ? AND id > ?", msgType, fromHeight, toHeight, startAfter).
+		Order("id").Limit(limit).Find(&msgs).Error
+	return msgs, err
 }
 
 func (db *Impl) Begin(ctx context.Context) *Impl {
 	return &Impl{
-		Db: db.Db.WithContext(ctx).Begin(),
+		Db:             db.Db.WithContext(ctx).Begin(),
+		EncodingConfig: db.EncodingConfig,
 	}
 }
 
@@ -490,6 +1839,16 @@ func (db *Impl) Rollback() {
 	db.Db.Rollback()
 }
 
+// SavePoint implements database.Database
+func (db *Impl) SavePoint(ctx context.Context, name string) error {
+	return db.session(ctx).SavePoint(name).Error
+}
+
+// RollbackTo implements database.Database
+func (db *Impl) RollbackTo(ctx context.Context, name string) error {
+	return db.session(ctx).RollbackTo(name).Error
+}
+
 func (db *Impl) Commit() error {
 	return db.Db.Commit().Error
 }
@@ -505,36 +1864,35 @@ func (db *Impl) Close() {
 // -------------------------------------------------------------------------------------------------------------------
 
 // GetLastPruned implements database.PruningDb
-func (db *Impl) GetLastPruned() (int64, error) {
+func (db *Impl) GetLastPruned(ctx context.Context) (int64, error) {
 	var lastPrunedHeight int64
-	err := db.Db.Raw(`SELECT coalesce(MAX(last_pruned_height),0) FROM pruning LIMIT 1;`).Scan(&lastPrunedHeight).Error
+	err := db.session(ctx).Raw(`SELECT coalesce(MAX(last_pruned_height),0) FROM pruning LIMIT 1;`).Scan(&lastPrunedHeight).Error
 	return lastPrunedHeight, err
 }
 
 // StoreLastPruned implements database.PruningDb
-func (db *Impl) StoreLastPruned(height int64) error {
-	err := db.Db.Exec(`DELETE FROM pruning`).Error
+func (db *Impl) StoreLastPruned(ctx context.Context, height int64) error {
+	err := db.session(ctx).Exec(`DELETE FROM pruning`).Error
 	if err != nil {
 		return err
 	}
 
-	err = db.Db.Exec(`INSERT INTO pruning (last_pruned_height) VALUES ($1)`, height).Error
+	err = db.session(ctx).Exec(`INSERT INTO pruning (last_pruned_height) VALUES ($1)`, height).Error
 	return err
 }
 
 // Prune implements database.PruningDb
-func (db *Impl) Prune(height int64) error {
-	err := db.Db.Exec(`DELETE FROM pre_commit WHERE height = $1`, height).Error
+func (db *Impl) Prune(ctx context.Context, height int64) error {
+	err := db.session(ctx).Exec(`DELETE FROM pre_commit WHERE height = $1`, height).Error
 	if err != nil {
 		return err
 	}
 
-	err = db.Db.Exec(`
-DELETE FROM message 
-USING transaction 
-WHERE message.transaction_hash = transaction.hash AND transaction.height = $1
-`, height).Error
-	return err
+	// the messages are only stored when the messages module is enabled
+	if !db.session(ctx).Migrator().HasTable(&models.Message{}) {
+		return nil
+	}
+	return db.session(ctx).Table((&models.Message{}).TableName()).Where("height = ?", height).Delete(&models.Message{}).Error
 }
 
 func errIsNotFound(err error) bool {
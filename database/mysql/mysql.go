@@ -1,8 +1,6 @@
 package mysql
 
 import (
-	"context"
-
 	"github.com/forbole/juno/v4/database"
 	"github.com/forbole/juno/v4/database/sqlclient"
 )
@@ -31,20 +29,3 @@ var _ database.Database = &Database{}
 type Database struct {
 	database.Impl
 }
-
-// GetMissingHeights returns a slice of missing block heights between startHeight and endHeight
-func (db *Database) GetMissingHeights(ctx context.Context, startHeight, endHeight uint64) []uint64 {
-	var result []uint64
-	for i := startHeight; i <= endHeight; i++ {
-		exist, _ := db.HasBlock(ctx, i)
-		if !exist {
-			result = append(result, i)
-		}
-	}
-
-	if len(result) == 0 {
-		return nil
-	}
-
-	return result
-}
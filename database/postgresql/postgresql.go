@@ -1,8 +1,6 @@
 package postgresql
 
 import (
-	"context"
-
 	"github.com/forbole/juno/v4/database"
 	"github.com/forbole/juno/v4/database/sqlclient"
 )
@@ -31,19 +29,3 @@ var _ database.Database = &Database{}
 type Database struct {
 	database.Impl
 }
-
-// GetMissingHeights returns a slice of missing block heights between startHeight and endHeight
-func (db *Database) GetMissingHeights(ctx context.Context, startHeight, endHeight uint64) []uint64 {
-	var result []uint64
-	stmt := `SELECT generate_series($1::int,$2::int) EXCEPT SELECT height FROM blocks ORDER BY 1;`
-	err := db.Db.Select(&result, stmt, startHeight, endHeight)
-	if err != nil {
-		return nil
-	}
-
-	if len(result) == 0 {
-		return nil
-	}
-
-	return result
-}
@@ -0,0 +1,112 @@
+package database
+
+import (
+	"context"
+	"fmt"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"github.com/forbole/juno/v4/common"
+	"github.com/forbole/juno/v4/models"
+)
+
+// AddBucketUsage implements database.Database
+func (db *Impl) AddBucketUsage(ctx context.Context, usage *models.BucketUsage) error {
+	return db.addUsage(ctx, usage, usage.TableName(), "bucket_id", &usage.StorageUsage)
+}
+
+// AddOwnerUsage implements database.Database
+func (db *Impl) AddOwnerUsage(ctx context.Context, usage *models.OwnerUsage) error {
+	return db.addUsage(ctx, usage, usage.TableName(), "owner_address", &usage.StorageUsage)
+}
+
+// AddSpUsage implements database.Database
+func (db *Impl) AddSpUsage(ctx context.Context, usage *models.SpUsage) error {
+	return db.addUsage(ctx, usage, usage.TableName(), "sp_address", &usage.StorageUsage)
+}
+
+// addUsage inserts the given row, or adds its counts to the existing row having the same key column
+func (db *Impl) addUsage(ctx context.Context, row interface{}, table string, keyColumn string, usage *models.StorageUsage) error {
+	increment := func(column string, value int64) clause.Expr {
+		return gorm.Expr(fmt.Sprintf("%s.%s + ?", table, column), value)
+	}
+
+	return db.session(ctx).Table(table).Clauses(clause.OnConflict{
+		Columns: []clause.Column{{Name: keyColumn}},
+		DoUpdates: clause.Assignments(map[string]interface{}{
+			"sealed_count":  increment("sealed_count", usage.SealedCount),
+			"sealed_size":   increment("sealed_size", usage.SealedSize),
+			"pending_count": increment("pending_count", usage.PendingCount),
+			"pending_size":  increment("pending_size", usage.PendingSize),
+			"removed_count": increment("removed_count", usage.RemovedCount),
+			"removed_size":  increment("removed_size", usage.RemovedSize),
+			"update_at":     usage.UpdateAt,
+		}),
+	}).Create(row).Error
+}
+
+// GetBucketUsage implements database.Database
+func (db *Impl) GetBucketUsage(ctx context.Context, bucketID common.Hash) (*models.BucketUsage, error) {
+	var usage models.BucketUsage
+	return &usage, db.getUsage(ctx, &usage, usage.TableName(), "bucket_id", bucketID)
+}
+
+// GetOwnerUsage implements database.Database
+func (db *Impl) GetOwnerUsage(ctx context.Context, owner common.Address) (*models.OwnerUsage, error) {
+	var usage models.OwnerUsage
+	return &usage, db.getUsage(ctx, &usage, usage.TableName(), "owner_address", owner)
+}
+
+// GetSpUsage implements database.Database
+func (db *Impl) GetSpUsage(ctx context.Context, spAddress common.Address) (*models.SpUsage, error) {
+	var usage models.SpUsage
+	return &usage, db.getUsage(ctx, &usage, usage.TableName(), "sp_address", spAddress)
+}
+
+// getUsage reads the row having the given key into usage, leaving it empty if the row does not exist
+func (db *Impl) getUsage(ctx context.Context, usage interface{}, table string, keyColumn string, key interface{}) error {
+	err := db.session(ctx).Table(table).Where(fmt.Sprintf("%s = ?", keyColumn), key).Take(usage).Error
+	if errIsNotFound(err) {
+		return nil
+	}
+	return err
+}
+
+// RebuildStorageUsage implements database.Database
+func (db *Impl) RebuildStorageUsage(ctx context.Context) error {
+	err := db.rebuildUsage(ctx, (&models.BucketUsage{}).TableName(), "bucket_id", "bucket_id")
+	if err != nil {
+		return err
+	}
+
+	err = db.rebuildUsage(ctx, (&models.OwnerUsage{}).TableName(), "owner_address", "owner_address")
+	if err != nil {
+		return err
+	}
+
+	return db.rebuildUsage(ctx, (&models.SpUsage{}).TableName(), "sp_address", "primary_sp_address")
+}
+
+// rebuildUsage replaces all the rows of the given usage table with the ones computed grouping the objects
+// by the given object column, which is stored inside the key column
+func (db *Impl) rebuildUsage(ctx context.Context, table string, keyColumn string, objectColumn string) error {
+	err := db.session(ctx).Exec(fmt.Sprintf("DELETE FROM %s", table)).Error
+	if err != nil {
+		return err
+	}
+
+	stmt := fmt.Sprintf(`INSERT INTO %[1]s (%[2]s, sealed_count, sealed_size, pending_count, pending_size, removed_count, removed_size, update_at)
+SELECT %[3]s,
+	SUM(CASE WHEN removed THEN 0 WHEN status = @sealed THEN 1 ELSE 0 END),
+	SUM(CASE WHEN removed THEN 0 WHEN status = @sealed THEN payload_size ELSE 0 END),
+	SUM(CASE WHEN removed THEN 0 WHEN status = @sealed THEN 0 ELSE 1 END),
+	SUM(CASE WHEN removed THEN 0 WHEN status = @sealed THEN 0 ELSE payload_size END),
+	SUM(CASE WHEN removed THEN 1 ELSE 0 END),
+	SUM(CASE WHEN removed THEN payload_size ELSE 0 END),
+	MAX(update_at)
+FROM %[4]s
+GROUP BY %[3]s`, table, keyColumn, objectColumn, (&models.Object{}).TableName())
+
+	return db.session(ctx).Exec(stmt, map[string]interface{}{"sealed": models.ObjectStatusSealed}).Error
+}
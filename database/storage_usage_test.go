@@ -0,0 +1,76 @@
+package database
+
+import (
+	"context"
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+
+	"github.com/forbole/juno/v4/common"
+	"github.com/forbole/juno/v4/models"
+)
+
+func TestRebuildStorageUsage(t *testing.T) {
+	gormDB, err := gorm.Open(sqlite.Open("file::memory:"), &gorm.Config{Logger: logger.Default.LogMode(logger.Silent)})
+	require.NoError(t, err)
+	require.NoError(t, gormDB.AutoMigrate(&models.Object{}, &models.BucketUsage{}, &models.OwnerUsage{}, &models.SpUsage{}))
+
+	db := &Impl{Db: gormDB}
+	ctx := context.Background()
+
+	bucket1, bucket2 := common.HexToHash("0x01"), common.HexToHash("0x02")
+	owner := common.HexToAddress("0x03")
+	sp := common.HexToAddress("0x04")
+	for i, object := range []*models.Object{
+		{BucketID: bucket1, PayloadSize: 10, Status: models.ObjectStatusSealed, UpdateAt: 1},
+		{BucketID: bucket1, PayloadSize: 20, Status: "OBJECT_STATUS_CREATED", UpdateAt: 3},
+		{BucketID: bucket1, PayloadSize: 30, Status: models.ObjectStatusSealed, Removed: true, UpdateAt: 2},
+		{BucketID: bucket2, PayloadSize: 40, Status: models.ObjectStatusSealed, UpdateAt: 5},
+	} {
+		object.ObjectID = common.BigToHash(big.NewInt(int64(i + 1)))
+		object.ObjectName = object.ObjectID.Hex()
+		object.OwnerAddress = owner
+		object.PrimarySpAddress = sp
+		require.NoError(t, gormDB.Create(object).Error)
+	}
+
+	// the stale rows are replaced
+	require.NoError(t, db.AddBucketUsage(ctx, &models.BucketUsage{
+		BucketID: common.HexToHash("0x05"), StorageUsage: models.StorageUsage{SealedCount: 7},
+	}))
+	require.NoError(t, db.AddBucketUsage(ctx, &models.BucketUsage{
+		BucketID: bucket1, StorageUsage: models.StorageUsage{SealedCount: 7},
+	}))
+
+	require.NoError(t, db.RebuildStorageUsage(ctx))
+
+	var bucketUsages []*models.BucketUsage
+	require.NoError(t, gormDB.Order("update_at").Find(&bucketUsages).Error)
+	require.Len(t, bucketUsages, 2)
+	require.Equal(t, bucket1, bucketUsages[0].BucketID)
+	require.Equal(t, models.StorageUsage{
+		SealedCount: 1, SealedSize: 10,
+		PendingCount: 1, PendingSize: 20,
+		RemovedCount: 1, RemovedSize: 30,
+		UpdateAt: 3,
+	}, bucketUsages[0].StorageUsage)
+	require.Equal(t, bucket2, bucketUsages[1].BucketID)
+	require.Equal(t, models.StorageUsage{SealedCount: 1, SealedSize: 40, UpdateAt: 5}, bucketUsages[1].StorageUsage)
+
+	ownerUsage, err := db.GetOwnerUsage(ctx, owner)
+	require.NoError(t, err)
+	require.Equal(t, models.StorageUsage{
+		SealedCount: 2, SealedSize: 50,
+		PendingCount: 1, PendingSize: 20,
+		RemovedCount: 1, RemovedSize: 30,
+		UpdateAt: 5,
+	}, ownerUsage.StorageUsage)
+
+	spUsage, err := db.GetSpUsage(ctx, sp)
+	require.NoError(t, err)
+	require.Equal(t, ownerUsage.StorageUsage, spUsage.StorageUsage)
+}
@@ -0,0 +1,272 @@
+package database
+
+import (
+	"context"
+	"fmt"
+
+	"gorm.io/gorm/clause"
+
+	"github.com/forbole/juno/v4/log"
+	"github.com/forbole/juno/v4/models"
+)
+
+// getSyncProgress returns the stored sync progress, or nil if it has not been stored yet.
+// If lock is true, the row is locked until the end of the current transaction.
+func (db *Impl) getSyncProgress(ctx context.Context, lock bool) (*models.SyncProgress, error) {
+	var progress models.SyncProgress
+
+	query := db.session(ctx).Table((&models.SyncProgress{}).TableName())
+	if lock {
+		query = query.Clauses(clause.Locking{Strength: "UPDATE"})
+	}
+
+	err := query.Where("id = ?", models.SyncProgressID).Take(&progress).Error
+	if errIsNotFound(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &progress, nil
+}
+
+// buildSyncProgress builds the sync progress from the blocks stored inside the database, scanning them once.
+// If no block has been stored yet, nil is returned instead.
+func (db *Impl) buildSyncProgress(ctx context.Context) (*models.SyncProgress, error) {
+	var bounds struct {
+		LowestHeight  uint64
+		HighestHeight uint64
+		Count         int64
+	}
+	err := db.session(ctx).Table((&models.Block{}).TableName()).
+		Select("MIN(height) AS lowest_height, MAX(height) AS highest_height, COUNT(*) AS count").
+		Scan(&bounds).Error
+	if err != nil {
+		return nil, err
+	}
+	if bounds.Count == 0 {
+		return nil, nil
+	}
+
+	var gaps []*models.SyncGap
+	err = db.session(ctx).Raw(`
+SELECT height + 1 AS start_height, next_height - 1 AS end_height
+FROM (SELECT height, LEAD(height) OVER (ORDER BY height) AS next_height FROM blocks) AS heights
+WHERE next_height > height + 1
+ORDER BY height`).Scan(&gaps).Error
+	if err != nil {
+		return nil, err
+	}
+
+	progress := &models.SyncProgress{
+		ID:            models.SyncProgressID,
+		LowestHeight:  bounds.LowestHeight,
+		Watermark:     bounds.HighestHeight,
+		HighestHeight: bounds.HighestHeight,
+	}
+	if len(gaps) > 0 {
+		progress.Watermark = gaps[0].StartHeight - 1
+	}
+
+	res := db.session(ctx).Table((&models.SyncProgress{}).TableName()).
+		Clauses(clause.OnConflict{DoNothing: true}).
+		Create(progress)
+	if res.Error != nil {
+		return nil, res.Error
+	}
+
+	// Someone else built the progress in the meantime
+	if res.RowsAffected == 0 {
+		return db.getSyncProgress(ctx, false)
+	}
+
+	if len(gaps) > 0 {
+		err = db.session(ctx).Table((&models.SyncGap{}).TableName()).Create(gaps).Error
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	log.Infow("built sync progress", "lowest_height", progress.LowestHeight,
+		"highest_height", progress.HighestHeight, "gaps", len(gaps))
+	return progress, nil
+}
+
+// saveSyncProgress updates the stored sync progress, recomputing its watermark from the remaining gaps
+func (db *Impl) saveSyncProgress(ctx context.Context, progress *models.SyncProgress) error {
+	var firstGap models.SyncGap
+	err := db.session(ctx).Table((&models.SyncGap{}).TableName()).Order("start_height").Take(&firstGap).Error
+	switch {
+	case errIsNotFound(err):
+		progress.Watermark = progress.HighestHeight
+	case err != nil:
+		return err
+	default:
+		progress.Watermark = firstGap.StartHeight - 1
+	}
+
+	return db.session(ctx).Table((&models.SyncProgress{}).TableName()).Save(progress).Error
+}
+
+// UpdateSyncProgress implements database.Database
+func (db *Impl) UpdateSyncProgress(ctx context.Context, height uint64) error {
+	progress, err := db.getSyncProgress(ctx, true)
+	if err != nil {
+		return err
+	}
+
+	// The first time, the progress is built from the stored blocks, which already include the given height
+	if progress == nil {
+		_, err = db.buildSyncProgress(ctx)
+		return err
+	}
+
+	gaps := db.session(ctx).Table((&models.SyncGap{}).TableName())
+	switch {
+	case height > progress.HighestHeight:
+		if height > progress.HighestHeight+1 {
+			err = gaps.Create(&models.SyncGap{StartHeight: progress.HighestHeight + 1, EndHeight: height - 1}).Error
+			if err != nil {
+				return err
+			}
+		}
+		progress.HighestHeight = height
+
+	case height < progress.LowestHeight:
+		if height < progress.LowestHeight-1 {
+			err = gaps.Create(&models.SyncGap{StartHeight: height + 1, EndHeight: progress.LowestHeight - 1}).Error
+			if err != nil {
+				return err
+			}
+		}
+		progress.LowestHeight = height
+
+	default:
+		var gap models.SyncGap
+		err = db.session(ctx).Table((&models.SyncGap{}).TableName()).
+			Where("start_height <= ? AND end_height >= ?", height, height).
+			Take(&gap).Error
+		if errIsNotFound(err) {
+			// The height has already been processed before
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		err = db.session(ctx).Table((&models.SyncGap{}).TableName()).Delete(&gap).Error
+		if err != nil {
+			return err
+		}
+
+		var split []*models.SyncGap
+		if gap.StartHeight < height {
+			split = append(split, &models.SyncGap{StartHeight: gap.StartHeight, EndHeight: height - 1})
+		}
+		if height < gap.EndHeight {
+			split = append(split, &models.SyncGap{StartHeight: height + 1, EndHeight: gap.EndHeight})
+		}
+		if len(split) > 0 {
+			err = db.session(ctx).Table((&models.SyncGap{}).TableName()).Create(split).Error
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	return db.saveSyncProgress(ctx, progress)
+}
+
+// truncateSyncProgress removes from the sync progress all the heights greater than the given one
+func (db *Impl) truncateSyncProgress(ctx context.Context, height uint64) error {
+	progress, err := db.getSyncProgress(ctx, true)
+	if err != nil {
+		return err
+	}
+	if progress == nil || progress.HighestHeight <= height {
+		return nil
+	}
+
+	if height < progress.LowestHeight {
+		err = db.session(ctx).Table((&models.SyncGap{}).TableName()).Where("1 = 1").Delete(&models.SyncGap{}).Error
+		if err != nil {
+			return err
+		}
+		return db.session(ctx).Table((&models.SyncProgress{}).TableName()).Delete(progress).Error
+	}
+
+	err = db.session(ctx).Table((&models.SyncGap{}).TableName()).Where("start_height > ?", height).Delete(&models.SyncGap{}).Error
+	if err != nil {
+		return err
+	}
+
+	// If the new highest height falls inside a gap, the highest processed height is the one preceding it
+	progress.HighestHeight = height
+	var gap models.SyncGap
+	err = db.session(ctx).Table((&models.SyncGap{}).TableName()).Where("end_height >= ?", height).Take(&gap).Error
+	switch {
+	case errIsNotFound(err):
+	case err != nil:
+		return err
+	default:
+		err = db.session(ctx).Table((&models.SyncGap{}).TableName()).Delete(&gap).Error
+		if err != nil {
+			return err
+		}
+		progress.HighestHeight = gap.StartHeight - 1
+	}
+
+	return db.saveSyncProgress(ctx, progress)
+}
+
+// GetMissingHeights implements database.Database
+func (db *Impl) GetMissingHeights(ctx context.Context, startHeight, endHeight uint64) ([]*models.SyncGap, error) {
+	progress, err := db.getSyncProgress(ctx, false)
+	if err == nil && progress == nil {
+		progress, err = db.buildSyncProgress(ctx)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get sync progress: %s", err)
+	}
+
+	var result []*models.SyncGap
+	appendRange := func(from, to uint64) {
+		if from < startHeight {
+			from = startHeight
+		}
+		if to > endHeight {
+			to = endHeight
+		}
+		if from <= to {
+			result = append(result, &models.SyncGap{StartHeight: from, EndHeight: to})
+		}
+	}
+
+	// Nothing has been stored yet
+	if progress == nil {
+		appendRange(startHeight, endHeight)
+		return result, nil
+	}
+
+	if startHeight < progress.LowestHeight {
+		appendRange(startHeight, progress.LowestHeight-1)
+	}
+
+	var gaps []*models.SyncGap
+	err = db.session(ctx).Table((&models.SyncGap{}).TableName()).
+		Where("end_height >= ? AND start_height <= ?", startHeight, endHeight).
+		Order("start_height").
+		Find(&gaps).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to get sync gaps: %s", err)
+	}
+	for _, gap := range gaps {
+		appendRange(gap.StartHeight, gap.EndHeight)
+	}
+
+	if endHeight > progress.HighestHeight {
+		appendRange(progress.HighestHeight+1, endHeight)
+	}
+
+	return result, nil
+}
@@ -0,0 +1,262 @@
+package database
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+
+	"github.com/forbole/juno/v4/models"
+)
+
+// gap is a shorthand to build the expected sync gaps
+type gap struct {
+	start, end uint64
+}
+
+// newSyncProgressDB returns a database holding the given sync progress and gaps
+func newSyncProgressDB(t *testing.T, progress *models.SyncProgress, gaps []gap) *Impl {
+	t.Helper()
+
+	gormDB, err := gorm.Open(sqlite.Open("file::memory:"), &gorm.Config{Logger: logger.Default.LogMode(logger.Silent)})
+	require.NoError(t, err)
+	require.NoError(t, gormDB.AutoMigrate(&models.Block{}, &models.SyncProgress{}, &models.SyncGap{}))
+
+	db := &Impl{Db: gormDB}
+	if progress != nil {
+		progress.ID = models.SyncProgressID
+		require.NoError(t, gormDB.Create(progress).Error)
+	}
+	for _, g := range gaps {
+		require.NoError(t, gormDB.Create(&models.SyncGap{StartHeight: g.start, EndHeight: g.end}).Error)
+	}
+	return db
+}
+
+// requireSyncProgress checks that the database holds the given sync progress and gaps
+func requireSyncProgress(t *testing.T, db *Impl, expected *models.SyncProgress, expectedGaps []gap) {
+	t.Helper()
+
+	progress, err := db.getSyncProgress(context.Background(), false)
+	require.NoError(t, err)
+	if expected == nil {
+		require.Nil(t, progress)
+	} else {
+		expected.ID = models.SyncProgressID
+		require.Equal(t, expected, progress)
+	}
+
+	var gaps []*models.SyncGap
+	require.NoError(t, db.Db.Order("start_height").Find(&gaps).Error)
+	actualGaps := make([]gap, len(gaps))
+	for i, g := range gaps {
+		actualGaps[i] = gap{start: g.StartHeight, end: g.EndHeight}
+	}
+	if expectedGaps == nil {
+		expectedGaps = []gap{}
+	}
+	require.Equal(t, expectedGaps, actualGaps)
+}
+
+func TestUpdateSyncProgress(t *testing.T) {
+	testCases := []struct {
+		name         string
+		progress     *models.SyncProgress
+		gaps         []gap
+		height       uint64
+		expected     *models.SyncProgress
+		expectedGaps []gap
+	}{
+		{
+			name:     "next height",
+			progress: &models.SyncProgress{LowestHeight: 10, Watermark: 10, HighestHeight: 10},
+			height:   11,
+			expected: &models.SyncProgress{LowestHeight: 10, Watermark: 11, HighestHeight: 11},
+		},
+		{
+			name:         "height past the highest one",
+			progress:     &models.SyncProgress{LowestHeight: 10, Watermark: 10, HighestHeight: 10},
+			height:       15,
+			expected:     &models.SyncProgress{LowestHeight: 10, Watermark: 10, HighestHeight: 15},
+			expectedGaps: []gap{{11, 14}},
+		},
+		{
+			name:         "height splitting a gap",
+			progress:     &models.SyncProgress{LowestHeight: 10, Watermark: 10, HighestHeight: 15},
+			gaps:         []gap{{11, 14}},
+			height:       12,
+			expected:     &models.SyncProgress{LowestHeight: 10, Watermark: 10, HighestHeight: 15},
+			expectedGaps: []gap{{11, 11}, {13, 14}},
+		},
+		{
+			name:         "height starting a gap",
+			progress:     &models.SyncProgress{LowestHeight: 10, Watermark: 10, HighestHeight: 15},
+			gaps:         []gap{{11, 14}},
+			height:       11,
+			expected:     &models.SyncProgress{LowestHeight: 10, Watermark: 11, HighestHeight: 15},
+			expectedGaps: []gap{{12, 14}},
+		},
+		{
+			name:         "height ending a gap",
+			progress:     &models.SyncProgress{LowestHeight: 10, Watermark: 10, HighestHeight: 20},
+			gaps:         []gap{{11, 14}, {17, 18}},
+			height:       14,
+			expected:     &models.SyncProgress{LowestHeight: 10, Watermark: 10, HighestHeight: 20},
+			expectedGaps: []gap{{11, 13}, {17, 18}},
+		},
+		{
+			name:         "height filling the first gap",
+			progress:     &models.SyncProgress{LowestHeight: 10, Watermark: 10, HighestHeight: 20},
+			gaps:         []gap{{11, 11}, {17, 18}},
+			height:       11,
+			expected:     &models.SyncProgress{LowestHeight: 10, Watermark: 16, HighestHeight: 20},
+			expectedGaps: []gap{{17, 18}},
+		},
+		{
+			name:     "height filling the last gap",
+			progress: &models.SyncProgress{LowestHeight: 10, Watermark: 10, HighestHeight: 15},
+			gaps:     []gap{{11, 11}},
+			height:   11,
+			expected: &models.SyncProgress{LowestHeight: 10, Watermark: 15, HighestHeight: 15},
+		},
+		{
+			name:         "height before the lowest one",
+			progress:     &models.SyncProgress{LowestHeight: 10, Watermark: 10, HighestHeight: 10},
+			height:       7,
+			expected:     &models.SyncProgress{LowestHeight: 7, Watermark: 7, HighestHeight: 10},
+			expectedGaps: []gap{{8, 9}},
+		},
+		{
+			name:     "height preceding the lowest one",
+			progress: &models.SyncProgress{LowestHeight: 10, Watermark: 10, HighestHeight: 10},
+			height:   9,
+			expected: &models.SyncProgress{LowestHeight: 9, Watermark: 10, HighestHeight: 10},
+		},
+		{
+			name:         "height already processed",
+			progress:     &models.SyncProgress{LowestHeight: 10, Watermark: 10, HighestHeight: 15},
+			gaps:         []gap{{11, 14}},
+			height:       15,
+			expected:     &models.SyncProgress{LowestHeight: 10, Watermark: 10, HighestHeight: 15},
+			expectedGaps: []gap{{11, 14}},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			db := newSyncProgressDB(t, tc.progress, tc.gaps)
+			require.NoError(t, db.UpdateSyncProgress(context.Background(), tc.height))
+			requireSyncProgress(t, db, tc.expected, tc.expectedGaps)
+		})
+	}
+}
+
+func TestTruncateSyncProgress(t *testing.T) {
+	testCases := []struct {
+		name         string
+		progress     *models.SyncProgress
+		gaps         []gap
+		height       uint64
+		expected     *models.SyncProgress
+		expectedGaps []gap
+	}{
+		{
+			name:         "height after the highest one",
+			progress:     &models.SyncProgress{LowestHeight: 10, Watermark: 10, HighestHeight: 15},
+			gaps:         []gap{{11, 12}},
+			height:       20,
+			expected:     &models.SyncProgress{LowestHeight: 10, Watermark: 10, HighestHeight: 15},
+			expectedGaps: []gap{{11, 12}},
+		},
+		{
+			name:         "processed height",
+			progress:     &models.SyncProgress{LowestHeight: 10, Watermark: 10, HighestHeight: 20},
+			gaps:         []gap{{11, 12}, {16, 18}},
+			height:       14,
+			expected:     &models.SyncProgress{LowestHeight: 10, Watermark: 10, HighestHeight: 14},
+			expectedGaps: []gap{{11, 12}},
+		},
+		{
+			name:     "height inside a gap",
+			progress: &models.SyncProgress{LowestHeight: 10, Watermark: 10, HighestHeight: 20},
+			gaps:     []gap{{11, 14}, {16, 18}},
+			height:   12,
+			expected: &models.SyncProgress{LowestHeight: 10, Watermark: 10, HighestHeight: 10},
+		},
+		{
+			name:     "height before the lowest one",
+			progress: &models.SyncProgress{LowestHeight: 10, Watermark: 10, HighestHeight: 20},
+			gaps:     []gap{{11, 14}},
+			height:   5,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			db := newSyncProgressDB(t, tc.progress, tc.gaps)
+			require.NoError(t, db.truncateSyncProgress(context.Background(), tc.height))
+			requireSyncProgress(t, db, tc.expected, tc.expectedGaps)
+		})
+	}
+}
+
+func TestGetMissingHeights(t *testing.T) {
+	testCases := []struct {
+		name        string
+		progress    *models.SyncProgress
+		gaps        []gap
+		startHeight uint64
+		endHeight   uint64
+		expected    []gap
+	}{
+		{
+			name:        "nothing stored",
+			startHeight: 1,
+			endHeight:   100,
+			expected:    []gap{{1, 100}},
+		},
+		{
+			name:        "range around the processed heights",
+			progress:    &models.SyncProgress{LowestHeight: 10, Watermark: 11, HighestHeight: 20},
+			gaps:        []gap{{12, 13}, {16, 18}},
+			startHeight: 5,
+			endHeight:   25,
+			expected:    []gap{{5, 9}, {12, 13}, {16, 18}, {21, 25}},
+		},
+		{
+			name:        "range cutting the gaps",
+			progress:    &models.SyncProgress{LowestHeight: 10, Watermark: 11, HighestHeight: 20},
+			gaps:        []gap{{12, 13}, {16, 18}},
+			startHeight: 13,
+			endHeight:   17,
+			expected:    []gap{{13, 13}, {16, 17}},
+		},
+		{
+			name:        "processed range",
+			progress:    &models.SyncProgress{LowestHeight: 10, Watermark: 11, HighestHeight: 20},
+			gaps:        []gap{{12, 13}, {16, 18}},
+			startHeight: 14,
+			endHeight:   15,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			db := newSyncProgressDB(t, tc.progress, tc.gaps)
+			missing, err := db.GetMissingHeights(context.Background(), tc.startHeight, tc.endHeight)
+			require.NoError(t, err)
+
+			actual := make([]gap, len(missing))
+			for i, g := range missing {
+				actual[i] = gap{start: g.StartHeight, end: g.EndHeight}
+			}
+			if tc.expected == nil {
+				tc.expected = []gap{}
+			}
+			require.Equal(t, tc.expected, actual)
+		})
+	}
+}
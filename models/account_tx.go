@@ -0,0 +1,20 @@
+package models
+
+import "github.com/forbole/juno/v4/common"
+
+// AccountTx represents the involvement of an address in a message of a transaction, as returned by the
+// MessageAddressesParser of the messages module. Validator operator addresses are stored as any other address.
+type AccountTx struct {
+	ID uint64 `gorm:"column:id;primaryKey" json:"-"`
+
+	Address  common.Address `gorm:"column:address;type:BINARY(20);not null;uniqueIndex:idx_account_tx_address_tx_msg,priority:1"`
+	TxHash   common.Hash    `gorm:"column:tx_hash;type:BINARY(32);not null;uniqueIndex:idx_account_tx_address_tx_msg,priority:2"`
+	MsgIndex int            `gorm:"column:msg_index;not null;uniqueIndex:idx_account_tx_address_tx_msg,priority:3"`
+	MsgType  string         `gorm:"column:msg_type;type:varchar(128);not null"`
+
+	Height int64 `gorm:"column:height;not null;index:idx_account_tx_height"`
+}
+
+func (*AccountTx) TableName() string {
+	return "account_txs"
+}
@@ -0,0 +1,48 @@
+package models
+
+import "github.com/forbole/juno/v4/common"
+
+const (
+	// TransferCoinSpent marks the coins leaving the from address, whatever their destination
+	TransferCoinSpent = "coin_spent"
+	// TransferCoinReceived marks the coins reaching the to address, whatever their origin
+	TransferCoinReceived = "coin_received"
+	// TransferTransfer marks the coins moved from the from address to the to address
+	TransferTransfer = "transfer"
+)
+
+// Transfer represents a coin_spent, coin_received or transfer event of the x/bank module, one row for each denom
+// of its amount. Coin spent events only have a from address and coin received events only a to address,
+// while transfer events of multi-sends have no from address. Events emitted at the beginning or at the end
+// of the block, such as the ones of the fees distribution, have an empty TxHash.
+type Transfer struct {
+	ID uint64 `gorm:"column:id;primaryKey"`
+
+	Kind        string         `gorm:"column:kind;type:varchar(16);not null"`
+	FromAddress common.Address `gorm:"column:from_address;type:BINARY(20);index:idx_transfer_from_address"`
+	ToAddress   common.Address `gorm:"column:to_address;type:BINARY(20);index:idx_transfer_to_address"`
+	Denom       string         `gorm:"column:denom;type:varchar(128);not null"`
+	Amount      *common.Big    `gorm:"column:amount"`
+
+	Height int64       `gorm:"column:height;not null;index:idx_transfer_height"`
+	TxHash common.Hash `gorm:"column:tx_hash;type:BINARY(32)"`
+}
+
+func (*Transfer) TableName() string {
+	return "transfers"
+}
+
+// AccountBalance represents the balance of an account in a denom at the end of a height it changed at.
+// The balance at any height is the one of the latest snapshot at or before that height.
+type AccountBalance struct {
+	ID uint64 `gorm:"column:id;primaryKey"`
+
+	Account common.Address `gorm:"column:account;type:BINARY(20);not null;uniqueIndex:idx_account_balance_account_denom_height,priority:1"`
+	Denom   string         `gorm:"column:denom;type:varchar(128);not null;uniqueIndex:idx_account_balance_account_denom_height,priority:2"`
+	Height  int64          `gorm:"column:height;not null;uniqueIndex:idx_account_balance_account_denom_height,priority:3;index:idx_account_balance_height"`
+	Amount  *common.Big    `gorm:"column:amount"`
+}
+
+func (*AccountBalance) TableName() string {
+	return "account_balances"
+}
@@ -27,3 +27,22 @@ type Bucket struct {
 func (*Bucket) TableName() string {
 	return "buckets"
 }
+
+// BucketQuotaChange represents a change of the read quota charged to a bucket, appended each time the quota is
+// updated so that the charged quota of a bucket can be followed over time.
+type BucketQuotaChange struct {
+	ID uint64 `gorm:"column:id;primaryKey"`
+
+	BucketID               common.Hash    `gorm:"column:bucket_id;type:BINARY(32);index:idx_bucket_quota_change_bucket_id"`
+	ChargedReadQuotaBefore uint64         `gorm:"column:charged_read_quota_before"`
+	ChargedReadQuotaAfter  uint64         `gorm:"column:charged_read_quota_after"`
+	OperatorAddress        common.Address `gorm:"column:operator_address;type:BINARY(20)"`
+
+	Height     int64       `gorm:"column:height;index:idx_bucket_quota_change_height"`
+	TxHash     common.Hash `gorm:"column:tx_hash;type:BINARY(32);not null"`
+	UpdateTime int64       `gorm:"column:update_time"` // seconds
+}
+
+func (*BucketQuotaChange) TableName() string {
+	return "bucket_quota_changes"
+}
@@ -0,0 +1,73 @@
+package models
+
+import "github.com/forbole/juno/v4/common"
+
+const (
+	// CrossChainPackageSend marks the packages sent by Greenfield to the destination chain
+	CrossChainPackageSend = "send"
+	// CrossChainPackageReceive marks the packages received by Greenfield from the source chain and claimed by the relayers
+	CrossChainPackageReceive = "receive"
+
+	MirrorStatusPending = "pending"
+	MirrorStatusSuccess = "success"
+	MirrorStatusFailed  = "failed"
+)
+
+// CrossChainPackage represents a cross-chain package sent or received by Greenfield.
+// The sequence is the one of the channel on the sending chain, so packages are unique by direction, chains,
+// channel and sequence. PackageType is the type of the package: 0 for SYN, 1 for ACK and 2 for FAIL_ACK.
+// Payload is only known for the sent packages, while Crash, ErrorMsg and AckSequence are only known for
+// the received ones, AckSequence being the sequence of the ACK or FAIL_ACK package sent in response, or -1.
+type CrossChainPackage struct {
+	ID uint64 `gorm:"column:id;primaryKey"`
+
+	Direction     string `gorm:"column:direction;type:varchar(8);uniqueIndex:idx_cross_chain_package_sequence,priority:1"`
+	SrcChainID    uint32 `gorm:"column:src_chain_id;uniqueIndex:idx_cross_chain_package_sequence,priority:2"`
+	DestChainID   uint32 `gorm:"column:dest_chain_id;uniqueIndex:idx_cross_chain_package_sequence,priority:3"`
+	ChannelID     uint32 `gorm:"column:channel_id;uniqueIndex:idx_cross_chain_package_sequence,priority:4"`
+	Sequence      uint64 `gorm:"column:sequence;uniqueIndex:idx_cross_chain_package_sequence,priority:5"`
+	PackageType   uint32 `gorm:"column:package_type"`
+	Timestamp     uint64 `gorm:"column:timestamp"`                         // seconds
+	Payload       string `gorm:"column:payload;type:text"`                 // hex
+	RelayerFee    string `gorm:"column:relayer_fee;type:varchar(128)"`     // decimal, in wei
+	AckRelayerFee string `gorm:"column:ack_relayer_fee;type:varchar(128)"` // decimal, in wei
+	AckSequence   int64  `gorm:"column:ack_sequence"`
+	Crash         bool   `gorm:"column:crash"`
+	ErrorMsg      string `gorm:"column:error_msg;type:text"`
+
+	Height int64       `gorm:"column:height;index:idx_cross_chain_package_height"`
+	TxHash common.Hash `gorm:"column:tx_hash;type:BINARY(32);index:idx_cross_chain_package_tx_hash"`
+}
+
+func (*CrossChainPackage) TableName() string {
+	return "cross_chain_packages"
+}
+
+// Mirror represents the latest request to mirror a bucket, an object or a group to the destination chain.
+// ResourceType is the name of the resource.ResourceType, and BucketName is only set for objects.
+// DestChainID, ChannelID and Sequence identify the SYN package sent by the request, while the update fields
+// are set once the ACK package carrying the result of the mirroring is claimed.
+type Mirror struct {
+	ID uint64 `gorm:"column:id;primaryKey"`
+
+	ResourceType    string         `gorm:"column:resource_type;type:varchar(32);uniqueIndex:idx_mirror_resource,priority:1"`
+	ResourceID      common.Hash    `gorm:"column:resource_id;type:BINARY(32);uniqueIndex:idx_mirror_resource,priority:2"`
+	ResourceName    string         `gorm:"column:resource_name;type:varchar(1024)"`
+	BucketName      string         `gorm:"column:bucket_name;type:varchar(64)"`
+	OperatorAddress common.Address `gorm:"column:operator_address;type:BINARY(20)"`
+	Status          string         `gorm:"column:status;type:varchar(8)"`
+	DestChainID     uint32         `gorm:"column:dest_chain_id"`
+	ChannelID       uint32         `gorm:"column:channel_id"`
+	Sequence        uint64         `gorm:"column:sequence"`
+
+	CreateAt     int64       `gorm:"column:create_at"`
+	CreateTxHash common.Hash `gorm:"column:create_tx_hash;type:BINARY(32)"`
+	CreateTime   int64       `gorm:"column:create_time"` // seconds
+	UpdateAt     int64       `gorm:"column:update_at"`
+	UpdateTxHash common.Hash `gorm:"column:update_tx_hash;type:BINARY(32)"`
+	UpdateTime   int64       `gorm:"column:update_time"` // seconds
+}
+
+func (*Mirror) TableName() string {
+	return "mirrors"
+}
@@ -0,0 +1,13 @@
+package models
+
+// FailedHeight represents a height that could not be processed after exhausting all its retries
+type FailedHeight struct {
+	Height    uint64 `gorm:"column:height;primaryKey;autoIncrement:false"`
+	Attempts  uint   `gorm:"column:attempts"`
+	LastError string `gorm:"column:last_error;type:TEXT"`
+	UpdateAt  int64  `gorm:"column:update_at"` // seconds
+}
+
+func (*FailedHeight) TableName() string {
+	return "failed_heights"
+}
@@ -0,0 +1,80 @@
+package models
+
+import "github.com/forbole/juno/v4/common"
+
+// ProposalStatusDropped is the status of the proposals that did not reach the minimum deposit in time,
+// which are removed from the chain state
+const ProposalStatusDropped = "PROPOSAL_STATUS_DROPPED"
+
+// Proposal represents a governance proposal. Status is the name of the govv1.ProposalStatus, or ProposalStatusDropped.
+// MessageTypes is the JSON array of the type URLs of the messages executed when the proposal passes, TotalDeposit
+// is the sum of the deposits as sdk.Coins, and the tally counts are only set once the voting period ends.
+// Proposals downloaded by the fast sync have no proposer nor create fields.
+type Proposal struct {
+	ID uint64 `gorm:"column:id;primaryKey"`
+
+	ProposalID      uint64         `gorm:"column:proposal_id;uniqueIndex:idx_proposal_proposal_id"`
+	Proposer        common.Address `gorm:"column:proposer;type:BINARY(20);index:idx_proposal_proposer"`
+	MessageTypes    string         `gorm:"column:message_types;type:text"`
+	Metadata        string         `gorm:"column:metadata;type:text"`
+	Status          string         `gorm:"column:status;type:varchar(32);index:idx_proposal_status"`
+	SubmitTime      int64          `gorm:"column:submit_time"`       // seconds
+	DepositEndTime  int64          `gorm:"column:deposit_end_time"`  // seconds
+	VotingStartTime int64          `gorm:"column:voting_start_time"` // seconds
+	VotingEndTime   int64          `gorm:"column:voting_end_time"`   // seconds
+	TotalDeposit    string         `gorm:"column:total_deposit;type:varchar(256)"`
+	YesCount        string         `gorm:"column:yes_count;type:varchar(128)"`          // decimal
+	AbstainCount    string         `gorm:"column:abstain_count;type:varchar(128)"`      // decimal
+	NoCount         string         `gorm:"column:no_count;type:varchar(128)"`           // decimal
+	NoWithVetoCount string         `gorm:"column:no_with_veto_count;type:varchar(128)"` // decimal
+
+	CreateAt     int64       `gorm:"column:create_at"`
+	CreateTxHash common.Hash `gorm:"column:create_tx_hash;type:BINARY(32)"`
+	UpdateAt     int64       `gorm:"column:update_at"`
+	UpdateTxHash common.Hash `gorm:"column:update_tx_hash;type:BINARY(32)"`
+}
+
+func (*Proposal) TableName() string {
+	return "proposals"
+}
+
+// ProposalDeposit represents a deposit made to a proposal, including the initial deposit of its proposer.
+// Amount is the deposited sdk.Coins.
+type ProposalDeposit struct {
+	ID uint64 `gorm:"column:id;primaryKey"`
+
+	ProposalID uint64         `gorm:"column:proposal_id;index:idx_proposal_deposit_proposal_id"`
+	Depositor  common.Address `gorm:"column:depositor;type:BINARY(20);index:idx_proposal_deposit_depositor"`
+	Amount     string         `gorm:"column:amount;type:varchar(256)"`
+
+	Height int64       `gorm:"column:height;index:idx_proposal_deposit_height"`
+	TxHash common.Hash `gorm:"column:tx_hash;type:BINARY(32)"`
+}
+
+func (*ProposalDeposit) TableName() string {
+	return "proposal_deposits"
+}
+
+// VoteOption represents an option of a weighted vote, having the name of the govv1.VoteOption and its decimal weight
+type VoteOption struct {
+	Option string `json:"option"`
+	Weight string `json:"weight"`
+}
+
+// ProposalVote represents the latest vote of a voter on a proposal, which replaces the previous ones.
+// Options is the JSON array of the VoteOption of the vote.
+type ProposalVote struct {
+	ID uint64 `gorm:"column:id;primaryKey"`
+
+	ProposalID uint64         `gorm:"column:proposal_id;uniqueIndex:idx_proposal_vote_proposal_voter,priority:1"`
+	Voter      common.Address `gorm:"column:voter;type:BINARY(20);uniqueIndex:idx_proposal_vote_proposal_voter,priority:2;index:idx_proposal_vote_voter"`
+	Options    string         `gorm:"column:options;type:text"`
+	Metadata   string         `gorm:"column:metadata;type:text"`
+
+	Height int64       `gorm:"column:height;index:idx_proposal_vote_height"`
+	TxHash common.Hash `gorm:"column:tx_hash;type:BINARY(32)"`
+}
+
+func (*ProposalVote) TableName() string {
+	return "proposal_votes"
+}
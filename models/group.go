@@ -2,15 +2,46 @@ package models
 
 import "github.com/forbole/juno/v4/common"
 
+const (
+	GroupMemberAdd    = "add"
+	GroupMemberRemove = "remove"
+	GroupMemberLeave  = "leave"
+)
+
+// Group represents a group of accounts, which can be granted permissions as a whole.
+// The name of a group is only unique among the groups of its owner that are not removed.
 type Group struct {
-	ID         uint64         `gorm:"column:id;primaryKey"`
-	Owner      common.Address `gorm:"column:owner;type:BINARY(20);index:idx_owner"`
-	GroupID    common.Hash    `gorm:"column:group_id;type:BINARY(32);index:idx_group_id;unique_index:idx_account_group,priority:2"`
-	GroupName  string         `gorm:"column:group_name;type:varchar(63)"`
+	ID uint64 `gorm:"column:id;primaryKey"`
+
+	GroupID    common.Hash    `gorm:"column:group_id;type:BINARY(32);uniqueIndex:idx_group_group_id"`
+	Owner      common.Address `gorm:"column:owner;type:BINARY(20);index:idx_group_owner_name,priority:1"`
+	GroupName  string         `gorm:"column:group_name;type:varchar(63);index:idx_group_owner_name,priority:2"`
 	SourceType string         `gorm:"column:source_type;type:varchar(63)"`
 
-	AccountID       common.Hash    `gorm:"column:account_id;type:BINARY(32);unique_index:idx_account_group,priority:1"`
+	CreateAt     int64       `gorm:"column:create_at"`
+	CreateTxHash common.Hash `gorm:"column:create_tx_hash;type:BINARY(32)"`
+	CreateTime   int64       `gorm:"column:create_time"`
+	UpdateAt     int64       `gorm:"column:update_at"`
+	UpdateTxHash common.Hash `gorm:"column:update_tx_hash;type:BINARY(32)"`
+	UpdateTime   int64       `gorm:"column:update_time"`
+	Removed      bool        `gorm:"column:removed;default:false"`
+}
+
+func (*Group) TableName() string {
+	return "groups"
+}
+
+// GroupMember represents the membership of an account in a group.
+// Removed is set once the member is removed from the group or leaves it, and ExpirationTime is the time,
+// in seconds, after which the membership is no longer valid, or zero if it never expires.
+// ExpirationTime is never populated yet, as EventUpdateGroupMember of greenfield v0.1.0 carries no expiration.
+type GroupMember struct {
+	ID uint64 `gorm:"column:id;primaryKey"`
+
+	GroupID         common.Hash    `gorm:"column:group_id;type:BINARY(32);uniqueIndex:idx_group_member_group_member,priority:1"`
+	Member          common.Address `gorm:"column:member;type:BINARY(20);uniqueIndex:idx_group_member_group_member,priority:2;index:idx_group_member_member"`
 	OperatorAddress common.Address `gorm:"column:operator_address;type:BINARY(20)"`
+	ExpirationTime  int64          `gorm:"column:expiration_time"`
 
 	CreateAt   int64 `gorm:"column:create_at"`
 	CreateTime int64 `gorm:"column:create_time"`
@@ -19,6 +50,30 @@ type Group struct {
 	Removed    bool  `gorm:"column:removed;default:false"`
 }
 
-func (*Group) TableName() string {
-	return "groups"
+func (*GroupMember) TableName() string {
+	return "group_members"
+}
+
+// IsActive tells whether the membership is valid at the given time, in seconds
+func (m *GroupMember) IsActive(time int64) bool {
+	return !m.Removed && (m.ExpirationTime == 0 || m.ExpirationTime > time)
+}
+
+// GroupMemberChange represents an entry of the membership change log of a group.
+// Action is either GroupMemberAdd, GroupMemberRemove or GroupMemberLeave.
+type GroupMemberChange struct {
+	ID uint64 `gorm:"column:id;primaryKey"`
+
+	GroupID         common.Hash    `gorm:"column:group_id;type:BINARY(32);index:idx_group_member_change_group_id"`
+	Member          common.Address `gorm:"column:member;type:BINARY(20);index:idx_group_member_change_member"`
+	Action          string         `gorm:"column:action;type:varchar(16)"`
+	OperatorAddress common.Address `gorm:"column:operator_address;type:BINARY(20)"`
+
+	Height int64       `gorm:"column:height;index:idx_group_member_change_height"`
+	TxHash common.Hash `gorm:"column:tx_hash;type:BINARY(32);index:idx_group_member_change_tx_hash"`
+	Time   int64       `gorm:"column:time"`
+}
+
+func (*GroupMemberChange) TableName() string {
+	return "group_member_changes"
 }
@@ -0,0 +1,55 @@
+package models
+
+import "github.com/forbole/juno/v4/common"
+
+// BucketVersion represents an immutable version of a bucket, appended each time the bucket changes
+// while the history mode is enabled. State holds the JSON encoded bucket as it was after the change.
+type BucketVersion struct {
+	ID uint64 `gorm:"column:id;primaryKey"`
+
+	BucketID  common.Hash `gorm:"column:bucket_id;type:BINARY(32);index:idx_bucket_id_height,priority:1"`
+	Height    int64       `gorm:"column:height;index:idx_bucket_id_height,priority:2"`
+	TxHash    common.Hash `gorm:"column:tx_hash;type:BINARY(32);not null"`
+	EventType string      `gorm:"column:event_type;type:VARCHAR(128)"`
+	Diff      string      `gorm:"column:diff;type:TEXT"`
+	State     string      `gorm:"column:state;type:TEXT"`
+}
+
+func (*BucketVersion) TableName() string {
+	return "bucket_versions"
+}
+
+// ObjectVersion represents an immutable version of an object, appended each time the object changes
+// while the history mode is enabled. State holds the JSON encoded object as it was after the change.
+type ObjectVersion struct {
+	ID uint64 `gorm:"column:id;primaryKey"`
+
+	ObjectID  common.Hash `gorm:"column:object_id;type:BINARY(32);index:idx_object_id_height,priority:1"`
+	Height    int64       `gorm:"column:height;index:idx_object_id_height,priority:2"`
+	TxHash    common.Hash `gorm:"column:tx_hash;type:BINARY(32);not null"`
+	EventType string      `gorm:"column:event_type;type:VARCHAR(128)"`
+	Diff      string      `gorm:"column:diff;type:MEDIUMTEXT"`
+	State     string      `gorm:"column:state;type:MEDIUMTEXT"`
+}
+
+func (*ObjectVersion) TableName() string {
+	return "object_versions"
+}
+
+// GroupVersion represents an immutable version of a group member, appended each time the member changes
+// while the history mode is enabled. State holds the JSON encoded member as it was after the change.
+type GroupVersion struct {
+	ID uint64 `gorm:"column:id;primaryKey"`
+
+	GroupID   common.Hash    `gorm:"column:group_id;type:BINARY(32);index:idx_group_id_height,priority:1"`
+	Member    common.Address `gorm:"column:member;type:BINARY(20)"`
+	Height    int64          `gorm:"column:height;index:idx_group_id_height,priority:2"`
+	TxHash    common.Hash    `gorm:"column:tx_hash;type:BINARY(32);not null"`
+	EventType string         `gorm:"column:event_type;type:VARCHAR(128)"`
+	Diff      string         `gorm:"column:diff;type:TEXT"`
+	State     string         `gorm:"column:state;type:TEXT"`
+}
+
+func (*GroupVersion) TableName() string {
+	return "group_versions"
+}
@@ -0,0 +1,45 @@
+package models
+
+import (
+	"encoding/json"
+
+	"github.com/forbole/juno/v4/common"
+	"github.com/forbole/juno/v4/types"
+)
+
+// Message represents a single message of a transaction, along with the addresses involved in it.
+// Value is the JSON encoding of the message, and Addresses is the JSON array of the involved addresses.
+type Message struct {
+	ID uint64 `gorm:"column:id;primaryKey" json:"-"`
+
+	TxHash    common.Hash `gorm:"column:tx_hash;type:BINARY(32);not null;uniqueIndex:idx_message_tx_hash_index,priority:1"`
+	Index     int         `gorm:"column:msg_index;not null;uniqueIndex:idx_message_tx_hash_index,priority:2"`
+	Type      string      `gorm:"column:type;type:varchar(128);not null;index:idx_message_type_height,priority:1"`
+	Value     string      `gorm:"column:value;type:json;not null"`
+	Addresses string      `gorm:"column:addresses;type:json;not null"`
+
+	Height int64 `gorm:"column:height;not null;index:idx_message_type_height,priority:2;index:idx_message_height"`
+}
+
+func (*Message) TableName() string {
+	return "messages"
+}
+
+// NewMessage builds the row storing the given message
+func NewMessage(msg *types.Message) *Message {
+	addresses := msg.Addresses
+	if addresses == nil {
+		addresses = []string{}
+	}
+	// encoding a slice of strings cannot fail
+	addressesBz, _ := json.Marshal(addresses)
+
+	return &Message{
+		TxHash:    common.HexToHash(msg.TxHash),
+		Index:     msg.Index,
+		Type:      msg.Type,
+		Value:     msg.Value,
+		Addresses: string(addressesBz),
+		Height:    msg.Height,
+	}
+}
@@ -0,0 +1,38 @@
+package models
+
+import "github.com/forbole/juno/v4/common"
+
+const (
+	PaymentTransferDeposit       = "deposit"
+	PaymentTransferWithdraw      = "withdraw"
+	PaymentTransferDisableRefund = "disable_refund"
+	PaymentTransferForceSettle   = "force_settle"
+	PaymentTransferAutoSettle    = "auto_settle"
+
+	// PaymentTransferIn marks the transfers increasing the balance of the account
+	PaymentTransferIn = "in"
+	// PaymentTransferOut marks the transfers decreasing the balance of the account
+	PaymentTransferOut = "out"
+	// PaymentTransferNone marks the entries that do not move any funds
+	PaymentTransferNone = "none"
+)
+
+// PaymentTransfer represents an entry of the ledger of a stream account, which is either a payment account
+// matching PaymentAccount.Addr or a regular account. Auto settlements happen at the end of the block, so their TxHash is empty.
+type PaymentTransfer struct {
+	ID uint64 `gorm:"column:id;primaryKey" json:"-"`
+
+	Account     common.Address `gorm:"column:account;type:BINARY(20);not null;index:idx_payment_transfer_account"`
+	Kind        string         `gorm:"column:kind;type:varchar(32);not null"`
+	Direction   string         `gorm:"column:direction;type:varchar(8);not null"`
+	FromAddress common.Address `gorm:"column:from_address;type:BINARY(20)"`
+	ToAddress   common.Address `gorm:"column:to_address;type:BINARY(20)"`
+	Amount      *common.Big    `gorm:"column:amount"`
+
+	Height int64       `gorm:"column:height;not null;index:idx_payment_transfer_height"`
+	TxHash common.Hash `gorm:"column:tx_hash;type:BINARY(32)"`
+}
+
+func (*PaymentTransfer) TableName() string {
+	return "payment_transfers"
+}
@@ -30,21 +30,10 @@ type Statements struct {
 	ActionValue    int            `gorm:"action_value;type:int"`
 	Resources      pq.StringArray `gorm:"resources;type:text"`
 	ExpirationTime int64          `gorm:"expiration_time;type:bigint(64)"`
-	LimitSize      uint64         `gorm:"limit_size;type:bigint(64)"`
+	LimitSize      *uint64        `gorm:"limit_size;type:bigint(64)"` // nil when the size of the created objects is not limited
 	Removed        bool           `gorm:"removed;"`
 }
 
 func (s Statements) TableName() string {
 	return "statements"
 }
-
-type GroupMember struct {
-	ID      uint64         `gorm:"id;type:bigint(64);primaryKey"`
-	GroupID uint64         `gorm:"id;type:bigint(64);index:idx_group_id"`
-	Account common.Address `gorm:"account;type:BINARY(20);index:idx_account"`
-	Removed bool           `gorm:"removed"`
-}
-
-func (g GroupMember) TableName() string {
-	return "group_member"
-}
@@ -0,0 +1,22 @@
+package models
+
+import (
+	"github.com/forbole/juno/v4/common"
+)
+
+// QuarantinedEvent represents an event that a module failed to handle, stored to be replayed later
+type QuarantinedEvent struct {
+	ID         uint64      `gorm:"column:id;primaryKey"`
+	Module     string      `gorm:"column:module;type:varchar(64);index:idx_quarantined_module"`
+	Height     int64       `gorm:"column:height;index:idx_quarantined_height"`
+	TxHash     common.Hash `gorm:"column:tx_hash;type:BINARY(32)"`
+	EventType  string      `gorm:"column:event_type;type:varchar(128)"`
+	Event      string      `gorm:"column:event;type:TEXT"` // JSON encoded sdk.Event
+	Attempts   uint        `gorm:"column:attempts"`
+	LastError  string      `gorm:"column:last_error;type:TEXT"`
+	UpdateTime int64       `gorm:"column:update_time"` // seconds
+}
+
+func (*QuarantinedEvent) TableName() string {
+	return "quarantined_events"
+}
@@ -0,0 +1,55 @@
+package models
+
+import (
+	"github.com/forbole/juno/v4/common"
+)
+
+// StorageProvider represents a storage provider of Greenfield.
+// It has no status: greenfield v0.1.0 emits no event when the status of a storage provider changes, so the status
+// could not be kept up to date from the events. It should be added along with the events carrying it.
+type StorageProvider struct {
+	ID uint64 `gorm:"column:id;primaryKey"`
+
+	OperatorAddress common.Address `gorm:"column:operator_address;type:BINARY(20);uniqueIndex:idx_sp_operator_address"`
+	FundingAddress  common.Address `gorm:"column:funding_address;type:BINARY(20);index:idx_sp_funding_address"`
+	SealAddress     common.Address `gorm:"column:seal_address;type:BINARY(20)"`
+	ApprovalAddress common.Address `gorm:"column:approval_address;type:BINARY(20)"`
+	Endpoint        string         `gorm:"column:endpoint;type:varchar(256);index:idx_sp_endpoint"`
+	DepositDenom    string         `gorm:"column:deposit_denom;type:varchar(64)"`
+	TotalDeposit    *common.Big    `gorm:"column:total_deposit"`
+
+	ReadPrice       string `gorm:"column:read_price;type:varchar(128)"`  // decimal, in wei per charge byte
+	FreeReadQuota   uint64 `gorm:"column:free_read_quota"`               // bytes
+	StorePrice      string `gorm:"column:store_price;type:varchar(128)"` // decimal, in wei per charge byte
+	PriceUpdateTime int64  `gorm:"column:price_update_time"`             // seconds
+
+	CreateAt     int64       `gorm:"column:create_at"`
+	CreateTxHash common.Hash `gorm:"column:create_tx_hash;type:BINARY(32);not null"`
+	CreateTime   int64       `gorm:"column:create_time"` // seconds
+	UpdateAt     int64       `gorm:"column:update_at"`
+	UpdateTxHash common.Hash `gorm:"column:update_tx_hash;type:BINARY(32);not null"`
+	UpdateTime   int64       `gorm:"column:update_time"` // seconds
+}
+
+func (*StorageProvider) TableName() string {
+	return "storage_providers"
+}
+
+// StoragePrice represents a price update of a storage provider.
+// Updates of the store price of secondary storage providers, which is the same for all of them, have an empty SpAddress.
+type StoragePrice struct {
+	ID uint64 `gorm:"column:id;primaryKey"`
+
+	SpAddress     common.Address `gorm:"column:sp_address;type:BINARY(20);index:idx_storage_price_sp"`
+	UpdateTimeSec int64          `gorm:"column:update_time_sec"`
+	ReadPrice     string         `gorm:"column:read_price;type:varchar(128)"`  // decimal, in wei per charge byte
+	FreeReadQuota uint64         `gorm:"column:free_read_quota"`               // bytes
+	StorePrice    string         `gorm:"column:store_price;type:varchar(128)"` // decimal, in wei per charge byte
+
+	Height int64       `gorm:"column:height;index:idx_storage_price_height"`
+	TxHash common.Hash `gorm:"column:tx_hash;type:BINARY(32)"`
+}
+
+func (*StoragePrice) TableName() string {
+	return "storage_prices"
+}
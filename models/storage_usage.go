@@ -0,0 +1,78 @@
+package models
+
+import (
+	"github.com/forbole/juno/v4/common"
+)
+
+// ObjectStatusSealed is the status of the objects that have been sealed by their storage providers
+const ObjectStatusSealed = "OBJECT_STATUS_SEALED"
+
+// StorageUsage contains the number of objects and the total payload size of a set of objects, split by their state.
+// Removed objects are only counted as removed, whatever their status was.
+type StorageUsage struct {
+	SealedCount  int64 `gorm:"column:sealed_count"`
+	SealedSize   int64 `gorm:"column:sealed_size"`
+	PendingCount int64 `gorm:"column:pending_count"`
+	PendingSize  int64 `gorm:"column:pending_size"`
+	RemovedCount int64 `gorm:"column:removed_count"`
+	RemovedSize  int64 `gorm:"column:removed_size"`
+	UpdateAt     int64 `gorm:"column:update_at"`
+}
+
+// IsZero tells whether the usage does not count any object
+func (u *StorageUsage) IsZero() bool {
+	return u.SealedCount == 0 && u.SealedSize == 0 && u.PendingCount == 0 && u.PendingSize == 0 &&
+		u.RemovedCount == 0 && u.RemovedSize == 0
+}
+
+// Add adds the given object to the usage, or subtracts it if sign is negative
+func (u *StorageUsage) Add(object *Object, sign int64) {
+	size := sign * int64(object.PayloadSize)
+	switch {
+	case object.Removed:
+		u.RemovedCount += sign
+		u.RemovedSize += size
+	case object.Status == ObjectStatusSealed:
+		u.SealedCount += sign
+		u.SealedSize += size
+	default:
+		u.PendingCount += sign
+		u.PendingSize += size
+	}
+}
+
+// BucketUsage represents the storage usage of the objects inside a bucket
+type BucketUsage struct {
+	ID uint64 `gorm:"column:id;primaryKey"`
+
+	BucketID     common.Hash `gorm:"column:bucket_id;type:BINARY(32);uniqueIndex:idx_bucket_usage_bucket_id"`
+	StorageUsage `gorm:"embedded"`
+}
+
+func (*BucketUsage) TableName() string {
+	return "bucket_usages"
+}
+
+// OwnerUsage represents the storage usage of the objects owned by an account
+type OwnerUsage struct {
+	ID uint64 `gorm:"column:id;primaryKey"`
+
+	OwnerAddress common.Address `gorm:"column:owner_address;type:BINARY(20);uniqueIndex:idx_owner_usage_owner_address"`
+	StorageUsage `gorm:"embedded"`
+}
+
+func (*OwnerUsage) TableName() string {
+	return "owner_usages"
+}
+
+// SpUsage represents the storage usage of the objects stored by a primary storage provider
+type SpUsage struct {
+	ID uint64 `gorm:"column:id;primaryKey"`
+
+	SpAddress    common.Address `gorm:"column:sp_address;type:BINARY(20);uniqueIndex:idx_sp_usage_sp_address"`
+	StorageUsage `gorm:"embedded"`
+}
+
+func (*SpUsage) TableName() string {
+	return "sp_usages"
+}
@@ -0,0 +1,34 @@
+package models
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestStorageUsage_Add(t *testing.T) {
+	var usage StorageUsage
+
+	usage.Add(&Object{PayloadSize: 10, Status: ObjectStatusSealed}, 1)
+	usage.Add(&Object{PayloadSize: 20, Status: "OBJECT_STATUS_CREATED"}, 1)
+	usage.Add(&Object{PayloadSize: 30, Status: ObjectStatusSealed, Removed: true}, 1)
+	require.Equal(t, StorageUsage{
+		SealedCount: 1, SealedSize: 10,
+		PendingCount: 1, PendingSize: 20,
+		RemovedCount: 1, RemovedSize: 30,
+	}, usage)
+
+	// sealing the pending object moves it from pending to sealed
+	usage.Add(&Object{PayloadSize: 20, Status: "OBJECT_STATUS_CREATED"}, -1)
+	usage.Add(&Object{PayloadSize: 20, Status: ObjectStatusSealed}, 1)
+	require.Equal(t, StorageUsage{
+		SealedCount: 2, SealedSize: 30,
+		RemovedCount: 1, RemovedSize: 30,
+	}, usage)
+	require.False(t, usage.IsZero())
+
+	usage.Add(&Object{PayloadSize: 10, Status: ObjectStatusSealed}, -1)
+	usage.Add(&Object{PayloadSize: 20, Status: ObjectStatusSealed}, -1)
+	usage.Add(&Object{PayloadSize: 30, Removed: true}, -1)
+	require.True(t, usage.IsZero())
+}
@@ -21,3 +21,37 @@ type StreamRecord struct {
 func (*StreamRecord) TableName() string {
 	return "stream_records"
 }
+
+// StreamRecordHistory represents the state of a stream record at the end of a height in which it was updated
+type StreamRecordHistory struct {
+	ID uint64 `gorm:"column:id;primaryKey" json:"-"`
+
+	Account         common.Address `gorm:"column:account;type:BINARY(20);uniqueIndex:idx_stream_record_history_account_height,priority:1"`
+	Height          int64          `gorm:"column:height;uniqueIndex:idx_stream_record_history_account_height,priority:2;index:idx_stream_record_history_height"`
+	TxHash          common.Hash    `gorm:"column:tx_hash;type:BINARY(32)"`
+	CrudTimestamp   int64          `gorm:"column:crud_timestamp"`
+	NetflowRate     *common.Big    `gorm:"column:netflow_rate"`
+	StaticBalance   *common.Big    `gorm:"column:static_balance"`
+	BufferBalance   *common.Big    `gorm:"column:buffer_balance"`
+	LockBalance     *common.Big    `gorm:"column:lock_balance"`
+	Status          string         `gorm:"column:status"`
+	SettleTimestamp int64          `gorm:"column:settle_timestamp"`
+}
+
+func (*StreamRecordHistory) TableName() string {
+	return "stream_record_histories"
+}
+
+// StreamOutflow represents a payment stream flowing from an account to another one
+type StreamOutflow struct {
+	ID uint64 `gorm:"column:id;primaryKey" json:"-"`
+
+	FromAddress common.Address `gorm:"column:from_address;type:BINARY(20);uniqueIndex:idx_stream_outflow_from_to,priority:1"`
+	ToAddress   common.Address `gorm:"column:to_address;type:BINARY(20);uniqueIndex:idx_stream_outflow_from_to,priority:2;index:idx_stream_outflow_to"`
+	Rate        *common.Big    `gorm:"column:rate"`
+	UpdateAt    int64          `gorm:"column:update_at"`
+}
+
+func (*StreamOutflow) TableName() string {
+	return "stream_outflows"
+}
@@ -0,0 +1,30 @@
+package models
+
+// SyncProgressID is the id of the only SyncProgress row
+const SyncProgressID = 1
+
+// SyncProgress represents the checkpoint of the heights processed so far.
+// All the heights between LowestHeight and HighestHeight have been processed, except for the ones inside
+// the SyncGap ranges. Watermark is the highest height up to which all the heights starting from LowestHeight
+// have been processed.
+type SyncProgress struct {
+	ID            uint64 `gorm:"column:id;primaryKey;autoIncrement:false"`
+	LowestHeight  uint64 `gorm:"column:lowest_height"`
+	Watermark     uint64 `gorm:"column:watermark"`
+	HighestHeight uint64 `gorm:"column:highest_height"`
+}
+
+func (*SyncProgress) TableName() string {
+	return "sync_progress"
+}
+
+// SyncGap represents a range of heights, bounds included, that have not been processed yet
+type SyncGap struct {
+	ID          uint64 `gorm:"column:id;primaryKey"`
+	StartHeight uint64 `gorm:"column:start_height;index:idx_start_height"`
+	EndHeight   uint64 `gorm:"column:end_height"`
+}
+
+func (*SyncGap) TableName() string {
+	return "sync_gaps"
+}
@@ -55,7 +55,7 @@ func (*Validator) TableName() string {
 	return "validators"
 }
 
-// ValidatorInfo is managed by upgrade module
+// ValidatorInfo is managed by staking module
 type ValidatorInfo struct {
 	ID uint64 `gorm:"column:id;primaryKey" json:"-"`
 
@@ -71,7 +71,7 @@ func (*ValidatorInfo) TableName() string {
 	return "validator_infos"
 }
 
-// ValidatorDescription is managed by upgrade module
+// ValidatorDescription is managed by staking module
 type ValidatorDescription struct {
 	ID uint64 `gorm:"column:id;primaryKey" json:"-"`
 
@@ -89,13 +89,14 @@ func (*ValidatorDescription) TableName() string {
 	return "validator_descriptions"
 }
 
-// ValidatorCommission is managed by upgrade module
+// ValidatorCommission is managed by staking module.
+// Commission is the decimal commission rate, and MinSelfDelegation the decimal minimum self delegation.
 type ValidatorCommission struct {
 	ID uint64 `gorm:"column:id;primaryKey" json:"-"`
 
 	ValidatorAddress  common.Address `gorm:"column:validator_address;type:binary(20);not null;uniqueIndex:idx_address"` // refer validator(consensus_address)
-	Commission        uint64         `gorm:"column:commission"`
-	MinSelfDelegation uint64         `gorm:"column:min_self_delegation"`
+	Commission        string         `gorm:"column:commission;type:varchar(64)"`
+	MinSelfDelegation string         `gorm:"column:min_self_delegation;type:varchar(128)"`
 	Height            uint64         `gorm:"column:height;index:idx_height"`
 }
 
@@ -116,7 +117,7 @@ func (*ValidatorVotingPower) TableName() string {
 	return "validator_voting_powers"
 }
 
-// ValidatorStatus is managed by staking and gov module
+// ValidatorStatus is managed by staking module. Status is the stakingtypes.BondStatus of the validator
 type ValidatorStatus struct {
 	ID uint64 `gorm:"column:id;primaryKey" json:"-"`
 
@@ -147,6 +148,51 @@ func (*ValidatorSigningInfo) TableName() string {
 	return "validator_signing_infos"
 }
 
+// PreCommit represents the signature of a validator on the commit of a block, which is carried by the next block.
+// VotingPower and ProposerPriority are the ones of the validator in the validator set of the committed height.
+type PreCommit struct {
+	ID uint64 `gorm:"column:id;primaryKey" json:"-"`
+
+	ValidatorAddress common.Address `gorm:"column:validator_address;type:binary(20);not null;uniqueIndex:idx_pre_commit_validator_height,priority:1"` // refer validator(consensus_address)
+	Height           int64          `gorm:"column:height;not null;uniqueIndex:idx_pre_commit_validator_height,priority:2;index:idx_pre_commit_height"`
+	Timestamp        int64          `gorm:"column:timestamp"` // seconds
+	VotingPower      int64          `gorm:"column:voting_power"`
+	ProposerPriority int64          `gorm:"column:proposer_priority"`
+}
+
+func (*PreCommit) TableName() string {
+	return "pre_commit"
+}
+
+// ValidatorUptime is managed by validator module.
+// It counts the blocks signed and missed by the validator among the last Window heights it was part of the
+// validator set, up to Height. MissedBlocksBitmap is the rolling window itself, one bit per height set when the
+// block was missed, and IndexOffset is the number of heights recorded so far.
+type ValidatorUptime struct {
+	ID uint64 `gorm:"column:id;primaryKey" json:"-"`
+
+	ValidatorAddress   common.Address `gorm:"column:validator_address;type:binary(20);not null;uniqueIndex:idx_validator_uptime_address"` // refer validator(consensus_address)
+	Window             int64          `gorm:"column:window_size"`
+	IndexOffset        int64          `gorm:"column:index_offset"`
+	SignedBlocks       int64          `gorm:"column:signed_blocks"`
+	MissedBlocks       int64          `gorm:"column:missed_blocks"`
+	MissedBlocksBitmap []byte         `gorm:"column:missed_blocks_bitmap"`
+	Height             int64          `gorm:"column:height;index:idx_validator_uptime_height"`
+}
+
+func (*ValidatorUptime) TableName() string {
+	return "validator_uptimes"
+}
+
+// Uptime returns the share of the blocks of the window signed by the validator, or zero if none was recorded
+func (u *ValidatorUptime) Uptime() float64 {
+	total := u.SignedBlocks + u.MissedBlocks
+	if total == 0 {
+		return 0
+	}
+	return float64(u.SignedBlocks) / float64(total)
+}
+
 func NewValidator(ConsensusAddress common.Address, ConsensusPubkey Pubkey) *Validator {
 	return &Validator{
 		ConsensusAddress: ConsensusAddress,
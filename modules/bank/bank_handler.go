@@ -0,0 +1,292 @@
+package bank
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math/big"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/cosmos/cosmos-sdk/types/query"
+	banktypes "github.com/cosmos/cosmos-sdk/x/bank/types"
+	abci "github.com/tendermint/tendermint/abci/types"
+	tmctypes "github.com/tendermint/tendermint/rpc/core/types"
+	tmtypes "github.com/tendermint/tendermint/types"
+
+	"github.com/forbole/juno/v4/common"
+	"github.com/forbole/juno/v4/log"
+	"github.com/forbole/juno/v4/models"
+	"github.com/forbole/juno/v4/node/remote"
+	"github.com/forbole/juno/v4/sink"
+	"github.com/forbole/juno/v4/types"
+)
+
+// bankEvents are the events moving coins, along with the attributes carrying their from and to addresses.
+// The sender of the transfer events is missing for the outputs of multi-sends.
+var bankEvents = map[string]struct{ from, to string }{
+	banktypes.EventTypeCoinSpent:    {from: banktypes.AttributeKeySpender},
+	banktypes.EventTypeCoinReceived: {to: banktypes.AttributeKeyReceiver},
+	banktypes.EventTypeTransfer:     {from: banktypes.AttributeKeySender, to: banktypes.AttributeKeyRecipient},
+}
+
+// HandleGenesis implements modules.GenesisModule.
+// The genesis balances are stored as the snapshots of height zero.
+func (m *Module) HandleGenesis(_ *tmtypes.GenesisDoc, appState map[string]json.RawMessage) error {
+	var genState banktypes.GenesisState
+	err := m.cdc.UnmarshalJSON(appState[banktypes.ModuleName], &genState)
+	if err != nil {
+		return fmt.Errorf("error while unmarshalling bank genesis state: %s", err)
+	}
+
+	var records []*sink.Record
+	for _, balance := range genState.Balances {
+		for _, coin := range balance.Coins {
+			records = append(records, sink.NewRecord(ModuleName, 0, common.Hash{}, sink.OperationSave, &models.AccountBalance{
+				Account: common.HexToAddress(balance.Address),
+				Denom:   coin.Denom,
+				Amount:  (*common.Big)(coin.Amount.BigInt()),
+			}))
+		}
+	}
+	return m.sink.Emit(context.Background(), records...)
+}
+
+// HandleBlock implements modules.BlockModule.
+// The coins moved by the begin and end blockers, such as the fees being distributed, are handled here.
+// As every height starts with its block, the transfers and the balances already stored at the height are removed,
+// so that a height can be processed again.
+func (m *Module) HandleBlock(
+	ctx context.Context, block *tmctypes.ResultBlock, results *tmctypes.ResultBlockResults, _ []*types.Tx, _ *tmctypes.ResultValidators,
+) error {
+	height := block.Block.Height
+
+	err := m.sink.Emit(ctx,
+		sink.NewRecord(ModuleName, height, common.Hash{}, sink.OperationDelete, &models.Transfer{Height: height}),
+		sink.NewRecord(ModuleName, height, common.Hash{}, sink.OperationDelete, &models.AccountBalance{Height: height}),
+	)
+	if err != nil {
+		return err
+	}
+
+	if results == nil {
+		return nil
+	}
+	for _, events := range [][]abci.Event{results.BeginBlockEvents, results.EndBlockEvents} {
+		for _, event := range events {
+			err := m.handleEvent(ctx, height, common.Hash{}, event)
+			if err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// HandleEvent implements modules.EventModule
+func (m *Module) HandleEvent(ctx context.Context, block *tmctypes.ResultBlock, txHash common.Hash, event sdk.Event) error {
+	if _, ok := bankEvents[event.Type]; !ok {
+		return nil
+	}
+	return m.handleEvent(ctx, block.Block.Height, txHash, abci.Event(event))
+}
+
+// handleEvent stores the transfers of the given event, along with the balances it changed
+func (m *Module) handleEvent(ctx context.Context, height int64, txHash common.Hash, event abci.Event) error {
+	if _, ok := bankEvents[event.Type]; !ok {
+		return nil
+	}
+
+	transfers, err := eventTransfers(event)
+	if err != nil {
+		return err
+	}
+
+	var records []*sink.Record
+	for _, transfer := range transfers {
+		transfer.Height, transfer.TxHash = height, txHash
+		records = append(records, sink.NewRecord(ModuleName, height, txHash, sink.OperationSave, transfer))
+	}
+
+	// only the coin spent and received events change the balances, the transfer events duplicating them
+	for _, transfer := range transfers {
+		var balance *models.AccountBalance
+		switch transfer.Kind {
+		case models.TransferCoinSpent:
+			balance, err = m.addBalance(ctx, height, transfer.FromAddress, transfer.Denom, new(big.Int).Neg(transfer.Amount.Raw()))
+		case models.TransferCoinReceived:
+			balance, err = m.addBalance(ctx, height, transfer.ToAddress, transfer.Denom, transfer.Amount.Raw())
+		default:
+			continue
+		}
+		if err != nil {
+			return err
+		}
+		records = append(records, sink.NewRecord(ModuleName, height, txHash, sink.OperationSave, balance))
+	}
+
+	return m.sink.Emit(ctx, records...)
+}
+
+// addBalance adds the given amount to the balance of the given account in the given denom, returning the snapshot
+// of the new balance. The balance starts from the latest snapshot up to the given height, which includes the changes
+// of the events of the height already handled.
+func (m *Module) addBalance(ctx context.Context, height int64, account common.Address, denom string, amount *big.Int) (*models.AccountBalance, error) {
+	latest, err := m.db.GetLatestBalance(ctx, account, denom, height)
+	if err != nil {
+		log.Errorw("get latest balance error", "module", m.Name(), "account", account.Hex(), "denom", denom, "err", err)
+		return nil, err
+	}
+
+	balance := new(big.Int)
+	if latest != nil && latest.Amount != nil {
+		balance.Set(latest.Amount.Raw())
+	}
+
+	balance.Add(balance, amount)
+	if balance.Sign() < 0 {
+		log.Warnw("negative balance, the previous balances are missing", "module", m.Name(), "account", account.Hex(),
+			"denom", denom, "height", height)
+	}
+
+	return &models.AccountBalance{
+		Account: account,
+		Denom:   denom,
+		Height:  height,
+		Amount:  (*common.Big)(balance),
+	}, nil
+}
+
+// bankQueryClient returns the client used to query the balances, which is only built when first needed
+func (m *Module) bankQueryClient() (banktypes.QueryClient, error) {
+	if m.grpcCfg == nil {
+		return nil, fmt.Errorf("the %s module requires a remote node", m.Name())
+	}
+
+	var err error
+	m.clientOnce.Do(func() {
+		conn, connErr := remote.CreateGrpcConnection(m.grpcCfg)
+		if connErr != nil {
+			err = fmt.Errorf("error while connecting to the gRPC endpoint: %s", connErr)
+			return
+		}
+		m.bankClient = banktypes.NewQueryClient(conn)
+	})
+	if err != nil {
+		return nil, err
+	}
+	if m.bankClient == nil {
+		return nil, fmt.Errorf("the gRPC endpoint of the %s module is not available", m.Name())
+	}
+	return m.bankClient, nil
+}
+
+// DownloadState implements modules.FastSyncModule.
+// The balances of every account are stored as the snapshots of the given height, reading the owners of each denom
+// of the total supply.
+func (m *Module) DownloadState(height int64) error {
+	client, err := m.bankQueryClient()
+	if err != nil {
+		return err
+	}
+
+	ctx := context.Background()
+	var denoms []string
+	var nextKey []byte
+	for {
+		res, err := client.TotalSupply(remote.GetHeightRequestContext(ctx, height), &banktypes.QueryTotalSupplyRequest{
+			Pagination: &query.PageRequest{Key: nextKey, Limit: 100},
+		})
+		if err != nil {
+			log.Errorw("query total supply error", "module", m.Name(), "height", height, "err", err)
+			return err
+		}
+		for _, coin := range res.Supply {
+			denoms = append(denoms, coin.Denom)
+		}
+
+		if res.Pagination == nil || len(res.Pagination.NextKey) == 0 {
+			break
+		}
+		nextKey = res.Pagination.NextKey
+	}
+
+	for _, denom := range denoms {
+		nextKey = nil
+		for {
+			res, err := client.DenomOwners(remote.GetHeightRequestContext(ctx, height), &banktypes.QueryDenomOwnersRequest{
+				Denom:      denom,
+				Pagination: &query.PageRequest{Key: nextKey, Limit: 100},
+			})
+			if err != nil {
+				log.Errorw("query denom owners error", "module", m.Name(), "denom", denom, "height", height, "err", err)
+				return err
+			}
+
+			records := make([]*sink.Record, len(res.DenomOwners))
+			for i, owner := range res.DenomOwners {
+				records[i] = sink.NewRecord(ModuleName, height, common.Hash{}, sink.OperationSave, &models.AccountBalance{
+					Account: common.HexToAddress(owner.Address),
+					Denom:   owner.Balance.Denom,
+					Height:  height,
+					Amount:  (*common.Big)(owner.Balance.Amount.BigInt()),
+				})
+			}
+
+			err = m.sink.Emit(ctx, records...)
+			if err != nil {
+				return err
+			}
+
+			if res.Pagination == nil || len(res.Pagination.NextKey) == 0 {
+				break
+			}
+			nextKey = res.Pagination.NextKey
+		}
+	}
+
+	log.Infow("downloaded state", "module", m.Name(), "height", height, "denoms", len(denoms))
+	return nil
+}
+
+// eventTransfers returns the transfers carried by the given bank event, one for each denom of its amount
+func eventTransfers(event abci.Event) ([]*models.Transfer, error) {
+	keys := bankEvents[event.Type]
+
+	attr, err := types.FindAttributeByKey(event, sdk.AttributeKeyAmount)
+	if err != nil {
+		return nil, err
+	}
+	coins, err := sdk.ParseCoinsNormalized(string(attr.Value))
+	if err != nil {
+		return nil, fmt.Errorf("invalid amount of %s event: %s", event.Type, err)
+	}
+
+	var from, to common.Address
+	if keys.from != "" {
+		attr, err = types.FindAttributeByKey(event, keys.from)
+		if err == nil {
+			from = common.HexToAddress(string(attr.Value))
+		} else if event.Type != banktypes.EventTypeTransfer {
+			return nil, err
+		}
+	}
+	if keys.to != "" {
+		attr, err = types.FindAttributeByKey(event, keys.to)
+		if err != nil {
+			return nil, err
+		}
+		to = common.HexToAddress(string(attr.Value))
+	}
+
+	transfers := make([]*models.Transfer, len(coins))
+	for i, coin := range coins {
+		transfers[i] = &models.Transfer{
+			Kind:        event.Type,
+			FromAddress: from,
+			ToAddress:   to,
+			Denom:       coin.Denom,
+			Amount:      (*common.Big)(coin.Amount.BigInt()),
+		}
+	}
+	return transfers, nil
+}
@@ -0,0 +1,173 @@
+package bank
+
+import (
+	"context"
+	"math/big"
+	"testing"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	banktypes "github.com/cosmos/cosmos-sdk/x/bank/types"
+	"github.com/stretchr/testify/require"
+	abci "github.com/tendermint/tendermint/abci/types"
+	tmctypes "github.com/tendermint/tendermint/rpc/core/types"
+	tmtypes "github.com/tendermint/tendermint/types"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+
+	"github.com/forbole/juno/v4/common"
+	"github.com/forbole/juno/v4/database"
+	"github.com/forbole/juno/v4/models"
+	"github.com/forbole/juno/v4/sink"
+)
+
+const (
+	sender    = "0x1111111111111111111111111111111111111111"
+	recipient = "0x2222222222222222222222222222222222222222"
+)
+
+func TestEventTransfers(t *testing.T) {
+	transfers, err := eventTransfers(abci.Event{
+		Type: banktypes.EventTypeTransfer,
+		Attributes: []abci.EventAttribute{
+			{Key: []byte(banktypes.AttributeKeyRecipient), Value: []byte(recipient)},
+			{Key: []byte(banktypes.AttributeKeySender), Value: []byte(sender)},
+			{Key: []byte("amount"), Value: []byte("5BNB,100stake")},
+		},
+	})
+	require.NoError(t, err)
+	require.Equal(t, []*models.Transfer{
+		{
+			Kind:        models.TransferTransfer,
+			FromAddress: common.HexToAddress(sender),
+			ToAddress:   common.HexToAddress(recipient),
+			Denom:       "BNB",
+			Amount:      (*common.Big)(big.NewInt(5)),
+		},
+		{
+			Kind:        models.TransferTransfer,
+			FromAddress: common.HexToAddress(sender),
+			ToAddress:   common.HexToAddress(recipient),
+			Denom:       "stake",
+			Amount:      (*common.Big)(big.NewInt(100)),
+		},
+	}, transfers)
+
+	// the outputs of multi-sends have no sender
+	transfers, err = eventTransfers(abci.Event{
+		Type: banktypes.EventTypeTransfer,
+		Attributes: []abci.EventAttribute{
+			{Key: []byte(banktypes.AttributeKeyRecipient), Value: []byte(recipient)},
+			{Key: []byte("amount"), Value: []byte("5BNB")},
+		},
+	})
+	require.NoError(t, err)
+	require.Len(t, transfers, 1)
+	require.Equal(t, common.Address{}, transfers[0].FromAddress)
+
+	transfers, err = eventTransfers(abci.Event{
+		Type: banktypes.EventTypeCoinSpent,
+		Attributes: []abci.EventAttribute{
+			{Key: []byte(banktypes.AttributeKeySpender), Value: []byte(sender)},
+			{Key: []byte("amount"), Value: []byte("7BNB")},
+		},
+	})
+	require.NoError(t, err)
+	require.Len(t, transfers, 1)
+	require.Equal(t, models.TransferCoinSpent, transfers[0].Kind)
+	require.Equal(t, common.HexToAddress(sender), transfers[0].FromAddress)
+	require.Equal(t, common.Address{}, transfers[0].ToAddress)
+
+	_, err = eventTransfers(abci.Event{
+		Type:       banktypes.EventTypeCoinReceived,
+		Attributes: []abci.EventAttribute{{Key: []byte("amount"), Value: []byte("7BNB")}},
+	})
+	require.Error(t, err)
+}
+
+// newTestModule returns a module writing to an in-memory database through the sql sink, with the given genesis
+// balance of the sender
+func newTestModule(t *testing.T, genesis int64) (*Module, *database.Impl) {
+	t.Helper()
+
+	gormDB, err := gorm.Open(sqlite.Open("file::memory:"), &gorm.Config{Logger: logger.Default.LogMode(logger.Silent)})
+	require.NoError(t, err)
+	sqlDB, err := gormDB.DB()
+	require.NoError(t, err)
+	// every connection to an in-memory database opens a new one
+	sqlDB.SetMaxOpenConns(1)
+	require.NoError(t, gormDB.AutoMigrate(&models.Transfer{}, &models.AccountBalance{}))
+
+	db := &database.Impl{Db: gormDB}
+	require.NoError(t, db.SaveAccountBalances(context.Background(), []*models.AccountBalance{
+		{Account: common.HexToAddress(sender), Denom: "BNB", Amount: (*common.Big)(big.NewInt(genesis))},
+	}))
+	return &Module{db: db, sink: sink.NewSQLSink(db)}, db
+}
+
+// coinSpent returns a coin spent event of the sender
+func coinSpent(amount string) sdk.Event {
+	return sdk.Event{
+		Type: banktypes.EventTypeCoinSpent,
+		Attributes: []abci.EventAttribute{
+			{Key: []byte(banktypes.AttributeKeySpender), Value: []byte(sender)},
+			{Key: []byte("amount"), Value: []byte(amount)},
+		},
+	}
+}
+
+// senderBalance returns the latest balance of the sender up to the given height
+func senderBalance(t *testing.T, ctx context.Context, db database.Database, height int64) int64 {
+	t.Helper()
+
+	balance, err := db.GetLatestBalance(ctx, common.HexToAddress(sender), "BNB", height)
+	require.NoError(t, err)
+	require.NotNil(t, balance)
+	return balance.Amount.Raw().Int64()
+}
+
+func TestHandleEvent_RolledBack(t *testing.T) {
+	m, db := newTestModule(t, 10)
+	block := &tmctypes.ResultBlock{Block: &tmtypes.Block{Header: tmtypes.Header{Height: 1}}}
+
+	dbTx := db.Begin(context.Background())
+	require.NoError(t, dbTx.Db.Error)
+	defer dbTx.Rollback()
+	ctx := database.WithTx(context.Background(), dbTx)
+
+	require.NoError(t, m.HandleBlock(ctx, block, nil, nil, nil))
+	require.NoError(t, m.HandleEvent(ctx, block, common.Hash{}, coinSpent("3BNB")))
+	require.Equal(t, int64(7), senderBalance(t, ctx, dbTx, 1))
+
+	// the changes of a handler discarded by the error policy are not counted when it is retried
+	require.NoError(t, dbTx.SavePoint(ctx, "handler"))
+	require.NoError(t, m.HandleEvent(ctx, block, common.Hash{}, coinSpent("2BNB")))
+	require.NoError(t, dbTx.RollbackTo(ctx, "handler"))
+	require.NoError(t, m.HandleEvent(ctx, block, common.Hash{}, coinSpent("2BNB")))
+	require.Equal(t, int64(5), senderBalance(t, ctx, dbTx, 1))
+}
+
+func TestHandleBlock_Reprocessed(t *testing.T) {
+	m, db := newTestModule(t, 10)
+	ctx := context.Background()
+
+	process := func(height int64) {
+		block := &tmctypes.ResultBlock{Block: &tmtypes.Block{Header: tmtypes.Header{Height: height}}}
+		require.NoError(t, m.HandleBlock(ctx, block, nil, nil, nil))
+		require.NoError(t, m.HandleEvent(ctx, block, common.Hash{}, coinSpent("3BNB")))
+	}
+
+	process(1)
+	process(2)
+	require.Equal(t, int64(4), senderBalance(t, ctx, db, 2))
+
+	// processing the heights again replaces their transfers and balances
+	process(1)
+	process(2)
+	require.Equal(t, int64(7), senderBalance(t, ctx, db, 1))
+	require.Equal(t, int64(4), senderBalance(t, ctx, db, 2))
+
+	var transfers int64
+	require.NoError(t, db.Db.Model(&models.Transfer{}).Count(&transfers).Error)
+	require.Equal(t, int64(2), transfers)
+}
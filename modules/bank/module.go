@@ -0,0 +1,115 @@
+package bank
+
+import (
+	"context"
+	"sync"
+
+	"github.com/cosmos/cosmos-sdk/codec"
+	banktypes "github.com/cosmos/cosmos-sdk/x/bank/types"
+	"gorm.io/gorm/schema"
+
+	"github.com/forbole/juno/v4/database"
+	"github.com/forbole/juno/v4/log"
+	"github.com/forbole/juno/v4/models"
+	"github.com/forbole/juno/v4/modules"
+	"github.com/forbole/juno/v4/node/remote"
+	"github.com/forbole/juno/v4/sink"
+	"github.com/forbole/juno/v4/types/config"
+)
+
+const (
+	ModuleName = "bank"
+)
+
+var (
+	_ modules.Module              = &Module{}
+	_ modules.PrepareTablesModule = &Module{}
+	_ modules.RollbackModule      = &Module{}
+	_ modules.ReadBackModule      = &Module{}
+	_ modules.ReplayModule        = &Module{}
+	_ modules.GenesisModule       = &Module{}
+	_ modules.BlockModule         = &Module{}
+	_ modules.EventModule         = &Module{}
+	_ modules.FastSyncModule      = &Module{}
+)
+
+// Module represents the bank module, which indexes the coins moved by the x/bank module and keeps a snapshot of the
+// balances of the accounts at every height they changed at.
+// The balances are computed from the previous snapshots, which are read back from the database, so the sql sink
+// is required for them to be correct. As the snapshots being written are read back as well, no state is kept
+// in memory, and the changes of an event are rolled back along with the database transaction handling it.
+type Module struct {
+	db   database.Database
+	sink sink.Sink
+	cdc  codec.JSONCodec
+
+	grpcCfg    *remote.GRPCConfig
+	clientOnce sync.Once
+	bankClient banktypes.QueryClient
+}
+
+// NewModule builds a new Module instance.
+// The codec is used to read the bank genesis state.
+func NewModule(cfg config.Config, db database.Database, sink sink.Sink, cdc codec.JSONCodec) *Module {
+	m := &Module{
+		db:   db,
+		sink: sink,
+		cdc:  cdc,
+	}
+	if details, ok := cfg.Node.Details.(*remote.Details); ok {
+		m.grpcCfg = details.GRPC
+	}
+	return m
+}
+
+// Name implements modules.Module
+func (m *Module) Name() string {
+	return ModuleName
+}
+
+// ReadsBack implements modules.ReadBackModule.
+// The balances are computed from the previous snapshots.
+func (m *Module) ReadsBack() bool {
+	return true
+}
+
+// CanReplay implements modules.ReplayModule.
+// A quarantined event cannot be handled again once the following heights have been processed, as the snapshots
+// written after it would not include its changes.
+func (m *Module) CanReplay() bool {
+	return false
+}
+
+// tables returns the tables the module writes to
+func (m *Module) tables() []schema.Tabler {
+	return []schema.Tabler{
+		&models.Transfer{},
+		&models.AccountBalance{},
+	}
+}
+
+// PrepareTables implements
+func (m *Module) PrepareTables() error {
+	return m.db.PrepareTables(context.TODO(), m.tables())
+}
+
+// RecreateTables implements
+func (m *Module) RecreateTables() error {
+	return m.db.RecreateTables(context.TODO(), m.tables())
+}
+
+// Rollback implements modules.RollbackModule
+func (m *Module) Rollback(ctx context.Context, height uint64) error {
+	transfers, err := m.db.DeleteAfter(ctx, &models.Transfer{}, "height", int64(height))
+	if err != nil {
+		return err
+	}
+
+	balances, err := m.db.DeleteAfter(ctx, &models.AccountBalance{}, "height", int64(height))
+	if err != nil {
+		return err
+	}
+
+	log.Infow("rolled back", "module", m.Name(), "height", height, "transfers", transfers, "balances", balances)
+	return nil
+}
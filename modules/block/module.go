@@ -45,6 +45,12 @@ func (m *Module) PrepareTables() error {
 		&models.Epoch{},
 
 		&models.Tx{},
+		&models.Validator{},
+		&models.PreCommit{},
+		&models.FailedHeight{},
+		&models.SyncProgress{},
+		&models.SyncGap{},
+		&models.QuarantinedEvent{},
 	})
 }
 
@@ -59,5 +65,11 @@ func (m *Module) RecreateTables() error {
 		&models.AverageBlockTimePerMinute{},
 
 		&models.Tx{},
+		&models.Validator{},
+		&models.PreCommit{},
+		&models.FailedHeight{},
+		&models.SyncProgress{},
+		&models.SyncGap{},
+		&models.QuarantinedEvent{},
 	})
 }
@@ -13,6 +13,7 @@ import (
 	"github.com/forbole/juno/v4/common"
 	"github.com/forbole/juno/v4/log"
 	"github.com/forbole/juno/v4/models"
+	"github.com/forbole/juno/v4/sink"
 )
 
 var (
@@ -87,7 +88,9 @@ func (m *Module) handleCreateBucket(ctx context.Context, block *tmctypes.ResultB
 		UpdateTime:   block.Block.Time.UTC().Unix(),
 	}
 
-	return m.db.SaveBucket(ctx, bucket)
+	return m.writeBucket(ctx, block, txHash, EventCreateBucket, bucket.BucketID, func() error {
+		return m.sink.Emit(ctx, sink.NewRecord(ModuleName, block.Block.Height, txHash, sink.OperationSave, bucket))
+	})
 }
 
 func (m *Module) handleDeleteBucket(ctx context.Context, block *tmctypes.ResultBlock, txHash common.Hash, deleteBucket *storagetypes.EventDeleteBucket) error {
@@ -101,7 +104,9 @@ func (m *Module) handleDeleteBucket(ctx context.Context, block *tmctypes.ResultB
 		UpdateTime:      block.Block.Time.UTC().Unix(),
 	}
 
-	return m.db.UpdateBucket(ctx, bucket)
+	return m.writeBucket(ctx, block, txHash, EventDeleteBucket, bucket.BucketID, func() error {
+		return m.sink.Emit(ctx, sink.NewRecord(ModuleName, block.Block.Height, txHash, sink.OperationUpdate, bucket))
+	})
 }
 
 func (m *Module) handleUpdateBucketInfo(ctx context.Context, block *tmctypes.ResultBlock, txHash common.Hash, updateBucket *storagetypes.EventUpdateBucketInfo) error {
@@ -116,5 +121,20 @@ func (m *Module) handleUpdateBucketInfo(ctx context.Context, block *tmctypes.Res
 		UpdateTime:       block.Block.Time.UTC().Unix(),
 	}
 
-	return m.db.UpdateBucket(ctx, bucket)
+	records := []*sink.Record{sink.NewRecord(ModuleName, block.Block.Height, txHash, sink.OperationUpdate, bucket)}
+	if updateBucket.ChargedReadQuotaBefore != updateBucket.ChargedReadQuotaAfter {
+		records = append(records, sink.NewRecord(ModuleName, block.Block.Height, txHash, sink.OperationSave, &models.BucketQuotaChange{
+			BucketID:               bucket.BucketID,
+			ChargedReadQuotaBefore: updateBucket.ChargedReadQuotaBefore,
+			ChargedReadQuotaAfter:  updateBucket.ChargedReadQuotaAfter,
+			OperatorAddress:        bucket.OperatorAddress,
+			Height:                 block.Block.Height,
+			TxHash:                 txHash,
+			UpdateTime:             bucket.UpdateTime,
+		}))
+	}
+
+	return m.writeBucket(ctx, block, txHash, EventUpdateBucketInfo, bucket.BucketID, func() error {
+		return m.sink.Emit(ctx, records...)
+	})
 }
@@ -0,0 +1,95 @@
+package bucket
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	storagetypes "github.com/bnb-chain/greenfield/x/storage/types"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/gogo/protobuf/proto"
+	"github.com/stretchr/testify/require"
+	tmctypes "github.com/tendermint/tendermint/rpc/core/types"
+	tmtypes "github.com/tendermint/tendermint/types"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+
+	"github.com/forbole/juno/v4/common"
+	"github.com/forbole/juno/v4/database"
+	"github.com/forbole/juno/v4/models"
+	"github.com/forbole/juno/v4/modules/history"
+	"github.com/forbole/juno/v4/sink"
+)
+
+const ownerAddress = "0x1111111111111111111111111111111111111111"
+
+func TestHandleUpdateBucketInfo_QuotaChanges(t *testing.T) {
+	gormDB, err := gorm.Open(sqlite.Open("file::memory:"), &gorm.Config{Logger: logger.Default.LogMode(logger.Silent)})
+	require.NoError(t, err)
+
+	db := &database.Impl{Db: gormDB}
+	m := &Module{db: db, sink: sink.NewSQLSink(db), historyCfg: &history.Config{Enabled: true}}
+	for _, table := range m.tables() {
+		require.NoError(t, gormDB.AutoMigrate(table))
+	}
+
+	ctx := context.Background()
+	handle := func(height int64, event proto.Message) {
+		sdkEvent, err := sdk.TypedEventToEvent(event)
+		require.NoError(t, err)
+
+		block := &tmctypes.ResultBlock{Block: &tmtypes.Block{Header: tmtypes.Header{Height: height, Time: time.Unix(height, 0)}}}
+		require.NoError(t, m.HandleEvent(ctx, block, common.HexToHash("0x01"), sdkEvent))
+	}
+	update := func(height int64, before, after uint64) {
+		handle(height, &storagetypes.EventUpdateBucketInfo{
+			OperatorAddress:        ownerAddress,
+			BucketName:             "bucket",
+			BucketId:               sdk.NewUint(1),
+			ChargedReadQuotaBefore: before,
+			ChargedReadQuotaAfter:  after,
+			PaymentAddressBefore:   ownerAddress,
+			PaymentAddressAfter:    ownerAddress,
+		})
+	}
+	changes := func() []*models.BucketQuotaChange {
+		var changes []*models.BucketQuotaChange
+		require.NoError(t, gormDB.Table((&models.BucketQuotaChange{}).TableName()).Order("id").Find(&changes).Error)
+		return changes
+	}
+
+	handle(10, &storagetypes.EventCreateBucket{
+		OwnerAddress:     ownerAddress,
+		BucketName:       "bucket",
+		BucketId:         sdk.NewUint(1),
+		ChargedReadQuota: 100,
+		PaymentAddress:   ownerAddress,
+	})
+	update(12, 100, 300)
+	// an update leaving the quota unchanged is not part of the history
+	update(13, 300, 300)
+	update(14, 300, 200)
+
+	quotaChanges := changes()
+	require.Len(t, quotaChanges, 2)
+	require.Equal(t, common.BigToHash(sdk.NewUint(1).BigInt()), quotaChanges[0].BucketID)
+	require.Equal(t, uint64(100), quotaChanges[0].ChargedReadQuotaBefore)
+	require.Equal(t, uint64(300), quotaChanges[0].ChargedReadQuotaAfter)
+	require.Equal(t, common.HexToAddress(ownerAddress), quotaChanges[0].OperatorAddress)
+	require.Equal(t, int64(12), quotaChanges[0].Height)
+	require.Equal(t, int64(12), quotaChanges[0].UpdateTime)
+	require.Equal(t, uint64(300), quotaChanges[1].ChargedReadQuotaBefore)
+	require.Equal(t, uint64(200), quotaChanges[1].ChargedReadQuotaAfter)
+	require.Equal(t, int64(14), quotaChanges[1].Height)
+
+	bucket, err := db.GetBucketByID(ctx, common.BigToHash(sdk.NewUint(1).BigInt()))
+	require.NoError(t, err)
+	require.Equal(t, uint64(200), bucket.ChargedReadQuota)
+
+	// the changes after the height are forgotten on rollback
+	require.NoError(t, m.Rollback(ctx, 13))
+	quotaChanges = changes()
+	require.Len(t, quotaChanges, 1)
+	require.Equal(t, int64(12), quotaChanges[0].Height)
+}
@@ -0,0 +1,45 @@
+package bucket
+
+import (
+	"context"
+
+	tmctypes "github.com/tendermint/tendermint/rpc/core/types"
+
+	"github.com/forbole/juno/v4/common"
+	"github.com/forbole/juno/v4/models"
+	"github.com/forbole/juno/v4/modules/history"
+)
+
+// writeBucket applies the given write to the bucket with the given id and, when the history mode is enabled,
+// appends a version row describing the change caused by the event with the given type.
+func (m *Module) writeBucket(ctx context.Context, block *tmctypes.ResultBlock, txHash common.Hash, eventType string, bucketID common.Hash, write func() error) error {
+	if !m.historyCfg.Enabled {
+		return write()
+	}
+
+	return history.Write(ctx,
+		history.One(func(ctx context.Context) (*models.Bucket, error) {
+			return m.db.GetBucketByID(ctx, bucketID)
+		}),
+		func(bucket *models.Bucket) common.Hash { return bucket.BucketID },
+		write,
+		func(bucket *models.Bucket, diff, state string) *models.BucketVersion {
+			return &models.BucketVersion{
+				BucketID:  bucket.BucketID,
+				Height:    block.Block.Height,
+				TxHash:    txHash,
+				EventType: eventType,
+				Diff:      diff,
+				State:     state,
+			}
+		},
+		func(ctx context.Context, versions []*models.BucketVersion) error {
+			for _, version := range versions {
+				if err := m.db.SaveBucketVersion(ctx, version); err != nil {
+					return err
+				}
+			}
+			return nil
+		},
+	)
+}
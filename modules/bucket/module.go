@@ -6,8 +6,12 @@ import (
 	"gorm.io/gorm/schema"
 
 	"github.com/forbole/juno/v4/database"
+	"github.com/forbole/juno/v4/log"
 	"github.com/forbole/juno/v4/models"
 	"github.com/forbole/juno/v4/modules"
+	"github.com/forbole/juno/v4/modules/history"
+	"github.com/forbole/juno/v4/sink"
+	"github.com/forbole/juno/v4/types/config"
 )
 
 const (
@@ -17,17 +21,33 @@ const (
 var (
 	_ modules.Module              = &Module{}
 	_ modules.PrepareTablesModule = &Module{}
+	_ modules.RollbackModule      = &Module{}
+	_ modules.ReadBackModule      = &Module{}
 )
 
 // Module represents the bucket module
 type Module struct {
-	db database.Database
+	db         database.Database
+	sink       sink.Sink
+	historyCfg *history.Config
 }
 
 // NewModule builds a new Module instance
-func NewModule(db database.Database) *Module {
+func NewModule(cfg config.Config, db database.Database, sink sink.Sink) *Module {
+	bz, err := cfg.GetBytes()
+	if err != nil {
+		panic(err)
+	}
+
+	historyCfg, err := history.ParseConfig(bz)
+	if err != nil {
+		panic(err)
+	}
+
 	return &Module{
-		db: db,
+		db:         db,
+		sink:       sink,
+		historyCfg: historyCfg,
 	}
 }
 
@@ -36,12 +56,78 @@ func (m *Module) Name() string {
 	return ModuleName
 }
 
+// ReadsBack implements modules.ReadBackModule.
+// The history mode reads the buckets before and after each change.
+func (m *Module) ReadsBack() bool {
+	return m.historyCfg.Enabled
+}
+
 // PrepareTables implements
 func (m *Module) PrepareTables() error {
-	return m.db.PrepareTables(context.TODO(), []schema.Tabler{&models.Bucket{}})
+	return m.db.PrepareTables(context.TODO(), m.tables())
 }
 
 // RecreateTables implements
 func (m *Module) RecreateTables() error {
-	return m.db.RecreateTables(context.TODO(), []schema.Tabler{&models.Bucket{}})
+	return m.db.RecreateTables(context.TODO(), m.tables())
+}
+
+// tables returns the tables handled by the module, including the history one when enabled
+func (m *Module) tables() []schema.Tabler {
+	if m.historyCfg.Enabled {
+		return []schema.Tabler{&models.Bucket{}, &models.BucketQuotaChange{}, &models.BucketVersion{}}
+	}
+	return []schema.Tabler{&models.Bucket{}, &models.BucketQuotaChange{}}
+}
+
+// Rollback implements modules.RollbackModule.
+// The charged read quota changes after the height are deleted.
+// The buckets updated after the height are restored from their versions when the history mode is enabled,
+// otherwise a modules.StaleRowsError is returned for them.
+func (m *Module) Rollback(ctx context.Context, height uint64) error {
+	deleted, err := m.db.DeleteAfter(ctx, &models.Bucket{}, "create_at", int64(height))
+	if err != nil {
+		return err
+	}
+
+	_, err = m.db.DeleteAfter(ctx, &models.BucketQuotaChange{}, "height", int64(height))
+	if err != nil {
+		return err
+	}
+
+	if !m.historyCfg.Enabled {
+		err = modules.CheckNotUpdated(ctx, m.db, m.Name(), modules.UpdatedAfter{
+			Table: &models.Bucket{}, Column: "update_at", Value: int64(height),
+		})
+		if err != nil {
+			return err
+		}
+
+		log.Infow("rolled back", "module", m.Name(), "height", height, "deleted", deleted)
+		return nil
+	}
+
+	var buckets []*models.Bucket
+	err = m.db.ListAfter(ctx, &models.Bucket{}, "update_at", int64(height), &buckets)
+	if err != nil {
+		return err
+	}
+
+	unknown, err := history.Restore(ctx, buckets, height, func(ctx context.Context, bucket *models.Bucket, height uint64) (*models.Bucket, error) {
+		return m.db.GetBucketAtHeight(ctx, bucket.BucketID, height)
+	}, m.db.SaveBucket)
+	if err != nil {
+		return err
+	}
+	if unknown > 0 {
+		return &modules.StaleRowsError{Module: m.Name(), Rows: unknown}
+	}
+
+	_, err = m.db.DeleteAfter(ctx, &models.BucketVersion{}, "height", int64(height))
+	if err != nil {
+		return err
+	}
+
+	log.Infow("rolled back", "module", m.Name(), "height", height, "deleted", deleted, "restored", len(buckets))
+	return nil
 }
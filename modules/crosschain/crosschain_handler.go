@@ -0,0 +1,220 @@
+package crosschain
+
+import (
+	"context"
+	"errors"
+
+	"github.com/bnb-chain/greenfield/types/resource"
+	storagetypes "github.com/bnb-chain/greenfield/x/storage/types"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	crosschaintypes "github.com/cosmos/cosmos-sdk/x/crosschain/types"
+	oracletypes "github.com/cosmos/cosmos-sdk/x/oracle/types"
+	"github.com/gogo/protobuf/proto"
+	abci "github.com/tendermint/tendermint/abci/types"
+	tmctypes "github.com/tendermint/tendermint/rpc/core/types"
+
+	"github.com/forbole/juno/v4/common"
+	"github.com/forbole/juno/v4/log"
+	"github.com/forbole/juno/v4/models"
+	"github.com/forbole/juno/v4/sink"
+)
+
+var (
+	EventCrossChain         = proto.MessageName(&crosschaintypes.EventCrossChain{})
+	EventPackageClaim       = proto.MessageName(&oracletypes.EventPackageClaim{})
+	EventMirrorBucket       = proto.MessageName(&storagetypes.EventMirrorBucket{})
+	EventMirrorBucketResult = proto.MessageName(&storagetypes.EventMirrorBucketResult{})
+	EventMirrorObject       = proto.MessageName(&storagetypes.EventMirrorObject{})
+	EventMirrorObjectResult = proto.MessageName(&storagetypes.EventMirrorObjectResult{})
+	EventMirrorGroup        = proto.MessageName(&storagetypes.EventMirrorGroup{})
+	EventMirrorGroupResult  = proto.MessageName(&storagetypes.EventMirrorGroupResult{})
+)
+
+var crossChainEvents = map[string]bool{
+	EventCrossChain:         true,
+	EventPackageClaim:       true,
+	EventMirrorBucket:       true,
+	EventMirrorBucketResult: true,
+	EventMirrorObject:       true,
+	EventMirrorObjectResult: true,
+	EventMirrorGroup:        true,
+	EventMirrorGroupResult:  true,
+}
+
+func (m *Module) HandleEvent(ctx context.Context, block *tmctypes.ResultBlock, txHash common.Hash, event sdk.Event) error {
+	if !crossChainEvents[event.Type] {
+		return nil
+	}
+
+	typedEvent, err := sdk.ParseTypedEvent(abci.Event(event))
+	if err != nil {
+		log.Errorw("parse typed events error", "module", m.Name(), "event", event, "err", err)
+		return err
+	}
+
+	switch event.Type {
+	case EventCrossChain:
+		crossChain, ok := typedEvent.(*crosschaintypes.EventCrossChain)
+		if !ok {
+			log.Errorw("type assert error", "type", "EventCrossChain", "event", typedEvent)
+			return errors.New("cross chain event assert error")
+		}
+		return m.handleCrossChain(ctx, block, txHash, crossChain)
+	case EventPackageClaim:
+		packageClaim, ok := typedEvent.(*oracletypes.EventPackageClaim)
+		if !ok {
+			log.Errorw("type assert error", "type", "EventPackageClaim", "event", typedEvent)
+			return errors.New("package claim event assert error")
+		}
+		return m.handlePackageClaim(ctx, block, txHash, packageClaim)
+	case EventMirrorBucket:
+		mirrorBucket, ok := typedEvent.(*storagetypes.EventMirrorBucket)
+		if !ok {
+			log.Errorw("type assert error", "type", "EventMirrorBucket", "event", typedEvent)
+			return errors.New("mirror bucket event assert error")
+		}
+		return m.handleMirror(ctx, block, txHash, &models.Mirror{
+			ResourceType:    resource.RESOURCE_TYPE_BUCKET.String(),
+			ResourceID:      common.BigToHash(mirrorBucket.BucketId.BigInt()),
+			ResourceName:    mirrorBucket.BucketName,
+			OperatorAddress: common.HexToAddress(mirrorBucket.OperatorAddress),
+		}, storagetypes.BucketChannelId)
+	case EventMirrorBucketResult:
+		mirrorBucketResult, ok := typedEvent.(*storagetypes.EventMirrorBucketResult)
+		if !ok {
+			log.Errorw("type assert error", "type", "EventMirrorBucketResult", "event", typedEvent)
+			return errors.New("mirror bucket result event assert error")
+		}
+		return m.handleMirrorResult(ctx, block, txHash, resource.RESOURCE_TYPE_BUCKET,
+			common.BigToHash(mirrorBucketResult.BucketId.BigInt()), mirrorBucketResult.Status)
+	case EventMirrorObject:
+		mirrorObject, ok := typedEvent.(*storagetypes.EventMirrorObject)
+		if !ok {
+			log.Errorw("type assert error", "type", "EventMirrorObject", "event", typedEvent)
+			return errors.New("mirror object event assert error")
+		}
+		return m.handleMirror(ctx, block, txHash, &models.Mirror{
+			ResourceType:    resource.RESOURCE_TYPE_OBJECT.String(),
+			ResourceID:      common.BigToHash(mirrorObject.ObjectId.BigInt()),
+			ResourceName:    mirrorObject.ObjectName,
+			BucketName:      mirrorObject.BucketName,
+			OperatorAddress: common.HexToAddress(mirrorObject.OperatorAddress),
+		}, storagetypes.ObjectChannelId)
+	case EventMirrorObjectResult:
+		mirrorObjectResult, ok := typedEvent.(*storagetypes.EventMirrorObjectResult)
+		if !ok {
+			log.Errorw("type assert error", "type", "EventMirrorObjectResult", "event", typedEvent)
+			return errors.New("mirror object result event assert error")
+		}
+		return m.handleMirrorResult(ctx, block, txHash, resource.RESOURCE_TYPE_OBJECT,
+			common.BigToHash(mirrorObjectResult.ObjectId.BigInt()), mirrorObjectResult.Status)
+	case EventMirrorGroup:
+		mirrorGroup, ok := typedEvent.(*storagetypes.EventMirrorGroup)
+		if !ok {
+			log.Errorw("type assert error", "type", "EventMirrorGroup", "event", typedEvent)
+			return errors.New("mirror group event assert error")
+		}
+		return m.handleMirror(ctx, block, txHash, &models.Mirror{
+			ResourceType:    resource.RESOURCE_TYPE_GROUP.String(),
+			ResourceID:      common.BigToHash(mirrorGroup.GroupId.BigInt()),
+			ResourceName:    mirrorGroup.GroupName,
+			OperatorAddress: common.HexToAddress(mirrorGroup.OwnerAddress),
+		}, storagetypes.GroupChannelId)
+	case EventMirrorGroupResult:
+		mirrorGroupResult, ok := typedEvent.(*storagetypes.EventMirrorGroupResult)
+		if !ok {
+			log.Errorw("type assert error", "type", "EventMirrorGroupResult", "event", typedEvent)
+			return errors.New("mirror group result event assert error")
+		}
+		return m.handleMirrorResult(ctx, block, txHash, resource.RESOURCE_TYPE_GROUP,
+			common.BigToHash(mirrorGroupResult.GroupId.BigInt()), mirrorGroupResult.Status)
+	}
+
+	return nil
+}
+
+func (m *Module) handleCrossChain(ctx context.Context, block *tmctypes.ResultBlock, txHash common.Hash, crossChain *crosschaintypes.EventCrossChain) error {
+	pkg := &models.CrossChainPackage{
+		Direction:     models.CrossChainPackageSend,
+		SrcChainID:    crossChain.SrcChainId,
+		DestChainID:   crossChain.DestChainId,
+		ChannelID:     crossChain.ChannelId,
+		Sequence:      crossChain.Sequence,
+		PackageType:   crossChain.PackageType,
+		Timestamp:     crossChain.Timestamp,
+		Payload:       crossChain.PackageLoad,
+		RelayerFee:    crossChain.RelayerFee,
+		AckRelayerFee: crossChain.AckRelayerFee,
+		AckSequence:   -1,
+
+		Height: block.Block.Height,
+		TxHash: txHash,
+	}
+
+	return m.sink.Emit(ctx, sink.NewRecord(ModuleName, block.Block.Height, txHash, sink.OperationSave, pkg))
+}
+
+func (m *Module) handlePackageClaim(ctx context.Context, block *tmctypes.ResultBlock, txHash common.Hash, packageClaim *oracletypes.EventPackageClaim) error {
+	pkg := &models.CrossChainPackage{
+		Direction:     models.CrossChainPackageReceive,
+		SrcChainID:    packageClaim.SrcChainId,
+		DestChainID:   packageClaim.DestChainId,
+		ChannelID:     packageClaim.ChannelId,
+		Sequence:      packageClaim.ReceiveSequence,
+		PackageType:   packageClaim.PackageType,
+		RelayerFee:    packageClaim.RelayerFee,
+		AckRelayerFee: packageClaim.AckRelayerFee,
+		AckSequence:   packageClaim.SendSequence,
+		Crash:         packageClaim.Crash,
+		ErrorMsg:      packageClaim.ErrorMsg,
+
+		Height: block.Block.Height,
+		TxHash: txHash,
+	}
+
+	return m.sink.Emit(ctx, sink.NewRecord(ModuleName, block.Block.Height, txHash, sink.OperationSave, pkg))
+}
+
+// handleMirror stores the given mirror request. The SYN package carrying the request is sent on the given channel
+// right before the mirror event is emitted, so it is the last package sent on the channel by the transaction so far.
+func (m *Module) handleMirror(ctx context.Context, block *tmctypes.ResultBlock, txHash common.Hash, mirror *models.Mirror, channelID sdk.ChannelID) error {
+	pkg, err := m.db.GetLastSentPackage(ctx, txHash, uint32(channelID))
+	if err != nil {
+		return err
+	}
+	if pkg != nil {
+		mirror.DestChainID = pkg.DestChainID
+		mirror.ChannelID = pkg.ChannelID
+		mirror.Sequence = pkg.Sequence
+	} else {
+		log.Warnw("mirror package not found", "module", m.Name(), "resource_type", mirror.ResourceType,
+			"resource_id", mirror.ResourceID.Hex(), "tx_hash", txHash.Hex())
+	}
+
+	mirror.Status = models.MirrorStatusPending
+	mirror.CreateAt = block.Block.Height
+	mirror.CreateTxHash = txHash
+	mirror.CreateTime = block.Block.Time.UTC().Unix()
+	mirror.UpdateAt = block.Block.Height
+	mirror.UpdateTxHash = txHash
+	mirror.UpdateTime = block.Block.Time.UTC().Unix()
+
+	return m.sink.Emit(ctx, sink.NewRecord(ModuleName, block.Block.Height, txHash, sink.OperationSave, mirror))
+}
+
+// handleMirrorResult updates the mirror request of the given resource with the status carried by the ACK package
+func (m *Module) handleMirrorResult(ctx context.Context, block *tmctypes.ResultBlock, txHash common.Hash, resourceType resource.ResourceType, resourceID common.Hash, status uint32) error {
+	mirror := &models.Mirror{
+		ResourceType: resourceType.String(),
+		ResourceID:   resourceID,
+		Status:       models.MirrorStatusFailed,
+		UpdateAt:     block.Block.Height,
+		UpdateTxHash: txHash,
+		UpdateTime:   block.Block.Time.UTC().Unix(),
+	}
+	if status == storagetypes.StatusSuccess {
+		mirror.Status = models.MirrorStatusSuccess
+	}
+
+	return m.sink.Emit(ctx, sink.NewRecord(ModuleName, block.Block.Height, txHash, sink.OperationUpdate, mirror))
+}
@@ -0,0 +1,155 @@
+package crosschain
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/bnb-chain/greenfield/types/resource"
+	storagetypes "github.com/bnb-chain/greenfield/x/storage/types"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	crosschaintypes "github.com/cosmos/cosmos-sdk/x/crosschain/types"
+	"github.com/gogo/protobuf/proto"
+	"github.com/stretchr/testify/require"
+	tmctypes "github.com/tendermint/tendermint/rpc/core/types"
+	tmtypes "github.com/tendermint/tendermint/types"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+
+	"github.com/forbole/juno/v4/common"
+	"github.com/forbole/juno/v4/database"
+	"github.com/forbole/juno/v4/models"
+	"github.com/forbole/juno/v4/sink"
+)
+
+const operatorAddress = "0x1111111111111111111111111111111111111111"
+
+// newTestModule returns a module writing to an in-memory database through the sql sink
+func newTestModule(t *testing.T) (*Module, *database.Impl) {
+	t.Helper()
+
+	gormDB, err := gorm.Open(sqlite.Open("file::memory:"), &gorm.Config{Logger: logger.Default.LogMode(logger.Silent)})
+	require.NoError(t, err)
+
+	m := NewModule(nil, nil)
+	for _, table := range m.tables() {
+		require.NoError(t, gormDB.AutoMigrate(table))
+	}
+
+	db := &database.Impl{Db: gormDB}
+	m.db = db
+	m.sink = sink.NewSQLSink(db)
+	return m, db
+}
+
+// handle makes the module handle the given event at the given height
+func handle(t *testing.T, m *Module, height int64, txHash common.Hash, event proto.Message) {
+	t.Helper()
+
+	sdkEvent, err := sdk.TypedEventToEvent(event)
+	require.NoError(t, err)
+
+	block := &tmctypes.ResultBlock{Block: &tmtypes.Block{Header: tmtypes.Header{Height: height, Time: time.Unix(1000, 0)}}}
+	require.NoError(t, m.HandleEvent(context.Background(), block, txHash, sdkEvent))
+}
+
+func TestHandleEvent_Mirror(t *testing.T) {
+	m, db := newTestModule(t)
+	ctx := context.Background()
+	txHash := common.HexToHash("0x01")
+	bucketID := common.BigToHash(sdk.NewUint(7).BigInt())
+
+	// the SYN package of the mirror request is sent right before the mirror event, along with an older package of
+	// the same transaction on another channel
+	handle(t, m, 10, txHash, &crosschaintypes.EventCrossChain{
+		DestChainId: 97,
+		ChannelId:   uint32(storagetypes.GroupChannelId),
+		Sequence:    3,
+	})
+	handle(t, m, 10, txHash, &crosschaintypes.EventCrossChain{
+		DestChainId: 97,
+		ChannelId:   uint32(storagetypes.BucketChannelId),
+		Sequence:    5,
+	})
+	handle(t, m, 10, txHash, &storagetypes.EventMirrorBucket{
+		OperatorAddress: operatorAddress,
+		BucketName:      "bucket",
+		BucketId:        sdk.NewUint(7),
+	})
+
+	mirror, err := db.GetMirror(ctx, resource.RESOURCE_TYPE_BUCKET.String(), bucketID)
+	require.NoError(t, err)
+	require.NotNil(t, mirror)
+	require.Equal(t, models.MirrorStatusPending, mirror.Status)
+	require.Equal(t, uint32(97), mirror.DestChainID)
+	require.Equal(t, uint32(storagetypes.BucketChannelId), mirror.ChannelID)
+	require.Equal(t, uint64(5), mirror.Sequence)
+	require.Equal(t, common.HexToAddress(operatorAddress), mirror.OperatorAddress)
+	require.Equal(t, int64(10), mirror.CreateAt)
+
+	// the ACK package reports the result of the mirror
+	handle(t, m, 12, common.HexToHash("0x02"), &storagetypes.EventMirrorBucketResult{
+		Status:     storagetypes.StatusSuccess,
+		BucketName: "bucket",
+		BucketId:   sdk.NewUint(7),
+	})
+
+	mirror, err = db.GetMirror(ctx, resource.RESOURCE_TYPE_BUCKET.String(), bucketID)
+	require.NoError(t, err)
+	require.Equal(t, models.MirrorStatusSuccess, mirror.Status)
+	require.Equal(t, uint64(5), mirror.Sequence)
+	require.Equal(t, int64(10), mirror.CreateAt)
+	require.Equal(t, int64(12), mirror.UpdateAt)
+	require.Equal(t, common.HexToHash("0x02"), mirror.UpdateTxHash)
+}
+
+func TestHandleEvent_MirrorPackageNotFound(t *testing.T) {
+	m, db := newTestModule(t)
+	groupID := common.BigToHash(sdk.NewUint(3).BigInt())
+
+	// the only package of the transaction is sent on another channel
+	handle(t, m, 10, common.HexToHash("0x01"), &crosschaintypes.EventCrossChain{
+		DestChainId: 97,
+		ChannelId:   uint32(storagetypes.BucketChannelId),
+		Sequence:    5,
+	})
+	handle(t, m, 10, common.HexToHash("0x01"), &storagetypes.EventMirrorGroup{
+		OwnerAddress: operatorAddress,
+		GroupName:    "group",
+		GroupId:      sdk.NewUint(3),
+	})
+
+	// the mirror is still stored, without any package
+	mirror, err := db.GetMirror(context.Background(), resource.RESOURCE_TYPE_GROUP.String(), groupID)
+	require.NoError(t, err)
+	require.NotNil(t, mirror)
+	require.Equal(t, models.MirrorStatusPending, mirror.Status)
+	require.Zero(t, mirror.DestChainID)
+	require.Zero(t, mirror.ChannelID)
+	require.Zero(t, mirror.Sequence)
+}
+
+func TestHandleEvent_MirrorFailed(t *testing.T) {
+	m, db := newTestModule(t)
+	objectID := common.BigToHash(sdk.NewUint(9).BigInt())
+
+	handle(t, m, 10, common.HexToHash("0x01"), &storagetypes.EventMirrorObject{
+		OperatorAddress: operatorAddress,
+		BucketName:      "bucket",
+		ObjectName:      "object",
+		ObjectId:        sdk.NewUint(9),
+	})
+	handle(t, m, 12, common.HexToHash("0x02"), &storagetypes.EventMirrorObjectResult{
+		Status:     storagetypes.StatusFail,
+		BucketName: "bucket",
+		ObjectName: "object",
+		ObjectId:   sdk.NewUint(9),
+	})
+
+	mirror, err := db.GetMirror(context.Background(), resource.RESOURCE_TYPE_OBJECT.String(), objectID)
+	require.NoError(t, err)
+	require.Equal(t, models.MirrorStatusFailed, mirror.Status)
+	require.Equal(t, "bucket", mirror.BucketName)
+	require.Equal(t, int64(12), mirror.UpdateAt)
+}
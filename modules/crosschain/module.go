@@ -0,0 +1,88 @@
+package crosschain
+
+import (
+	"context"
+
+	"gorm.io/gorm/schema"
+
+	"github.com/forbole/juno/v4/database"
+	"github.com/forbole/juno/v4/log"
+	"github.com/forbole/juno/v4/models"
+	"github.com/forbole/juno/v4/modules"
+	"github.com/forbole/juno/v4/sink"
+)
+
+const (
+	ModuleName = "crosschain"
+)
+
+var (
+	_ modules.Module              = &Module{}
+	_ modules.PrepareTablesModule = &Module{}
+	_ modules.RollbackModule      = &Module{}
+	_ modules.ReadBackModule      = &Module{}
+)
+
+// Module represents the cross-chain module, indexing the packages exchanged with the destination chain
+// along with the requests to mirror resources to it
+type Module struct {
+	db   database.Database
+	sink sink.Sink
+}
+
+// NewModule builds a new Module instance
+func NewModule(db database.Database, sink sink.Sink) *Module {
+	return &Module{
+		db:   db,
+		sink: sink,
+	}
+}
+
+// Name implements modules.Module
+func (m *Module) Name() string {
+	return ModuleName
+}
+
+// ReadsBack implements modules.ReadBackModule.
+// The acknowledgements are matched with the packages sent before.
+func (m *Module) ReadsBack() bool {
+	return true
+}
+
+// tables returns the tables the module writes to
+func (m *Module) tables() []schema.Tabler {
+	return []schema.Tabler{&models.CrossChainPackage{}, &models.Mirror{}}
+}
+
+// PrepareTables implements
+func (m *Module) PrepareTables() error {
+	return m.db.PrepareTables(context.TODO(), m.tables())
+}
+
+// RecreateTables implements
+func (m *Module) RecreateTables() error {
+	return m.db.RecreateTables(context.TODO(), m.tables())
+}
+
+// Rollback implements modules.RollbackModule
+func (m *Module) Rollback(ctx context.Context, height uint64) error {
+	packages, err := m.db.DeleteAfter(ctx, &models.CrossChainPackage{}, "height", int64(height))
+	if err != nil {
+		return err
+	}
+
+	mirrors, err := m.db.DeleteAfter(ctx, &models.Mirror{}, "create_at", int64(height))
+	if err != nil {
+		return err
+	}
+
+	err = modules.CheckNotUpdated(ctx, m.db, m.Name(), modules.UpdatedAfter{
+		Table: &models.Mirror{}, Column: "update_at", Value: int64(height),
+	})
+	if err != nil {
+		return err
+	}
+
+	log.Infow("rolled back", "module", m.Name(), "height", height, "packages", packages, "mirrors", mirrors)
+	return nil
+}
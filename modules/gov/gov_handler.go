@@ -0,0 +1,340 @@
+package gov
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"time"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/cosmos/cosmos-sdk/types/query"
+	govtypes "github.com/cosmos/cosmos-sdk/x/gov/types"
+	govv1 "github.com/cosmos/cosmos-sdk/x/gov/types/v1"
+	govv1beta1 "github.com/cosmos/cosmos-sdk/x/gov/types/v1beta1"
+	tmctypes "github.com/tendermint/tendermint/rpc/core/types"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/forbole/juno/v4/common"
+	"github.com/forbole/juno/v4/log"
+	"github.com/forbole/juno/v4/models"
+	"github.com/forbole/juno/v4/node/remote"
+	"github.com/forbole/juno/v4/sink"
+	"github.com/forbole/juno/v4/types"
+)
+
+// HandleMsg implements modules.MessageModule.
+// Both the v1 and the legacy v1beta1 messages are handled, as they are served by the same keeper.
+func (m *Module) HandleMsg(ctx context.Context, block *tmctypes.ResultBlock, index int, msg sdk.Msg, tx *types.Tx) error {
+	if !tx.Successful() {
+		return nil
+	}
+
+	txHash := common.HexToHash(tx.TxHash)
+	switch msg := msg.(type) {
+	case *govv1.MsgSubmitProposal:
+		return m.handleSubmitProposal(ctx, block, txHash, index, tx, msg.Proposer, msg.InitialDeposit)
+	case *govv1beta1.MsgSubmitProposal:
+		return m.handleSubmitProposal(ctx, block, txHash, index, tx, msg.Proposer, msg.InitialDeposit)
+	case *govv1.MsgDeposit:
+		return m.handleDeposit(ctx, block, txHash, msg.ProposalId, msg.Depositor, msg.Amount)
+	case *govv1beta1.MsgDeposit:
+		return m.handleDeposit(ctx, block, txHash, msg.ProposalId, msg.Depositor, msg.Amount)
+	case *govv1.MsgVote:
+		options := []models.VoteOption{{Option: msg.Option.String(), Weight: sdk.OneDec().String()}}
+		return m.handleVote(ctx, block, txHash, msg.ProposalId, msg.Voter, options, msg.Metadata)
+	case *govv1beta1.MsgVote:
+		options := []models.VoteOption{{Option: msg.Option.String(), Weight: sdk.OneDec().String()}}
+		return m.handleVote(ctx, block, txHash, msg.ProposalId, msg.Voter, options, "")
+	case *govv1.MsgVoteWeighted:
+		options := make([]models.VoteOption, len(msg.Options))
+		for i, option := range msg.Options {
+			options[i] = models.VoteOption{Option: option.Option.String(), Weight: option.Weight}
+		}
+		return m.handleVote(ctx, block, txHash, msg.ProposalId, msg.Voter, options, msg.Metadata)
+	case *govv1beta1.MsgVoteWeighted:
+		options := make([]models.VoteOption, len(msg.Options))
+		for i, option := range msg.Options {
+			options[i] = models.VoteOption{Option: option.Option.String(), Weight: option.Weight.String()}
+		}
+		return m.handleVote(ctx, block, txHash, msg.ProposalId, msg.Voter, options, "")
+	}
+
+	return nil
+}
+
+// HandleBlock implements modules.BlockModule.
+// Proposals reaching the end of their voting period, or of their deposit period without the minimum deposit,
+// are handled by the end blocker, so their final state is indexed here.
+func (m *Module) HandleBlock(
+	ctx context.Context, block *tmctypes.ResultBlock, results *tmctypes.ResultBlockResults, _ []*types.Tx, _ *tmctypes.ResultValidators,
+) error {
+	if results == nil {
+		return nil
+	}
+
+	for _, event := range results.EndBlockEvents {
+		if event.Type != govtypes.EventTypeActiveProposal && event.Type != govtypes.EventTypeInactiveProposal {
+			continue
+		}
+
+		attr, err := types.FindAttributeByKey(event, govtypes.AttributeKeyProposalID)
+		if err != nil {
+			return err
+		}
+		proposalID, err := strconv.ParseUint(string(attr.Value), 10, 64)
+		if err != nil {
+			return fmt.Errorf("invalid proposal id %s: %s", attr.Value, err)
+		}
+
+		err = m.refreshProposal(ctx, block, common.Hash{}, proposalID)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (m *Module) handleSubmitProposal(
+	ctx context.Context, block *tmctypes.ResultBlock, txHash common.Hash, index int, tx *types.Tx, proposer string, initialDeposit sdk.Coins,
+) error {
+	// the id of the proposal is only known from the events of the message
+	event, err := tx.FindEventByType(index, govtypes.EventTypeSubmitProposal)
+	if err != nil {
+		return err
+	}
+	value, err := tx.FindAttributeByKey(event, govtypes.AttributeKeyProposalID)
+	if err != nil {
+		return err
+	}
+	proposalID, err := strconv.ParseUint(value, 10, 64)
+	if err != nil {
+		return fmt.Errorf("invalid proposal id %s: %s", value, err)
+	}
+
+	p, err := m.queryProposal(ctx, block.Block.Height, proposalID)
+	if err != nil {
+		return err
+	}
+	if p == nil {
+		return fmt.Errorf("proposal %d not found at height %d", proposalID, block.Block.Height)
+	}
+
+	proposal, err := newProposal(p)
+	if err != nil {
+		return err
+	}
+	proposal.Proposer = common.HexToAddress(proposer)
+	proposal.CreateAt = block.Block.Height
+	proposal.CreateTxHash = txHash
+	proposal.UpdateAt = block.Block.Height
+	proposal.UpdateTxHash = txHash
+
+	records := []*sink.Record{sink.NewRecord(ModuleName, block.Block.Height, txHash, sink.OperationSave, proposal)}
+	if !initialDeposit.IsZero() {
+		records = append(records, sink.NewRecord(ModuleName, block.Block.Height, txHash, sink.OperationSave, &models.ProposalDeposit{
+			ProposalID: proposalID,
+			Depositor:  common.HexToAddress(proposer),
+			Amount:     initialDeposit.String(),
+			Height:     block.Block.Height,
+			TxHash:     txHash,
+		}))
+	}
+	return m.sink.Emit(ctx, records...)
+}
+
+func (m *Module) handleDeposit(ctx context.Context, block *tmctypes.ResultBlock, txHash common.Hash, proposalID uint64, depositor string, amount sdk.Coins) error {
+	deposit := &models.ProposalDeposit{
+		ProposalID: proposalID,
+		Depositor:  common.HexToAddress(depositor),
+		Amount:     amount.String(),
+		Height:     block.Block.Height,
+		TxHash:     txHash,
+	}
+
+	err := m.sink.Emit(ctx, sink.NewRecord(ModuleName, block.Block.Height, txHash, sink.OperationSave, deposit))
+	if err != nil {
+		return err
+	}
+
+	// the deposit can start the voting period of the proposal
+	return m.refreshProposal(ctx, block, txHash, proposalID)
+}
+
+func (m *Module) handleVote(
+	ctx context.Context, block *tmctypes.ResultBlock, txHash common.Hash, proposalID uint64, voter string, options []models.VoteOption, metadata string,
+) error {
+	bz, err := json.Marshal(options)
+	if err != nil {
+		return err
+	}
+
+	vote := &models.ProposalVote{
+		ProposalID: proposalID,
+		Voter:      common.HexToAddress(voter),
+		Options:    string(bz),
+		Metadata:   metadata,
+		Height:     block.Block.Height,
+		TxHash:     txHash,
+	}
+
+	return m.sink.Emit(ctx, sink.NewRecord(ModuleName, block.Block.Height, txHash, sink.OperationSave, vote))
+}
+
+// refreshProposal stores the state of the given proposal at the height of the block.
+// Proposals not found were removed for not reaching the minimum deposit in time, so they are marked as dropped.
+func (m *Module) refreshProposal(ctx context.Context, block *tmctypes.ResultBlock, txHash common.Hash, proposalID uint64) error {
+	p, err := m.queryProposal(ctx, block.Block.Height, proposalID)
+	if err != nil {
+		return err
+	}
+
+	var proposal *models.Proposal
+	if p != nil {
+		proposal, err = newProposal(p)
+		if err != nil {
+			return err
+		}
+	} else {
+		proposal, err = m.db.GetProposal(ctx, proposalID)
+		if err != nil {
+			return err
+		}
+		if proposal == nil {
+			log.Warnw("dropped proposal not found", "module", m.Name(), "proposal_id", proposalID)
+			proposal = &models.Proposal{ProposalID: proposalID}
+		}
+		proposal.ID = 0
+		proposal.Status = models.ProposalStatusDropped
+	}
+	proposal.UpdateAt = block.Block.Height
+	proposal.UpdateTxHash = txHash
+
+	return m.sink.Emit(ctx, sink.NewRecord(ModuleName, block.Block.Height, txHash, sink.OperationSave, proposal))
+}
+
+// queryClient returns the client used to query the proposals, which is only built when first needed
+func (m *Module) queryClient() (govv1.QueryClient, error) {
+	if m.grpcCfg == nil {
+		return nil, fmt.Errorf("the %s module requires a remote node", m.Name())
+	}
+
+	var err error
+	m.clientOnce.Do(func() {
+		conn, connErr := remote.CreateGrpcConnection(m.grpcCfg)
+		if connErr != nil {
+			err = fmt.Errorf("error while connecting to the gRPC endpoint: %s", connErr)
+			return
+		}
+		m.client = govv1.NewQueryClient(conn)
+	})
+	if err != nil {
+		return nil, err
+	}
+	if m.client == nil {
+		return nil, fmt.Errorf("the gRPC endpoint of the %s module is not available", m.Name())
+	}
+	return m.client, nil
+}
+
+// queryProposal returns the state of the given proposal at the given height, or nil if it does not exist
+func (m *Module) queryProposal(ctx context.Context, height int64, proposalID uint64) (*govv1.Proposal, error) {
+	client, err := m.queryClient()
+	if err != nil {
+		return nil, err
+	}
+
+	res, err := client.Proposal(remote.GetHeightRequestContext(ctx, height), &govv1.QueryProposalRequest{ProposalId: proposalID})
+	if status.Code(err) == codes.NotFound {
+		return nil, nil
+	}
+	if err != nil {
+		log.Errorw("query proposal error", "module", m.Name(), "proposal_id", proposalID, "height", height, "err", err)
+		return nil, err
+	}
+	return res.Proposal, nil
+}
+
+// DownloadState implements modules.FastSyncModule.
+// The proposals are stored as they are at the given height, without their proposer, deposits and votes.
+func (m *Module) DownloadState(height int64) error {
+	client, err := m.queryClient()
+	if err != nil {
+		return err
+	}
+
+	ctx := context.Background()
+	var nextKey []byte
+	for {
+		res, err := client.Proposals(remote.GetHeightRequestContext(ctx, height), &govv1.QueryProposalsRequest{
+			Pagination: &query.PageRequest{Key: nextKey, Limit: 100},
+		})
+		if err != nil {
+			log.Errorw("query proposals error", "module", m.Name(), "height", height, "err", err)
+			return err
+		}
+
+		records := make([]*sink.Record, len(res.Proposals))
+		for i, p := range res.Proposals {
+			proposal, err := newProposal(p)
+			if err != nil {
+				return err
+			}
+			proposal.UpdateAt = height
+			records[i] = sink.NewRecord(ModuleName, height, common.Hash{}, sink.OperationSave, proposal)
+		}
+
+		err = m.sink.Emit(ctx, records...)
+		if err != nil {
+			return err
+		}
+
+		if res.Pagination == nil || len(res.Pagination.NextKey) == 0 {
+			break
+		}
+		nextKey = res.Pagination.NextKey
+	}
+
+	log.Infow("downloaded state", "module", m.Name(), "height", height)
+	return nil
+}
+
+// newProposal builds the row describing the given proposal, without its proposer and create and update fields
+func newProposal(p *govv1.Proposal) (*models.Proposal, error) {
+	messageTypes := make([]string, len(p.Messages))
+	for i, msg := range p.Messages {
+		messageTypes[i] = msg.TypeUrl
+	}
+	bz, err := json.Marshal(messageTypes)
+	if err != nil {
+		return nil, err
+	}
+
+	proposal := &models.Proposal{
+		ProposalID:      p.Id,
+		MessageTypes:    string(bz),
+		Metadata:        p.Metadata,
+		Status:          p.Status.String(),
+		SubmitTime:      unixOrZero(p.SubmitTime),
+		DepositEndTime:  unixOrZero(p.DepositEndTime),
+		VotingStartTime: unixOrZero(p.VotingStartTime),
+		VotingEndTime:   unixOrZero(p.VotingEndTime),
+		TotalDeposit:    sdk.Coins(p.TotalDeposit).String(),
+	}
+	if p.FinalTallyResult != nil {
+		proposal.YesCount = p.FinalTallyResult.YesCount
+		proposal.AbstainCount = p.FinalTallyResult.AbstainCount
+		proposal.NoCount = p.FinalTallyResult.NoCount
+		proposal.NoWithVetoCount = p.FinalTallyResult.NoWithVetoCount
+	}
+	return proposal, nil
+}
+
+// unixOrZero returns the given time in seconds, or zero if it is not set
+func unixOrZero(t *time.Time) int64 {
+	if t == nil || t.IsZero() {
+		return 0
+	}
+	return t.UTC().Unix()
+}
@@ -0,0 +1,48 @@
+package gov
+
+import (
+	"testing"
+	"time"
+
+	codectypes "github.com/cosmos/cosmos-sdk/codec/types"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	govv1 "github.com/cosmos/cosmos-sdk/x/gov/types/v1"
+	"github.com/stretchr/testify/require"
+
+	"github.com/forbole/juno/v4/models"
+)
+
+func TestNewProposal(t *testing.T) {
+	submitTime := time.Unix(1000, 0)
+	votingEndTime := time.Unix(2000, 0)
+
+	proposal, err := newProposal(&govv1.Proposal{
+		Id:            7,
+		Messages:      []*codectypes.Any{{TypeUrl: "/cosmos.gov.v1.MsgExecLegacyContent"}},
+		Status:        govv1.StatusPassed,
+		SubmitTime:    &submitTime,
+		VotingEndTime: &votingEndTime,
+		TotalDeposit:  []sdk.Coin{sdk.NewInt64Coin("BNB", 10)},
+		Metadata:      "metadata",
+		FinalTallyResult: &govv1.TallyResult{
+			YesCount:        "3",
+			AbstainCount:    "0",
+			NoCount:         "1",
+			NoWithVetoCount: "0",
+		},
+	})
+	require.NoError(t, err)
+	require.Equal(t, &models.Proposal{
+		ProposalID:      7,
+		MessageTypes:    `["/cosmos.gov.v1.MsgExecLegacyContent"]`,
+		Metadata:        "metadata",
+		Status:          "PROPOSAL_STATUS_PASSED",
+		SubmitTime:      1000,
+		VotingEndTime:   2000,
+		TotalDeposit:    "10BNB",
+		YesCount:        "3",
+		AbstainCount:    "0",
+		NoCount:         "1",
+		NoWithVetoCount: "0",
+	}, proposal)
+}
@@ -0,0 +1,108 @@
+package gov
+
+import (
+	"context"
+	"sync"
+
+	govv1 "github.com/cosmos/cosmos-sdk/x/gov/types/v1"
+	"gorm.io/gorm/schema"
+
+	"github.com/forbole/juno/v4/database"
+	"github.com/forbole/juno/v4/log"
+	"github.com/forbole/juno/v4/models"
+	"github.com/forbole/juno/v4/modules"
+	"github.com/forbole/juno/v4/node/remote"
+	"github.com/forbole/juno/v4/sink"
+	"github.com/forbole/juno/v4/types/config"
+)
+
+const (
+	ModuleName = "gov"
+)
+
+var (
+	_ modules.Module              = &Module{}
+	_ modules.PrepareTablesModule = &Module{}
+	_ modules.RollbackModule      = &Module{}
+	_ modules.ReadBackModule      = &Module{}
+	_ modules.MessageModule       = &Module{}
+	_ modules.BlockModule         = &Module{}
+	_ modules.FastSyncModule      = &Module{}
+)
+
+// Module represents the governance module.
+// The state of the proposals is queried through the gRPC endpoint of the node, so a remote node is required.
+type Module struct {
+	db   database.Database
+	sink sink.Sink
+
+	grpcCfg    *remote.GRPCConfig
+	clientOnce sync.Once
+	client     govv1.QueryClient
+}
+
+// NewModule builds a new Module instance
+func NewModule(cfg config.Config, db database.Database, sink sink.Sink) *Module {
+	m := &Module{
+		db:   db,
+		sink: sink,
+	}
+	if details, ok := cfg.Node.Details.(*remote.Details); ok {
+		m.grpcCfg = details.GRPC
+	}
+	return m
+}
+
+// Name implements modules.Module
+func (m *Module) Name() string {
+	return ModuleName
+}
+
+// ReadsBack implements modules.ReadBackModule.
+// The dropped proposals are read back to update their status.
+func (m *Module) ReadsBack() bool {
+	return true
+}
+
+// tables returns the tables the module writes to
+func (m *Module) tables() []schema.Tabler {
+	return []schema.Tabler{&models.Proposal{}, &models.ProposalDeposit{}, &models.ProposalVote{}}
+}
+
+// PrepareTables implements
+func (m *Module) PrepareTables() error {
+	return m.db.PrepareTables(context.TODO(), m.tables())
+}
+
+// RecreateTables implements
+func (m *Module) RecreateTables() error {
+	return m.db.RecreateTables(context.TODO(), m.tables())
+}
+
+// Rollback implements modules.RollbackModule
+func (m *Module) Rollback(ctx context.Context, height uint64) error {
+	proposals, err := m.db.DeleteAfter(ctx, &models.Proposal{}, "create_at", int64(height))
+	if err != nil {
+		return err
+	}
+
+	deposits, err := m.db.DeleteAfter(ctx, &models.ProposalDeposit{}, "height", int64(height))
+	if err != nil {
+		return err
+	}
+
+	votes, err := m.db.DeleteAfter(ctx, &models.ProposalVote{}, "height", int64(height))
+	if err != nil {
+		return err
+	}
+
+	err = modules.CheckNotUpdated(ctx, m.db, m.Name(), modules.UpdatedAfter{
+		Table: &models.Proposal{}, Column: "update_at", Value: int64(height),
+	})
+	if err != nil {
+		return err
+	}
+
+	log.Infow("rolled back", "module", m.Name(), "height", height, "proposals", proposals, "deposits", deposits, "votes", votes)
+	return nil
+}
@@ -13,6 +13,8 @@ import (
 	"github.com/forbole/juno/v4/common"
 	"github.com/forbole/juno/v4/log"
 	"github.com/forbole/juno/v4/models"
+	"github.com/forbole/juno/v4/sink"
+	"github.com/forbole/juno/v4/types"
 )
 
 var (
@@ -29,7 +31,29 @@ var groupEvents = map[string]bool{
 	EventUpdateGroupMember: true,
 }
 
-func (m *Module) HandleEvent(ctx context.Context, block *tmctypes.ResultBlock, _ common.Hash, event sdk.Event) error {
+// HandleMsg implements modules.MessageModule.
+// The chain emits EventDeleteGroup instead of EventLeaveGroup when a member leaves a group, without telling
+// which member left, so leaving members are taken from MsgLeaveGroup instead.
+func (m *Module) HandleMsg(ctx context.Context, block *tmctypes.ResultBlock, _ int, msg sdk.Msg, tx *types.Tx) error {
+	leaveGroup, ok := msg.(*storagetypes.MsgLeaveGroup)
+	if !ok || !tx.Successful() {
+		return nil
+	}
+
+	group, err := m.db.GetGroupByName(ctx, common.HexToAddress(leaveGroup.GroupOwner), leaveGroup.GroupName)
+	if err != nil {
+		return err
+	}
+	if group == nil {
+		log.Warnw("group not found, skipping leave", "module", m.Name(), "owner", leaveGroup.GroupOwner,
+			"group_name", leaveGroup.GroupName, "tx_hash", tx.TxHash)
+		return nil
+	}
+
+	return m.leaveGroup(ctx, block, common.HexToHash(tx.TxHash), EventLeaveGroup, group.GroupID, common.HexToAddress(leaveGroup.Member))
+}
+
+func (m *Module) HandleEvent(ctx context.Context, block *tmctypes.ResultBlock, txHash common.Hash, event sdk.Event) error {
 	if !groupEvents[event.Type] {
 		return nil
 	}
@@ -47,14 +71,14 @@ func (m *Module) HandleEvent(ctx context.Context, block *tmctypes.ResultBlock, _
 			log.Errorw("type assert error", "type", "EventCreateGroup", "event", typedEvent)
 			return errors.New("create group event assert error")
 		}
-		return m.handleCreateGroup(ctx, block, createGroup)
+		return m.handleCreateGroup(ctx, block, txHash, createGroup)
 	case EventUpdateGroupMember:
 		updateGroupMember, ok := typedEvent.(*storagetypes.EventUpdateGroupMember)
 		if !ok {
 			log.Errorw("type assert error", "type", "EventUpdateGroupMember", "event", typedEvent)
 			return errors.New("update group member event assert error")
 		}
-		return m.handleUpdateGroupMember(ctx, block, updateGroupMember)
+		return m.handleUpdateGroupMember(ctx, block, txHash, updateGroupMember)
 
 	case EventDeleteGroup:
 		deleteGroup, ok := typedEvent.(*storagetypes.EventDeleteGroup)
@@ -62,105 +86,171 @@ func (m *Module) HandleEvent(ctx context.Context, block *tmctypes.ResultBlock, _
 			log.Errorw("type assert error", "type", "EventDeleteGroup", "event", typedEvent)
 			return errors.New("delete group event assert error")
 		}
-		return m.handleDeleteGroup(ctx, block, deleteGroup)
+		return m.handleDeleteGroup(ctx, block, txHash, deleteGroup)
 	case EventLeaveGroup:
 		leaveGroup, ok := typedEvent.(*storagetypes.EventLeaveGroup)
 		if !ok {
 			log.Errorw("type assert error", "type", "EventLeaveGroup", "event", typedEvent)
 			return errors.New("leave group event assert error")
 		}
-		return m.handleLeaveGroup(ctx, block, leaveGroup)
+		return m.handleLeaveGroup(ctx, block, txHash, leaveGroup)
 	}
 	return nil
 }
 
-func (m *Module) handleCreateGroup(ctx context.Context, block *tmctypes.ResultBlock, createGroup *storagetypes.EventCreateGroup) error {
-
-	var membersToAddList []*models.Group
-	for _, member := range createGroup.Members {
-		groupItem := &models.Group{
-			Owner:      common.HexToAddress(createGroup.OwnerAddress),
-			GroupID:    common.BigToHash(createGroup.GroupId.BigInt()),
-			GroupName:  createGroup.GroupName,
-			SourceType: createGroup.SourceType.String(),
-			AccountID:  common.HexToHash(member),
+func (m *Module) handleCreateGroup(ctx context.Context, block *tmctypes.ResultBlock, txHash common.Hash, createGroup *storagetypes.EventCreateGroup) error {
+	group := &models.Group{
+		GroupID:    common.BigToHash(createGroup.GroupId.BigInt()),
+		Owner:      common.HexToAddress(createGroup.OwnerAddress),
+		GroupName:  createGroup.GroupName,
+		SourceType: createGroup.SourceType.String(),
 
-			CreateAt:   block.Block.Height,
-			CreateTime: block.Block.Time.UTC().Unix(),
-			UpdateAt:   block.Block.Height,
-			UpdateTime: block.Block.Time.UTC().Unix(),
-			Removed:    false,
-		}
-		membersToAddList = append(membersToAddList, groupItem)
+		CreateAt:     block.Block.Height,
+		CreateTxHash: txHash,
+		CreateTime:   block.Block.Time.UTC().Unix(),
+		UpdateAt:     block.Block.Height,
+		UpdateTxHash: txHash,
+		UpdateTime:   block.Block.Time.UTC().Unix(),
+		Removed:      false,
 	}
 
-	return m.db.CreateGroup(ctx, membersToAddList)
+	return m.writeGroup(ctx, block, txHash, EventCreateGroup, group.GroupID, func() error {
+		records := []*sink.Record{
+			sink.NewRecord(ModuleName, block.Block.Height, txHash, sink.OperationSave, group),
+		}
+		records = append(records, m.addMembers(block, txHash, group.GroupID, group.Owner, createGroup.Members)...)
+		return m.sink.Emit(ctx, records...)
+	})
 }
 
-func (m *Module) handleDeleteGroup(ctx context.Context, block *tmctypes.ResultBlock, deleteGroup *storagetypes.EventDeleteGroup) error {
-	group := &models.Group{
-		Owner:     common.HexToAddress(deleteGroup.OwnerAddress),
-		GroupID:   common.BigToHash(deleteGroup.GroupId.BigInt()),
-		GroupName: deleteGroup.GroupName,
+func (m *Module) handleDeleteGroup(ctx context.Context, block *tmctypes.ResultBlock, txHash common.Hash, deleteGroup *storagetypes.EventDeleteGroup) error {
+	groupID := common.BigToHash(deleteGroup.GroupId.BigInt())
 
-		UpdateAt:   block.Block.Height,
-		UpdateTime: block.Block.Time.UTC().Unix(),
-		Removed:    true,
+	// the event is emitted when a member leaves the group as well, in which case the member has already left
+	// while handling the message
+	left, err := m.hasLeft(ctx, txHash, groupID, nil)
+	if err != nil {
+		return err
+	}
+	if left {
+		return nil
 	}
-	return m.db.DeleteGroup(ctx, group)
-}
 
-func (m *Module) handleLeaveGroup(ctx context.Context, block *tmctypes.ResultBlock, leaveGroup *storagetypes.EventLeaveGroup) error {
 	group := &models.Group{
-		Owner:     common.HexToAddress(leaveGroup.OwnerAddress),
-		GroupID:   common.BigToHash(leaveGroup.GroupId.BigInt()),
-		GroupName: leaveGroup.GroupName,
-		AccountID: common.HexToHash(leaveGroup.MemberAddress),
+		GroupID: groupID,
 
-		UpdateAt:   block.Block.Height,
-		UpdateTime: block.Block.Time.UTC().Unix(),
-		Removed:    true,
+		UpdateAt:     block.Block.Height,
+		UpdateTxHash: txHash,
+		UpdateTime:   block.Block.Time.UTC().Unix(),
+		Removed:      true,
 	}
-	return m.db.UpdateGroup(ctx, group)
+	return m.writeGroup(ctx, block, txHash, EventDeleteGroup, group.GroupID, func() error {
+		return m.sink.Emit(ctx, sink.NewRecord(ModuleName, block.Block.Height, txHash, sink.OperationUpdate, group))
+	})
 }
 
-func (m *Module) handleUpdateGroupMember(ctx context.Context, block *tmctypes.ResultBlock, updateGroupMember *storagetypes.EventUpdateGroupMember) error {
+func (m *Module) handleLeaveGroup(ctx context.Context, block *tmctypes.ResultBlock, txHash common.Hash, leaveGroup *storagetypes.EventLeaveGroup) error {
+	groupID := common.BigToHash(leaveGroup.GroupId.BigInt())
+	member := common.HexToAddress(leaveGroup.MemberAddress)
 
-	membersToAdd := updateGroupMember.MembersToAdd
-	membersToDelete := updateGroupMember.MembersToDelete
+	left, err := m.hasLeft(ctx, txHash, groupID, &member)
+	if err != nil {
+		return err
+	}
+	if left {
+		return nil
+	}
 
-	var membersToAddList []*models.Group
-	for _, memberToAdd := range membersToAdd {
-		groupItem := &models.Group{
-			Owner:           common.HexToAddress(updateGroupMember.OwnerAddress),
-			GroupID:         common.BigToHash(updateGroupMember.GroupId.BigInt()),
-			GroupName:       updateGroupMember.GroupName,
-			AccountID:       common.HexToHash(memberToAdd),
-			OperatorAddress: common.HexToAddress(updateGroupMember.OperatorAddress),
+	return m.leaveGroup(ctx, block, txHash, EventLeaveGroup, groupID, member)
+}
 
-			UpdateAt:   block.Block.Height,
-			UpdateTime: block.Block.Time.UTC().Unix(),
-			Removed:    false,
+func (m *Module) handleUpdateGroupMember(ctx context.Context, block *tmctypes.ResultBlock, txHash common.Hash, updateGroupMember *storagetypes.EventUpdateGroupMember) error {
+	groupID := common.BigToHash(updateGroupMember.GroupId.BigInt())
+	operator := common.HexToAddress(updateGroupMember.OperatorAddress)
+
+	return m.writeGroup(ctx, block, txHash, EventUpdateGroupMember, groupID, func() error {
+		records := m.addMembers(block, txHash, groupID, operator, updateGroupMember.MembersToAdd)
+		for _, member := range updateGroupMember.MembersToDelete {
+			records = append(records, m.removeMember(block, txHash, groupID, operator, common.HexToAddress(member), models.GroupMemberRemove)...)
 		}
-		membersToAddList = append(membersToAddList, groupItem)
+		return m.sink.Emit(ctx, records...)
+	})
+}
+
+// leaveGroup removes the given member from the given group, which the member has left
+func (m *Module) leaveGroup(ctx context.Context, block *tmctypes.ResultBlock, txHash common.Hash, eventType string, groupID common.Hash, member common.Address) error {
+	return m.writeGroup(ctx, block, txHash, eventType, groupID, func() error {
+		return m.sink.Emit(ctx, m.removeMember(block, txHash, groupID, member, member, models.GroupMemberLeave)...)
+	})
+}
+
+// hasLeft tells whether the given member, or any member if nil, has already left the given group within the given transaction
+func (m *Module) hasLeft(ctx context.Context, txHash common.Hash, groupID common.Hash, member *common.Address) (bool, error) {
+	if txHash == (common.Hash{}) {
+		return false, nil
+	}
+
+	changes, err := m.db.GetGroupMemberChangesByTx(ctx, txHash)
+	if err != nil {
+		return false, err
 	}
 
-	m.db.CreateGroup(ctx, membersToAddList)
+	for _, change := range changes {
+		if change.GroupID == groupID && change.Action == models.GroupMemberLeave && (member == nil || change.Member == *member) {
+			return true, nil
+		}
+	}
+	return false, nil
+}
 
-	for _, memberToDelete := range membersToDelete {
-		groupItem := &models.Group{
-			Owner:           common.HexToAddress(updateGroupMember.OwnerAddress),
-			GroupID:         common.BigToHash(updateGroupMember.GroupId.BigInt()),
-			GroupName:       updateGroupMember.GroupName,
-			AccountID:       common.HexToHash(memberToDelete),
-			OperatorAddress: common.HexToAddress(updateGroupMember.OperatorAddress),
+// addMembers returns the records adding the given members to the given group, along with their membership changes
+func (m *Module) addMembers(block *tmctypes.ResultBlock, txHash common.Hash, groupID common.Hash, operator common.Address, members []string) []*sink.Record {
+	records := make([]*sink.Record, 0, 2*len(members))
+	changes := make([]*sink.Record, 0, len(members))
+	for _, member := range members {
+		groupMember := &models.GroupMember{
+			GroupID:         groupID,
+			Member:          common.HexToAddress(member),
+			OperatorAddress: operator,
 
+			CreateAt:   block.Block.Height,
+			CreateTime: block.Block.Time.UTC().Unix(),
 			UpdateAt:   block.Block.Height,
 			UpdateTime: block.Block.Time.UTC().Unix(),
-			Removed:    true,
+			Removed:    false,
 		}
-		m.db.UpdateGroup(ctx, groupItem)
+		records = append(records, sink.NewRecord(ModuleName, block.Block.Height, txHash, sink.OperationSave, groupMember))
+		changes = append(changes, m.memberChange(block, txHash, groupID, operator, groupMember.Member, models.GroupMemberAdd))
 	}
+	return append(records, changes...)
+}
 
-	return nil
+// removeMember returns the records removing the given member from the given group, along with its membership change
+func (m *Module) removeMember(block *tmctypes.ResultBlock, txHash common.Hash, groupID common.Hash, operator, member common.Address, action string) []*sink.Record {
+	groupMember := &models.GroupMember{
+		GroupID:         groupID,
+		Member:          member,
+		OperatorAddress: operator,
+
+		UpdateAt:   block.Block.Height,
+		UpdateTime: block.Block.Time.UTC().Unix(),
+		Removed:    true,
+	}
+	return []*sink.Record{
+		sink.NewRecord(ModuleName, block.Block.Height, txHash, sink.OperationUpdate, groupMember),
+		m.memberChange(block, txHash, groupID, operator, member, action),
+	}
+}
+
+// memberChange returns the record appending the given membership change to the change log
+func (m *Module) memberChange(block *tmctypes.ResultBlock, txHash common.Hash, groupID common.Hash, operator, member common.Address, action string) *sink.Record {
+	return sink.NewRecord(ModuleName, block.Block.Height, txHash, sink.OperationSave, &models.GroupMemberChange{
+		GroupID:         groupID,
+		Member:          member,
+		Action:          action,
+		OperatorAddress: operator,
+		Height:          block.Block.Height,
+		TxHash:          txHash,
+		Time:            block.Block.Time.UTC().Unix(),
+	})
 }
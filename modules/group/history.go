@@ -0,0 +1,39 @@
+package group
+
+import (
+	"context"
+
+	tmctypes "github.com/tendermint/tendermint/rpc/core/types"
+
+	"github.com/forbole/juno/v4/common"
+	"github.com/forbole/juno/v4/models"
+	"github.com/forbole/juno/v4/modules/history"
+)
+
+// writeGroup applies the given write to the group with the given id and, when the history mode is enabled,
+// appends a version row for each member changed by the event with the given type.
+func (m *Module) writeGroup(ctx context.Context, block *tmctypes.ResultBlock, txHash common.Hash, eventType string, groupID common.Hash, write func() error) error {
+	if !m.historyCfg.Enabled {
+		return write()
+	}
+
+	return history.Write(ctx,
+		func(ctx context.Context) ([]*models.GroupMember, error) {
+			return m.db.GetGroupMembers(ctx, groupID)
+		},
+		func(member *models.GroupMember) common.Address { return member.Member },
+		write,
+		func(member *models.GroupMember, diff, state string) *models.GroupVersion {
+			return &models.GroupVersion{
+				GroupID:   groupID,
+				Member:    member.Member,
+				Height:    block.Block.Height,
+				TxHash:    txHash,
+				EventType: eventType,
+				Diff:      diff,
+				State:     state,
+			}
+		},
+		m.db.SaveGroupVersions,
+	)
+}
@@ -2,12 +2,17 @@ package group
 
 import (
 	"context"
+	"fmt"
 
 	"gorm.io/gorm/schema"
 
 	"github.com/forbole/juno/v4/database"
+	"github.com/forbole/juno/v4/log"
 	"github.com/forbole/juno/v4/models"
 	"github.com/forbole/juno/v4/modules"
+	"github.com/forbole/juno/v4/modules/history"
+	"github.com/forbole/juno/v4/sink"
+	"github.com/forbole/juno/v4/types/config"
 )
 
 const (
@@ -17,17 +22,34 @@ const (
 var (
 	_ modules.Module              = &Module{}
 	_ modules.PrepareTablesModule = &Module{}
+	_ modules.RollbackModule      = &Module{}
+	_ modules.ReadBackModule      = &Module{}
+	_ modules.MessageModule       = &Module{}
 )
 
 // Module represents the telemetry module
 type Module struct {
-	db database.Database
+	db         database.Database
+	sink       sink.Sink
+	historyCfg *history.Config
 }
 
 // NewModule builds a new Module instance
-func NewModule(db database.Database) *Module {
+func NewModule(cfg config.Config, db database.Database, sink sink.Sink) *Module {
+	bz, err := cfg.GetBytes()
+	if err != nil {
+		panic(err)
+	}
+
+	historyCfg, err := history.ParseConfig(bz)
+	if err != nil {
+		panic(err)
+	}
+
 	return &Module{
-		db: db,
+		db:         db,
+		sink:       sink,
+		historyCfg: historyCfg,
 	}
 }
 
@@ -36,12 +58,108 @@ func (m *Module) Name() string {
 	return ModuleName
 }
 
-// PrepareTables implements
+// ReadsBack implements modules.ReadBackModule.
+// The member changes of a transaction are read back when handling the group deletion events.
+func (m *Module) ReadsBack() bool {
+	return true
+}
+
+// PrepareTables implements.
+// The groups table indexed by the previous versions, having a row per member, cannot be migrated as it lacks
+// the transactions and the membership changes, so it has to be recreated and the groups indexed again.
 func (m *Module) PrepareTables() error {
-	return m.db.PrepareTables(context.TODO(), []schema.Tabler{&models.Group{}})
+	if m.db.HasColumn(context.TODO(), &models.Group{}, "account_id") {
+		return fmt.Errorf("the %s table has a row per member, recreate it using the parse modules %s --recreate-tables --start <first height> command",
+			(&models.Group{}).TableName(), ModuleName)
+	}
+	return m.db.PrepareTables(context.TODO(), m.tables())
 }
 
 // RecreateTables implements
 func (m *Module) RecreateTables() error {
-	return m.db.RecreateTables(context.TODO(), []schema.Tabler{&models.Group{}})
+	return m.db.RecreateTables(context.TODO(), m.tables())
+}
+
+// tables returns the tables handled by the module, including the history one when enabled
+func (m *Module) tables() []schema.Tabler {
+	tables := []schema.Tabler{&models.Group{}, &models.GroupMember{}, &models.GroupMemberChange{}}
+	if m.historyCfg.Enabled {
+		tables = append(tables, &models.GroupVersion{})
+	}
+	return tables
+}
+
+// Rollback implements modules.RollbackModule.
+// The groups are only updated when deleted, so the ones deleted after the height are restored as they were created.
+// The members updated after the height are restored from their versions when the history mode is enabled,
+// otherwise a modules.StaleRowsError is returned for them.
+func (m *Module) Rollback(ctx context.Context, height uint64) error {
+	deleted, err := m.db.DeleteAfter(ctx, &models.Group{}, "create_at", int64(height))
+	if err != nil {
+		return err
+	}
+
+	deletedMembers, err := m.db.DeleteAfter(ctx, &models.GroupMember{}, "create_at", int64(height))
+	if err != nil {
+		return err
+	}
+
+	_, err = m.db.DeleteAfter(ctx, &models.GroupMemberChange{}, "height", int64(height))
+	if err != nil {
+		return err
+	}
+
+	var groups []*models.Group
+	err = m.db.ListAfter(ctx, &models.Group{}, "update_at", int64(height), &groups)
+	if err != nil {
+		return err
+	}
+	for _, group := range groups {
+		group.UpdateAt, group.UpdateTxHash, group.UpdateTime = group.CreateAt, group.CreateTxHash, group.CreateTime
+		group.Removed = false
+		err = m.db.SaveGroup(ctx, group)
+		if err != nil {
+			return err
+		}
+	}
+
+	restoredMembers, err := m.restoreMembers(ctx, height)
+	if err != nil {
+		return err
+	}
+
+	log.Infow("rolled back", "module", m.Name(), "height", height, "deleted", deleted, "deleted_members", deletedMembers,
+		"restored", len(groups), "restored_members", restoredMembers)
+	return nil
+}
+
+// restoreMembers restores the group members updated after the given height from their versions,
+// returning the number of restored members
+func (m *Module) restoreMembers(ctx context.Context, height uint64) (int, error) {
+	if !m.historyCfg.Enabled {
+		return 0, modules.CheckNotUpdated(ctx, m.db, m.Name(), modules.UpdatedAfter{
+			Table: &models.GroupMember{}, Column: "update_at", Value: int64(height),
+		})
+	}
+
+	var members []*models.GroupMember
+	err := m.db.ListAfter(ctx, &models.GroupMember{}, "update_at", int64(height), &members)
+	if err != nil {
+		return 0, err
+	}
+
+	unknown, err := history.Restore(ctx, members, height, func(ctx context.Context, member *models.GroupMember, height uint64) (*models.GroupMember, error) {
+		return m.db.GetGroupMemberAtHeight(ctx, member.GroupID, member.Member, height)
+	}, func(ctx context.Context, member *models.GroupMember) error {
+		return m.db.SaveGroupMembers(ctx, []*models.GroupMember{member})
+	})
+	if err != nil {
+		return 0, err
+	}
+	if unknown > 0 {
+		return 0, &modules.StaleRowsError{Module: m.Name(), Rows: unknown}
+	}
+
+	_, err = m.db.DeleteAfter(ctx, &models.GroupVersion{}, "height", int64(height))
+	return len(members), err
 }
@@ -0,0 +1,40 @@
+package group
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+
+	"github.com/forbole/juno/v4/common"
+	"github.com/forbole/juno/v4/database"
+	"github.com/forbole/juno/v4/models"
+	"github.com/forbole/juno/v4/modules/history"
+)
+
+// legacyGroup is the groups table indexed by the previous versions, having a row per member
+type legacyGroup struct {
+	ID        uint64      `gorm:"column:id;primaryKey"`
+	GroupID   common.Hash `gorm:"column:group_id;type:BINARY(32)"`
+	AccountID common.Hash `gorm:"column:account_id;type:BINARY(32)"`
+}
+
+func (*legacyGroup) TableName() string {
+	return "groups"
+}
+
+func TestPrepareTables_LegacyGroups(t *testing.T) {
+	gormDB, err := gorm.Open(sqlite.Open("file::memory:"), &gorm.Config{Logger: logger.Default.LogMode(logger.Silent)})
+	require.NoError(t, err)
+	require.NoError(t, gormDB.AutoMigrate(&legacyGroup{}))
+
+	m := &Module{db: &database.Impl{Db: gormDB}, historyCfg: &history.Config{}}
+	require.Error(t, m.PrepareTables())
+
+	require.NoError(t, m.RecreateTables())
+	require.NoError(t, m.PrepareTables())
+	require.False(t, gormDB.Migrator().HasColumn(&models.Group{}, "account_id"))
+	require.True(t, gormDB.Migrator().HasIndex(&models.Group{}, "idx_group_group_id"))
+}
@@ -0,0 +1,39 @@
+package history
+
+import "gopkg.in/yaml.v3"
+
+// Config represents the configuration of the history mode.
+// When enabled, the modules supporting it append an immutable version row for each change they apply.
+type Config struct {
+	Enabled bool `yaml:"enabled"`
+}
+
+// NewConfig allows to build a new Config instance
+func NewConfig(enabled bool) *Config {
+	return &Config{
+		Enabled: enabled,
+	}
+}
+
+// DefaultConfig returns the default Config instance, which keeps the history mode disabled
+func DefaultConfig() *Config {
+	return NewConfig(false)
+}
+
+// ParseConfig allows to parse a byte array as a Config instance.
+// If the history section is missing, the default configuration is returned.
+func ParseConfig(bz []byte) (*Config, error) {
+	type T struct {
+		Config *Config `yaml:"history"`
+	}
+	var cfg T
+	err := yaml.Unmarshal(bz, &cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	if cfg.Config == nil {
+		return DefaultConfig(), nil
+	}
+	return cfg.Config, nil
+}
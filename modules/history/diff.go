@@ -0,0 +1,67 @@
+package history
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// Change represents the change of a single column between two versions of a row
+type Change struct {
+	From interface{} `json:"from"`
+	To   interface{} `json:"to"`
+}
+
+// Diff returns the JSON encoded set of columns that differ between prev and cur, keyed by their column name.
+// Both values must be pointers to the same gorm model; prev can be nil when the row did not exist before.
+// The primary key column is never included.
+func Diff(prev, cur interface{}) (string, error) {
+	curValue := reflect.ValueOf(cur)
+	if curValue.Kind() != reflect.Ptr || curValue.IsNil() {
+		return "", fmt.Errorf("invalid current value %T", cur)
+	}
+	curValue = curValue.Elem()
+
+	prevValue := reflect.New(curValue.Type()).Elem()
+	if value := reflect.ValueOf(prev); value.Kind() == reflect.Ptr && !value.IsNil() {
+		if value.Elem().Type() != curValue.Type() {
+			return "", fmt.Errorf("mismatching types %T and %T", prev, cur)
+		}
+		prevValue = value.Elem()
+	}
+
+	changes := make(map[string]Change)
+	for i := 0; i < curValue.NumField(); i++ {
+		field := curValue.Type().Field(i)
+		column, primaryKey := parseGormTag(field.Tag.Get("gorm"))
+		if column == "" || primaryKey {
+			continue
+		}
+
+		from, to := prevValue.Field(i).Interface(), curValue.Field(i).Interface()
+		if reflect.DeepEqual(from, to) {
+			continue
+		}
+		changes[column] = Change{From: from, To: to}
+	}
+
+	bz, err := json.Marshal(changes)
+	if err != nil {
+		return "", err
+	}
+	return string(bz), nil
+}
+
+// parseGormTag returns the column name and whether the column is the primary key, given a gorm struct tag
+func parseGormTag(tag string) (column string, primaryKey bool) {
+	for _, setting := range strings.Split(tag, ";") {
+		switch {
+		case strings.HasPrefix(setting, "column:"):
+			column = strings.TrimPrefix(setting, "column:")
+		case setting == "primaryKey":
+			primaryKey = true
+		}
+	}
+	return column, primaryKey
+}
@@ -0,0 +1,56 @@
+package history_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/forbole/juno/v4/common"
+	"github.com/forbole/juno/v4/models"
+	"github.com/forbole/juno/v4/modules/history"
+)
+
+func TestDiff(t *testing.T) {
+	prev := &models.Bucket{
+		ID:               1,
+		BucketName:       "bucket",
+		ChargedReadQuota: 10,
+		UpdateAt:         5,
+	}
+	cur := *prev
+	cur.ID = 2
+	cur.ChargedReadQuota = 20
+	cur.UpdateAt = 6
+
+	diff, err := history.Diff(prev, &cur)
+	require.NoError(t, err)
+	require.JSONEq(t, `{
+		"charged_read_quota": {"from": 10, "to": 20},
+		"update_at": {"from": 5, "to": 6}
+	}`, diff)
+
+	diff, err = history.Diff((*models.Bucket)(nil), &models.Bucket{BucketID: common.HexToHash("0x01")})
+	require.NoError(t, err)
+	require.JSONEq(t, `{
+		"bucket_id": {
+			"from": "0x0000000000000000000000000000000000000000000000000000000000000000",
+			"to": "0x0000000000000000000000000000000000000000000000000000000000000001"
+		}
+	}`, diff)
+
+	_, err = history.Diff(prev, &models.Object{})
+	require.Error(t, err)
+}
+
+func TestParseConfig(t *testing.T) {
+	cfg, err := history.ParseConfig([]byte(`
+history:
+  enabled: true
+`))
+	require.NoError(t, err)
+	require.True(t, cfg.Enabled)
+
+	cfg, err = history.ParseConfig([]byte(`invalid_field: yes`))
+	require.NoError(t, err)
+	require.False(t, cfg.Enabled)
+}
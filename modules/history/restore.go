@@ -0,0 +1,33 @@
+package history
+
+import (
+	"context"
+)
+
+// Restore restores the given rows, which were updated after the given height, to their state at that height.
+// The state of each row is read from its versions using getAt, and written back using save.
+// It returns the number of rows having no version at that height, which happens when the history mode was
+// enabled after they were last written, and whose state cannot be restored.
+func Restore[T any](
+	ctx context.Context, rows []*T, height uint64,
+	getAt func(ctx context.Context, row *T, height uint64) (*T, error),
+	save func(ctx context.Context, row *T) error,
+) (int64, error) {
+	var unknown int64
+	for _, row := range rows {
+		state, err := getAt(ctx, row, height)
+		if err != nil {
+			return 0, err
+		}
+		if state == nil {
+			unknown++
+			continue
+		}
+
+		err = save(ctx, state)
+		if err != nil {
+			return 0, err
+		}
+	}
+	return unknown, nil
+}
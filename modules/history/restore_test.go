@@ -0,0 +1,35 @@
+package history_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/forbole/juno/v4/common"
+	"github.com/forbole/juno/v4/models"
+	"github.com/forbole/juno/v4/modules/history"
+)
+
+func TestRestore(t *testing.T) {
+	versioned := common.HexToHash("0x01")
+	unversioned := common.HexToHash("0x02")
+
+	var saved []*models.Bucket
+	unknown, err := history.Restore(context.Background(), []*models.Bucket{
+		{BucketID: versioned, ChargedReadQuota: 20, UpdateAt: 8},
+		{BucketID: unversioned, ChargedReadQuota: 30, UpdateAt: 9},
+	}, 5, func(_ context.Context, bucket *models.Bucket, height uint64) (*models.Bucket, error) {
+		require.Equal(t, uint64(5), height)
+		if bucket.BucketID != versioned {
+			return nil, nil
+		}
+		return &models.Bucket{BucketID: versioned, ChargedReadQuota: 10, UpdateAt: 4}, nil
+	}, func(_ context.Context, bucket *models.Bucket) error {
+		saved = append(saved, bucket)
+		return nil
+	})
+	require.NoError(t, err)
+	require.Equal(t, int64(1), unknown)
+	require.Equal(t, []*models.Bucket{{BucketID: versioned, ChargedReadQuota: 10, UpdateAt: 4}}, saved)
+}
@@ -0,0 +1,72 @@
+package history
+
+import (
+	"context"
+	"encoding/json"
+)
+
+// Write applies the given write and appends a version for each row it changed.
+// The rows are read before and after the write using get, and matched using key; the rows left unchanged by the
+// write get no version. The version of each changed row is built by newVersion, which receives the row after the
+// write along with its diff and JSON encoded state, and all the versions are stored using save.
+func Write[K comparable, T any, V any](
+	ctx context.Context,
+	get func(ctx context.Context) ([]*T, error),
+	key func(row *T) K,
+	write func() error,
+	newVersion func(row *T, diff, state string) V,
+	save func(ctx context.Context, versions []V) error,
+) error {
+	prevRows, err := get(ctx)
+	if err != nil {
+		return err
+	}
+
+	prev := make(map[K]*T, len(prevRows))
+	for _, row := range prevRows {
+		prev[key(row)] = row
+	}
+
+	if err = write(); err != nil {
+		return err
+	}
+
+	curRows, err := get(ctx)
+	if err != nil {
+		return err
+	}
+
+	var versions []V
+	for _, row := range curRows {
+		diff, err := Diff(prev[key(row)], row)
+		if err != nil {
+			return err
+		}
+		if diff == "{}" {
+			continue
+		}
+
+		state, err := json.Marshal(row)
+		if err != nil {
+			return err
+		}
+
+		versions = append(versions, newVersion(row, diff, string(state)))
+	}
+
+	if len(versions) == 0 {
+		return nil
+	}
+	return save(ctx, versions)
+}
+
+// One adapts the given getter of a single row, which returns nil when the row does not exist, for Write
+func One[T any](get func(ctx context.Context) (*T, error)) func(ctx context.Context) ([]*T, error) {
+	return func(ctx context.Context) ([]*T, error) {
+		row, err := get(ctx)
+		if err != nil || row == nil {
+			return nil, err
+		}
+		return []*T{row}, nil
+	}
+}
@@ -0,0 +1,50 @@
+package history_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/forbole/juno/v4/common"
+	"github.com/forbole/juno/v4/models"
+	"github.com/forbole/juno/v4/modules/history"
+)
+
+func TestWrite(t *testing.T) {
+	alice := common.HexToAddress("0x01")
+	bob := common.HexToAddress("0x02")
+
+	members := []*models.GroupMember{{Member: alice, UpdateAt: 1}}
+	var saved []string
+	err := history.Write(context.Background(),
+		func(context.Context) ([]*models.GroupMember, error) {
+			rows := make([]*models.GroupMember, len(members))
+			for i, member := range members {
+				row := *member
+				rows[i] = &row
+			}
+			return rows, nil
+		},
+		func(member *models.GroupMember) common.Address { return member.Member },
+		func() error {
+			// alice is left unchanged while bob is added
+			members = append(members, &models.GroupMember{Member: bob, UpdateAt: 2})
+			return nil
+		},
+		func(member *models.GroupMember, diff, _ string) string {
+			require.Equal(t, bob, member.Member)
+			return diff
+		},
+		func(_ context.Context, versions []string) error {
+			saved = versions
+			return nil
+		},
+	)
+	require.NoError(t, err)
+	require.Len(t, saved, 1)
+	require.JSONEq(t, `{
+		"member": {"from": "0x0000000000000000000000000000000000000000", "to": "0x0000000000000000000000000000000000000002"},
+		"update_at": {"from": 0, "to": 2}
+	}`, saved[0])
+}
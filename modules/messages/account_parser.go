@@ -54,6 +54,9 @@ var CosmosMessageAddressesParser = JoinMessageParsers(
 	IBCTransferMessagesParser,
 	SlashingMessagesParser,
 	StakingMessagesParser,
+	StorageMessagesParser,
+	PaymentMessagesParser,
+	PermissionMessagesParser,
 	DefaultMessagesParser,
 )
 
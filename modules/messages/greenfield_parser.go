@@ -0,0 +1,116 @@
+package messages
+
+import (
+	paymenttypes "github.com/bnb-chain/greenfield/x/payment/types"
+	permissiontypes "github.com/bnb-chain/greenfield/x/permission/types"
+	storagetypes "github.com/bnb-chain/greenfield/x/storage/types"
+	"github.com/cosmos/cosmos-sdk/codec"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+)
+
+// StorageMessagesParser returns the list of all the accounts involved in the given
+// message if it's related to the buckets, objects and groups of the x/storage module
+func StorageMessagesParser(_ codec.Codec, cosmosMsg sdk.Msg) ([]string, error) {
+	switch msg := cosmosMsg.(type) {
+
+	case *storagetypes.MsgCreateBucket:
+		return []string{msg.Creator, msg.PaymentAddress, msg.PrimarySpAddress}, nil
+
+	case *storagetypes.MsgDeleteBucket:
+		return []string{msg.Operator}, nil
+
+	case *storagetypes.MsgUpdateBucketInfo:
+		return []string{msg.Operator, msg.PaymentAddress}, nil
+
+	case *storagetypes.MsgMirrorBucket:
+		return []string{msg.Operator}, nil
+
+	case *storagetypes.MsgCreateObject:
+		return append([]string{msg.Creator}, msg.ExpectSecondarySpAddresses...), nil
+
+	case *storagetypes.MsgSealObject:
+		return append([]string{msg.Operator}, msg.SecondarySpAddresses...), nil
+
+	case *storagetypes.MsgRejectSealObject:
+		return []string{msg.Operator}, nil
+
+	case *storagetypes.MsgCopyObject:
+		return []string{msg.Operator}, nil
+
+	case *storagetypes.MsgDeleteObject:
+		return []string{msg.Operator}, nil
+
+	case *storagetypes.MsgCancelCreateObject:
+		return []string{msg.Operator}, nil
+
+	case *storagetypes.MsgMirrorObject:
+		return []string{msg.Operator}, nil
+
+	case *storagetypes.MsgCreateGroup:
+		return append([]string{msg.Creator}, msg.Members...), nil
+
+	case *storagetypes.MsgDeleteGroup:
+		return []string{msg.Operator}, nil
+
+	case *storagetypes.MsgUpdateGroupMember:
+		addresses := []string{msg.Operator, msg.GroupOwner}
+		addresses = append(addresses, msg.MembersToAdd...)
+		return append(addresses, msg.MembersToDelete...), nil
+
+	case *storagetypes.MsgLeaveGroup:
+		return []string{msg.Member, msg.GroupOwner}, nil
+
+	case *storagetypes.MsgMirrorGroup:
+		return []string{msg.Operator}, nil
+
+	}
+
+	return nil, MessageNotSupported(cosmosMsg)
+}
+
+// PaymentMessagesParser returns the list of all the accounts involved in the given
+// message if it's related to the x/payment module
+func PaymentMessagesParser(_ codec.Codec, cosmosMsg sdk.Msg) ([]string, error) {
+	switch msg := cosmosMsg.(type) {
+
+	case *paymenttypes.MsgCreatePaymentAccount:
+		return []string{msg.Creator}, nil
+
+	case *paymenttypes.MsgDeposit:
+		return []string{msg.Creator, msg.To}, nil
+
+	case *paymenttypes.MsgWithdraw:
+		return []string{msg.Creator, msg.From}, nil
+
+	case *paymenttypes.MsgDisableRefund:
+		return []string{msg.Owner, msg.Addr}, nil
+
+	}
+
+	return nil, MessageNotSupported(cosmosMsg)
+}
+
+// PermissionMessagesParser returns the list of all the accounts involved in the given
+// message if it's related to the policies of the x/permission module, which are handled by the x/storage module.
+// Group principals are not accounts, so only the operator is returned for them.
+func PermissionMessagesParser(_ codec.Codec, cosmosMsg sdk.Msg) ([]string, error) {
+	switch msg := cosmosMsg.(type) {
+
+	case *storagetypes.MsgPutPolicy:
+		return append([]string{msg.Operator}, principalAccounts(msg.Principal)...), nil
+
+	case *storagetypes.MsgDeletePolicy:
+		return append([]string{msg.Operator}, principalAccounts(msg.Principal)...), nil
+
+	}
+
+	return nil, MessageNotSupported(cosmosMsg)
+}
+
+// principalAccounts returns the account of the given principal, if it is an account
+func principalAccounts(principal *permissiontypes.Principal) []string {
+	if principal == nil || principal.Type != permissiontypes.PRINCIPAL_TYPE_GNFD_ACCOUNT {
+		return nil
+	}
+	return []string{principal.Value}
+}
@@ -0,0 +1,72 @@
+package messages
+
+import (
+	"context"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	tmctypes "github.com/tendermint/tendermint/rpc/core/types"
+
+	"github.com/forbole/juno/v4/common"
+	"github.com/forbole/juno/v4/log"
+	"github.com/forbole/juno/v4/models"
+	"github.com/forbole/juno/v4/sink"
+	"github.com/forbole/juno/v4/types"
+)
+
+// HandleMsg implements modules.MessageModule.
+// The messages of failed transactions are indexed as well, as the addresses are involved in them all the same.
+// Messages whose addresses cannot be parsed are stored without any address.
+func (m *Module) HandleMsg(ctx context.Context, block *tmctypes.ResultBlock, index int, msg sdk.Msg, tx *types.Tx) error {
+	height := block.Block.Height
+	txHash := common.HexToHash(tx.TxHash)
+	msgType := sdk.MsgTypeURL(msg)
+
+	addresses, err := m.parser(m.cdc, msg)
+	if err != nil {
+		log.Warnw("message addresses not parsed", "module", m.Name(), "tx_hash", tx.TxHash, "index", index, "err", err)
+	}
+	accountTxs := newAccountTxs(addresses, txHash, index, msgType, height)
+
+	value, err := m.cdc.MarshalJSON(msg)
+	if err != nil {
+		log.Errorw("marshal message error", "module", m.Name(), "tx_hash", tx.TxHash, "index", index, "err", err)
+		return err
+	}
+	involved := make([]string, len(accountTxs))
+	for i, accountTx := range accountTxs {
+		involved[i] = accountTx.Address.Hex()
+	}
+
+	records := []*sink.Record{sink.NewRecord(ModuleName, height, txHash, sink.OperationSave,
+		models.NewMessage(types.NewMessage(tx.TxHash, index, msgType, string(value), involved, height)))}
+	for _, accountTx := range accountTxs {
+		records = append(records, sink.NewRecord(ModuleName, height, txHash, sink.OperationSave, accountTx))
+	}
+	return m.sink.Emit(ctx, records...)
+}
+
+// newAccountTxs builds the rows storing the involvement of the given addresses in a message, once for each address.
+// Addresses of other chains, such as the receivers of IBC transfers, and empty addresses are skipped.
+func newAccountTxs(addresses []string, txHash common.Hash, index int, msgType string, height int64) []*models.AccountTx {
+	var accountTxs []*models.AccountTx
+	seen := make(map[common.Address]bool, len(addresses))
+	for _, address := range addresses {
+		if !common.IsHexAddress(address) {
+			continue
+		}
+		addr := common.HexToAddress(address)
+		if seen[addr] {
+			continue
+		}
+		seen[addr] = true
+
+		accountTxs = append(accountTxs, &models.AccountTx{
+			Address:  addr,
+			TxHash:   txHash,
+			MsgIndex: index,
+			MsgType:  msgType,
+			Height:   height,
+		})
+	}
+	return accountTxs
+}
@@ -0,0 +1,64 @@
+package messages
+
+import (
+	"testing"
+
+	permissiontypes "github.com/bnb-chain/greenfield/x/permission/types"
+	storagetypes "github.com/bnb-chain/greenfield/x/storage/types"
+	"github.com/stretchr/testify/require"
+
+	"github.com/forbole/juno/v4/common"
+	"github.com/forbole/juno/v4/models"
+)
+
+const (
+	operator = "0x1111111111111111111111111111111111111111"
+	member   = "0x2222222222222222222222222222222222222222"
+)
+
+func TestPermissionMessagesParser(t *testing.T) {
+	addresses, err := PermissionMessagesParser(nil, &storagetypes.MsgPutPolicy{
+		Operator:  operator,
+		Principal: &permissiontypes.Principal{Type: permissiontypes.PRINCIPAL_TYPE_GNFD_ACCOUNT, Value: member},
+	})
+	require.NoError(t, err)
+	require.Equal(t, []string{operator, member}, addresses)
+
+	addresses, err = PermissionMessagesParser(nil, &storagetypes.MsgDeletePolicy{
+		Operator:  operator,
+		Principal: &permissiontypes.Principal{Type: permissiontypes.PRINCIPAL_TYPE_GNFD_GROUP, Value: "1"},
+	})
+	require.NoError(t, err)
+	require.Equal(t, []string{operator}, addresses)
+
+	_, err = PermissionMessagesParser(nil, &storagetypes.MsgDeleteBucket{Operator: operator})
+	require.Error(t, err)
+}
+
+func TestNewAccountTxs(t *testing.T) {
+	txHash := common.HexToHash("0x01")
+	addresses, err := StorageMessagesParser(nil, &storagetypes.MsgUpdateGroupMember{
+		Operator:        operator,
+		GroupOwner:      operator,
+		MembersToAdd:    []string{member},
+		MembersToDelete: []string{"cosmos1invalid"},
+	})
+	require.NoError(t, err)
+
+	require.Equal(t, []*models.AccountTx{
+		{
+			Address:  common.HexToAddress(operator),
+			TxHash:   txHash,
+			MsgIndex: 2,
+			MsgType:  "/bnbchain.greenfield.storage.MsgUpdateGroupMember",
+			Height:   10,
+		},
+		{
+			Address:  common.HexToAddress(member),
+			TxHash:   txHash,
+			MsgIndex: 2,
+			MsgType:  "/bnbchain.greenfield.storage.MsgUpdateGroupMember",
+			Height:   10,
+		},
+	}, newAccountTxs(addresses, txHash, 2, "/bnbchain.greenfield.storage.MsgUpdateGroupMember", 10))
+}
@@ -0,0 +1,81 @@
+package messages
+
+import (
+	"context"
+
+	"github.com/cosmos/cosmos-sdk/codec"
+	"gorm.io/gorm/schema"
+
+	"github.com/forbole/juno/v4/database"
+	"github.com/forbole/juno/v4/log"
+	"github.com/forbole/juno/v4/models"
+	"github.com/forbole/juno/v4/modules"
+	"github.com/forbole/juno/v4/sink"
+)
+
+const (
+	ModuleName = "messages"
+)
+
+var (
+	_ modules.Module              = &Module{}
+	_ modules.PrepareTablesModule = &Module{}
+	_ modules.RollbackModule      = &Module{}
+	_ modules.MessageModule       = &Module{}
+)
+
+// Module represents the messages module, which stores each message of the transactions along with the addresses
+// involved in it, indexing them so that the transactions of an address can be listed
+type Module struct {
+	parser MessageAddressesParser
+	cdc    codec.Codec
+	db     database.Database
+	sink   sink.Sink
+}
+
+// NewModule builds a new Module instance.
+// The parser is used to get the addresses involved in each message.
+func NewModule(parser MessageAddressesParser, cdc codec.Codec, db database.Database, sink sink.Sink) *Module {
+	return &Module{
+		parser: parser,
+		cdc:    cdc,
+		db:     db,
+		sink:   sink,
+	}
+}
+
+// Name implements modules.Module
+func (m *Module) Name() string {
+	return ModuleName
+}
+
+// tables returns the tables the module writes to
+func (m *Module) tables() []schema.Tabler {
+	return []schema.Tabler{&models.Message{}, &models.AccountTx{}}
+}
+
+// PrepareTables implements
+func (m *Module) PrepareTables() error {
+	return m.db.PrepareTables(context.TODO(), m.tables())
+}
+
+// RecreateTables implements
+func (m *Module) RecreateTables() error {
+	return m.db.RecreateTables(context.TODO(), m.tables())
+}
+
+// Rollback implements modules.RollbackModule
+func (m *Module) Rollback(ctx context.Context, height uint64) error {
+	msgs, err := m.db.DeleteAfter(ctx, &models.Message{}, "height", int64(height))
+	if err != nil {
+		return err
+	}
+
+	accountTxs, err := m.db.DeleteAfter(ctx, &models.AccountTx{}, "height", int64(height))
+	if err != nil {
+		return err
+	}
+
+	log.Infow("rolled back", "module", m.Name(), "height", height, "messages", msgs, "account_txs", accountTxs)
+	return nil
+}
@@ -44,6 +44,25 @@ type PrepareTablesModule interface {
 	RecreateTables() error
 }
 
+type ReadBackModule interface {
+	// ReadsBack returns true if the module, as configured, reads back from the database the records it emitted
+	// while handling the previous heights. Such modules require the sql sink to be enabled.
+	ReadsBack() bool
+}
+
+type ReplayModule interface {
+	// CanReplay returns false if the events quarantined by the module cannot be handled again once the following
+	// heights have been processed, as their changes depend on the state at the height they were emitted at.
+	CanReplay() bool
+}
+
+type RollbackModule interface {
+	// Rollback removes the data written by the module after the given height.
+	// It is called by the rollback command, inside the same database transaction that removes
+	// the blocks, once a chain fork has been detected.
+	Rollback(ctx context.Context, height uint64) error
+}
+
 type AdditionalOperationsModule interface {
 	// RunAdditionalOperations runs all the additional operations required by the module.
 	// This is the perfect place where to initialize all the operations that subscribe to websockets or other
@@ -86,39 +105,46 @@ type BlockModule interface {
 	// HandleBlock allows to handle a single block.
 	// For convenience of use, all the transactions present inside the given block will be passed as well.
 	// For each transaction present inside the block, HandleTx will be called as well.
-	// NOTE. The returned error will be logged using the BlockError method. All other modules' handlers
-	// will still be called.
-	HandleBlock(block *tmctypes.ResultBlock, results *tmctypes.ResultBlockResults, txs []*types.Tx, vals *tmctypes.ResultValidators) error
+	// The given context carries the database transaction of the height being processed.
+	// NOTE. If an error is returned, the whole height is rolled back and will be processed again.
+	HandleBlock(ctx context.Context, block *tmctypes.ResultBlock, results *tmctypes.ResultBlockResults, txs []*types.Tx, vals *tmctypes.ResultValidators) error
+}
+
+type CommitModule interface {
+	// HandleCommit allows to handle the commit of the previous height, which is carried by the given block as its
+	// last commit. The given validators are the validator set of the committed height, that signed the commit.
+	// The given context carries the database transaction of the height being processed.
+	// NOTE. If an error is returned, the whole height is rolled back and will be processed again.
+	HandleCommit(ctx context.Context, block *tmctypes.ResultBlock, vals *tmctypes.ResultValidators) error
 }
 
 type TransactionModule interface {
 	// HandleTx handles a single transaction.
 	// For each message present inside the transaction, HandleMsg will be called as well.
-	// NOTE. The returned error will be logged using the TxError method. All other modules' handlers
-	// will still be called.
-	HandleTx(tx *types.Tx) error
+	// NOTE. If an error is returned, the whole height is rolled back and will be processed again.
+	HandleTx(ctx context.Context, tx *types.Tx) error
 }
 
 type MessageModule interface {
 	// HandleMsg handles a single message.
 	// For convenience of use, the index of the message inside the transaction and the transaction itself
 	// are passed as well.
-	// NOTE. The returned error will be logged using the MsgError method. All other modules' handlers
-	// will still be called.
-	HandleMsg(block *tmctypes.ResultBlock, index int, msg sdk.Msg, tx *types.Tx) error
+	// NOTE. If an error is returned, the whole height is rolled back and will be processed again.
+	HandleMsg(ctx context.Context, block *tmctypes.ResultBlock, index int, msg sdk.Msg, tx *types.Tx) error
 }
 
 type AuthzMessageModule interface {
 	// HandleMsgExec handles a single message that is contained within an authz.MsgExec instance.
 	// For convenience of use, the index of the message inside the transaction and the transaction itself
 	// are passed as well.
-	// NOTE. The returned error will be logged using the MsgError method. All other modules' handlers
-	// will still be called.
-	HandleMsgExec(index int, msgExec *authz.MsgExec, authzMsgIndex int, executedMsg sdk.Msg, tx *types.Tx) error
+	// NOTE. If an error is returned, the whole height is rolled back and will be processed again.
+	HandleMsgExec(ctx context.Context, index int, msgExec *authz.MsgExec, authzMsgIndex int, executedMsg sdk.Msg, tx *types.Tx) error
 }
 
 type EventModule interface {
-	//HandleEvent index param here to save possible sequence order
+	// HandleEvent handles a single event emitted by a transaction.
+	// The given context carries the database transaction of the height being processed.
+	// NOTE. If an error is returned, the whole height is rolled back and will be processed again.
 	HandleEvent(ctx context.Context, block *tmctypes.ResultBlock, txHash common.Hash, event sdk.Event) error
 }
 
@@ -0,0 +1,73 @@
+package object
+
+import (
+	"context"
+
+	tmctypes "github.com/tendermint/tendermint/rpc/core/types"
+
+	"github.com/forbole/juno/v4/common"
+	"github.com/forbole/juno/v4/models"
+	"github.com/forbole/juno/v4/modules/history"
+)
+
+// writeObject applies the given write to the object with the given id. When the storage usage aggregates are enabled,
+// they are updated with the change of the object. When the history mode is enabled, a version row describing
+// the change caused by the event with the given type is appended as well.
+func (m *Module) writeObject(ctx context.Context, block *tmctypes.ResultBlock, txHash common.Hash, eventType string, objectID common.Hash, write func() error) error {
+	getObject := func(ctx context.Context) (*models.Object, error) {
+		return m.db.GetObjectByID(ctx, objectID)
+	}
+
+	if m.usageCfg.Enabled {
+		write = m.withUsage(ctx, block.Block.Height, getObject, write)
+	}
+
+	if !m.historyCfg.Enabled {
+		return write()
+	}
+
+	return history.Write(ctx,
+		history.One(getObject),
+		func(object *models.Object) common.Hash { return object.ObjectID },
+		write,
+		func(object *models.Object, diff, state string) *models.ObjectVersion {
+			return &models.ObjectVersion{
+				ObjectID:  object.ObjectID,
+				Height:    block.Block.Height,
+				TxHash:    txHash,
+				EventType: eventType,
+				Diff:      diff,
+				State:     state,
+			}
+		},
+		func(ctx context.Context, versions []*models.ObjectVersion) error {
+			for _, version := range versions {
+				if err := m.db.SaveObjectVersion(ctx, version); err != nil {
+					return err
+				}
+			}
+			return nil
+		},
+	)
+}
+
+// withUsage wraps the given write so that the storage usage aggregates are updated with the change it applies
+// to the object read by get
+func (m *Module) withUsage(ctx context.Context, height int64, get func(ctx context.Context) (*models.Object, error), write func() error) func() error {
+	return func() error {
+		prev, err := get(ctx)
+		if err != nil {
+			return err
+		}
+
+		if err = write(); err != nil {
+			return err
+		}
+
+		cur, err := get(ctx)
+		if err != nil {
+			return err
+		}
+		return m.updateUsage(ctx, height, prev, cur)
+	}
+}
@@ -6,8 +6,12 @@ import (
 	"gorm.io/gorm/schema"
 
 	"github.com/forbole/juno/v4/database"
+	"github.com/forbole/juno/v4/log"
 	"github.com/forbole/juno/v4/models"
 	"github.com/forbole/juno/v4/modules"
+	"github.com/forbole/juno/v4/modules/history"
+	"github.com/forbole/juno/v4/sink"
+	"github.com/forbole/juno/v4/types/config"
 )
 
 const (
@@ -17,17 +21,40 @@ const (
 var (
 	_ modules.Module              = &Module{}
 	_ modules.PrepareTablesModule = &Module{}
+	_ modules.RollbackModule      = &Module{}
+	_ modules.ReadBackModule      = &Module{}
 )
 
 // Module represents the object module
 type Module struct {
-	db database.Database
+	db         database.Database
+	sink       sink.Sink
+	historyCfg *history.Config
+	usageCfg   *UsageConfig
 }
 
 // NewModule builds a new Module instance
-func NewModule(db database.Database) *Module {
+func NewModule(cfg config.Config, db database.Database, sink sink.Sink) *Module {
+	bz, err := cfg.GetBytes()
+	if err != nil {
+		panic(err)
+	}
+
+	historyCfg, err := history.ParseConfig(bz)
+	if err != nil {
+		panic(err)
+	}
+
+	usageCfg, err := ParseUsageConfig(bz)
+	if err != nil {
+		panic(err)
+	}
+
 	return &Module{
-		db: db,
+		db:         db,
+		sink:       sink,
+		historyCfg: historyCfg,
+		usageCfg:   usageCfg,
 	}
 }
 
@@ -36,12 +63,84 @@ func (m *Module) Name() string {
 	return ModuleName
 }
 
+// ReadsBack implements modules.ReadBackModule.
+// The source objects are read back when copying objects.
+func (m *Module) ReadsBack() bool {
+	return true
+}
+
 // PrepareTables implements
 func (m *Module) PrepareTables() error {
-	return m.db.PrepareTables(context.TODO(), []schema.Tabler{&models.Object{}})
+	return m.db.PrepareTables(context.TODO(), m.tables())
 }
 
 // RecreateTables implements
 func (m *Module) RecreateTables() error {
-	return m.db.RecreateTables(context.TODO(), []schema.Tabler{&models.Object{}})
+	return m.db.RecreateTables(context.TODO(), m.tables())
+}
+
+// tables returns the tables handled by the module, including the history and usage ones when enabled
+func (m *Module) tables() []schema.Tabler {
+	tables := []schema.Tabler{&models.Object{}}
+	if m.historyCfg.Enabled {
+		tables = append(tables, &models.ObjectVersion{})
+	}
+	if m.usageCfg.Enabled {
+		tables = append(tables, &models.BucketUsage{}, &models.OwnerUsage{}, &models.SpUsage{})
+	}
+	return tables
+}
+
+// Rollback implements modules.RollbackModule.
+// The objects updated after the height are restored from their versions when the history mode is enabled,
+// otherwise a modules.StaleRowsError is returned for them.
+func (m *Module) Rollback(ctx context.Context, height uint64) error {
+	deleted, err := m.db.DeleteAfter(ctx, &models.Object{}, "create_at", int64(height))
+	if err != nil {
+		return err
+	}
+
+	restored, err := m.restoreObjects(ctx, height)
+	if err != nil {
+		return err
+	}
+
+	if m.usageCfg.Enabled {
+		err = m.db.RebuildStorageUsage(ctx)
+		if err != nil {
+			return err
+		}
+	}
+
+	log.Infow("rolled back", "module", m.Name(), "height", height, "deleted", deleted, "restored", restored)
+	return nil
+}
+
+// restoreObjects restores the objects updated after the given height from their versions,
+// returning the number of restored objects
+func (m *Module) restoreObjects(ctx context.Context, height uint64) (int, error) {
+	if !m.historyCfg.Enabled {
+		return 0, modules.CheckNotUpdated(ctx, m.db, m.Name(), modules.UpdatedAfter{
+			Table: &models.Object{}, Column: "update_at", Value: int64(height),
+		})
+	}
+
+	var objects []*models.Object
+	err := m.db.ListAfter(ctx, &models.Object{}, "update_at", int64(height), &objects)
+	if err != nil {
+		return 0, err
+	}
+
+	unknown, err := history.Restore(ctx, objects, height, func(ctx context.Context, object *models.Object, height uint64) (*models.Object, error) {
+		return m.db.GetObjectAtHeight(ctx, object.ObjectID, height)
+	}, m.db.SaveObject)
+	if err != nil {
+		return 0, err
+	}
+	if unknown > 0 {
+		return 0, &modules.StaleRowsError{Module: m.Name(), Rows: unknown}
+	}
+
+	_, err = m.db.DeleteAfter(ctx, &models.ObjectVersion{}, "height", int64(height))
+	return len(objects), err
 }
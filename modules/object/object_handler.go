@@ -3,6 +3,7 @@ package object
 import (
 	"context"
 	"errors"
+	"fmt"
 
 	storagetypes "github.com/bnb-chain/greenfield/x/storage/types"
 	sdk "github.com/cosmos/cosmos-sdk/types"
@@ -13,6 +14,7 @@ import (
 	"github.com/forbole/juno/v4/common"
 	"github.com/forbole/juno/v4/log"
 	"github.com/forbole/juno/v4/models"
+	"github.com/forbole/juno/v4/sink"
 )
 
 var (
@@ -118,7 +120,9 @@ func (m *Module) handleCreateObject(ctx context.Context, block *tmctypes.ResultB
 		Removed:      false,
 	}
 
-	return m.db.SaveObject(ctx, object)
+	return m.writeObject(ctx, block, txHash, EventCreateObject, object.ObjectID, func() error {
+		return m.sink.Emit(ctx, sink.NewRecord(ModuleName, block.Block.Height, txHash, sink.OperationSave, object))
+	})
 }
 
 func (m *Module) handleSealObject(ctx context.Context, block *tmctypes.ResultBlock, txHash common.Hash, sealObject *storagetypes.EventSealObject) error {
@@ -136,7 +140,9 @@ func (m *Module) handleSealObject(ctx context.Context, block *tmctypes.ResultBlo
 		Removed:      false,
 	}
 
-	return m.db.UpdateObject(ctx, object)
+	return m.writeObject(ctx, block, txHash, EventSealObject, object.ObjectID, func() error {
+		return m.sink.Emit(ctx, sink.NewRecord(ModuleName, block.Block.Height, txHash, sink.OperationUpdate, object))
+	})
 }
 
 func (m *Module) handleCancelCreateObject(ctx context.Context, block *tmctypes.ResultBlock, txHash common.Hash, cancelCreateObject *storagetypes.EventCancelCreateObject) error {
@@ -152,15 +158,32 @@ func (m *Module) handleCancelCreateObject(ctx context.Context, block *tmctypes.R
 		Removed:          true,
 	}
 
-	return m.db.UpdateObject(ctx, object)
+	return m.writeObject(ctx, block, txHash, EventCancelCreateObject, object.ObjectID, func() error {
+		return m.sink.Emit(ctx, sink.NewRecord(ModuleName, block.Block.Height, txHash, sink.OperationUpdate, object))
+	})
 }
 
 func (m *Module) handleCopyObject(ctx context.Context, block *tmctypes.ResultBlock, txHash common.Hash, copyObject *storagetypes.EventCopyObject) error {
-	destObject, err := m.db.GetObject(ctx, common.BigToHash(copyObject.SrcObjectId.BigInt()))
+	srcObjectID := common.BigToHash(copyObject.SrcObjectId.BigInt())
+	destObject, err := m.db.GetObject(ctx, srcObjectID)
 	if err != nil {
 		return err
 	}
+	if destObject.ObjectID != srcObjectID {
+		return fmt.Errorf("source object %s not found", srcObjectID)
+	}
+
+	// the event does not contain the id of the destination bucket
+	dstBucket, err := m.db.GetBucketByName(ctx, copyObject.DstBucketName)
+	if err != nil {
+		return err
+	}
+	if dstBucket == nil {
+		return fmt.Errorf("destination bucket %s not found", copyObject.DstBucketName)
+	}
+	destObject.BucketID = dstBucket.BucketID
 
+	destObject.ID = 0
 	destObject.ObjectID = common.BigToHash(copyObject.DstObjectId.BigInt())
 	destObject.ObjectName = copyObject.DstObjectName
 	destObject.BucketName = copyObject.DstBucketName
@@ -173,7 +196,9 @@ func (m *Module) handleCopyObject(ctx context.Context, block *tmctypes.ResultBlo
 	destObject.UpdateTime = block.Block.Time.UTC().Unix()
 	destObject.Removed = false
 
-	return m.db.UpdateObject(ctx, destObject)
+	return m.writeObject(ctx, block, txHash, EventCopyObject, destObject.ObjectID, func() error {
+		return m.sink.Emit(ctx, sink.NewRecord(ModuleName, block.Block.Height, txHash, sink.OperationSave, destObject))
+	})
 }
 
 func (m *Module) handleDeleteObject(ctx context.Context, block *tmctypes.ResultBlock, txHash common.Hash, deleteObject *storagetypes.EventDeleteObject) error {
@@ -190,7 +215,9 @@ func (m *Module) handleDeleteObject(ctx context.Context, block *tmctypes.ResultB
 		Removed:      true,
 	}
 
-	return m.db.UpdateObject(ctx, object)
+	return m.writeObject(ctx, block, txHash, EventDeleteObject, object.ObjectID, func() error {
+		return m.sink.Emit(ctx, sink.NewRecord(ModuleName, block.Block.Height, txHash, sink.OperationUpdate, object))
+	})
 }
 
 // RejectSeal event won't emit a delete event, need to be deleted manually here in metadata service
@@ -208,5 +235,7 @@ func (m *Module) handleRejectSealObject(ctx context.Context, block *tmctypes.Res
 		Removed:      true,
 	}
 
-	return m.db.UpdateObject(ctx, object)
+	return m.writeObject(ctx, block, txHash, EventRejectSealObject, object.ObjectID, func() error {
+		return m.sink.Emit(ctx, sink.NewRecord(ModuleName, block.Block.Height, txHash, sink.OperationUpdate, object))
+	})
 }
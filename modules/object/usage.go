@@ -0,0 +1,112 @@
+package object
+
+import (
+	"context"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/forbole/juno/v4/common"
+	"github.com/forbole/juno/v4/models"
+)
+
+// UsageConfig represents the configuration of the storage usage aggregates.
+// When enabled, the number and size of the objects of each bucket, owner and primary storage provider
+// are updated along with each object change.
+type UsageConfig struct {
+	Enabled bool `yaml:"enabled"`
+}
+
+// NewUsageConfig allows to build a new UsageConfig instance
+func NewUsageConfig(enabled bool) *UsageConfig {
+	return &UsageConfig{
+		Enabled: enabled,
+	}
+}
+
+// DefaultUsageConfig returns the default UsageConfig instance, which keeps the aggregates disabled
+func DefaultUsageConfig() *UsageConfig {
+	return NewUsageConfig(false)
+}
+
+// ParseUsageConfig allows to parse a byte array as a UsageConfig instance.
+// If the usage section is missing, the default configuration is returned.
+func ParseUsageConfig(bz []byte) (*UsageConfig, error) {
+	type T struct {
+		Config *UsageConfig `yaml:"usage"`
+	}
+	var cfg T
+	err := yaml.Unmarshal(bz, &cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	if cfg.Config == nil {
+		return DefaultUsageConfig(), nil
+	}
+	return cfg.Config, nil
+}
+
+// updateUsage applies to the storage usage aggregates the change of an object from prev to cur,
+// either of which is nil if the object did not exist before or after the change.
+func (m *Module) updateUsage(ctx context.Context, height int64, prev, cur *models.Object) error {
+	buckets := map[common.Hash]*models.StorageUsage{}
+	owners := map[common.Address]*models.StorageUsage{}
+	sps := map[common.Address]*models.StorageUsage{}
+
+	add := func(object *models.Object, sign int64) {
+		if object == nil {
+			return
+		}
+
+		if buckets[object.BucketID] == nil {
+			buckets[object.BucketID] = &models.StorageUsage{}
+		}
+		if owners[object.OwnerAddress] == nil {
+			owners[object.OwnerAddress] = &models.StorageUsage{}
+		}
+		if sps[object.PrimarySpAddress] == nil {
+			sps[object.PrimarySpAddress] = &models.StorageUsage{}
+		}
+
+		for _, usage := range []*models.StorageUsage{
+			buckets[object.BucketID], owners[object.OwnerAddress], sps[object.PrimarySpAddress],
+		} {
+			usage.Add(object, sign)
+			usage.UpdateAt = height
+		}
+	}
+	add(prev, -1)
+	add(cur, 1)
+
+	for bucketID, usage := range buckets {
+		if usage.IsZero() {
+			continue
+		}
+		err := m.db.AddBucketUsage(ctx, &models.BucketUsage{BucketID: bucketID, StorageUsage: *usage})
+		if err != nil {
+			return err
+		}
+	}
+
+	for owner, usage := range owners {
+		if usage.IsZero() {
+			continue
+		}
+		err := m.db.AddOwnerUsage(ctx, &models.OwnerUsage{OwnerAddress: owner, StorageUsage: *usage})
+		if err != nil {
+			return err
+		}
+	}
+
+	for spAddress, usage := range sps {
+		if usage.IsZero() {
+			continue
+		}
+		err := m.db.AddSpUsage(ctx, &models.SpUsage{SpAddress: spAddress, StorageUsage: *usage})
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
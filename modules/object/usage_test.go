@@ -0,0 +1,71 @@
+package object
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+
+	"github.com/forbole/juno/v4/common"
+	"github.com/forbole/juno/v4/database"
+	"github.com/forbole/juno/v4/models"
+)
+
+func TestUpdateUsage(t *testing.T) {
+	gormDB, err := gorm.Open(sqlite.Open("file::memory:"), &gorm.Config{Logger: logger.Default.LogMode(logger.Silent)})
+	require.NoError(t, err)
+	require.NoError(t, gormDB.AutoMigrate(&models.BucketUsage{}, &models.OwnerUsage{}, &models.SpUsage{}))
+
+	db := &database.Impl{Db: gormDB}
+	m := &Module{db: db}
+	ctx := context.Background()
+
+	bucketID := common.HexToHash("0x01")
+	owner := common.HexToAddress("0x02")
+	sp := common.HexToAddress("0x03")
+	created := &models.Object{BucketID: bucketID, OwnerAddress: owner, PrimarySpAddress: sp, PayloadSize: 10}
+	sealed := *created
+	sealed.Status = models.ObjectStatusSealed
+	removed := sealed
+	removed.Removed = true
+
+	require.NoError(t, m.updateUsage(ctx, 1, nil, created))
+	require.NoError(t, m.updateUsage(ctx, 2, nil, &sealed))
+	require.NoError(t, m.updateUsage(ctx, 3, created, &sealed))
+
+	bucketUsage, err := db.GetBucketUsage(ctx, bucketID)
+	require.NoError(t, err)
+	require.Equal(t, models.StorageUsage{SealedCount: 2, SealedSize: 20, UpdateAt: 3}, bucketUsage.StorageUsage)
+
+	require.NoError(t, m.updateUsage(ctx, 4, &sealed, &removed))
+
+	ownerUsage, err := db.GetOwnerUsage(ctx, owner)
+	require.NoError(t, err)
+	require.Equal(t, models.StorageUsage{SealedCount: 1, SealedSize: 10, RemovedCount: 1, RemovedSize: 10, UpdateAt: 4},
+		ownerUsage.StorageUsage)
+
+	spUsage, err := db.GetSpUsage(ctx, sp)
+	require.NoError(t, err)
+	require.Equal(t, ownerUsage.StorageUsage, spUsage.StorageUsage)
+
+	// an object moving to another owner is subtracted from the previous one
+	transferred := sealed
+	transferred.OwnerAddress = common.HexToAddress("0x04")
+	require.NoError(t, m.updateUsage(ctx, 5, &sealed, &transferred))
+
+	ownerUsage, err = db.GetOwnerUsage(ctx, owner)
+	require.NoError(t, err)
+	require.Equal(t, models.StorageUsage{RemovedCount: 1, RemovedSize: 10, UpdateAt: 5}, ownerUsage.StorageUsage)
+
+	ownerUsage, err = db.GetOwnerUsage(ctx, transferred.OwnerAddress)
+	require.NoError(t, err)
+	require.Equal(t, models.StorageUsage{SealedCount: 1, SealedSize: 10, UpdateAt: 5}, ownerUsage.StorageUsage)
+
+	// the storage provider is left unchanged
+	spUsage, err = db.GetSpUsage(ctx, sp)
+	require.NoError(t, err)
+	require.Equal(t, int64(4), spUsage.UpdateAt)
+}
@@ -2,12 +2,14 @@ package payment
 
 import (
 	"context"
+	"fmt"
 
 	"gorm.io/gorm/schema"
 
 	"github.com/forbole/juno/v4/database"
 	"github.com/forbole/juno/v4/models"
 	"github.com/forbole/juno/v4/modules"
+	"github.com/forbole/juno/v4/sink"
 )
 
 const (
@@ -17,17 +19,22 @@ const (
 var (
 	_ modules.Module              = &Module{}
 	_ modules.PrepareTablesModule = &Module{}
+	_ modules.RollbackModule      = &Module{}
+	_ modules.ReadBackModule      = &Module{}
+	_ modules.BlockModule         = &Module{}
 )
 
 // Module represents the payment module
 type Module struct {
-	db database.Database
+	db   database.Database
+	sink sink.Sink
 }
 
 // NewModule builds a new Module instance
-func NewModule(db database.Database) *Module {
+func NewModule(db database.Database, sink sink.Sink) *Module {
 	return &Module{
-		db: db,
+		db:   db,
+		sink: sink,
 	}
 }
 
@@ -36,12 +43,53 @@ func (m *Module) Name() string {
 	return ModuleName
 }
 
+// ReadsBack implements modules.ReadBackModule.
+// The payment accounts are read back to detect the disabled refunds.
+func (m *Module) ReadsBack() bool {
+	return true
+}
+
+// tables returns the tables the module writes to
+func (m *Module) tables() []schema.Tabler {
+	return []schema.Tabler{&models.StreamRecord{}, &models.PaymentAccount{}, &models.StreamRecordHistory{}, &models.StreamOutflow{},
+		&models.PaymentTransfer{}}
+}
+
 // PrepareTables implements
 func (m *Module) PrepareTables() error {
-	return m.db.PrepareTables(context.TODO(), []schema.Tabler{&models.StreamRecord{}, &models.PaymentAccount{}})
+	return m.db.PrepareTables(context.TODO(), m.tables())
 }
 
 // RecreateTables implements
 func (m *Module) RecreateTables() error {
-	return m.db.RecreateTables(context.TODO(), []schema.Tabler{&models.StreamRecord{}, &models.PaymentAccount{}})
+	return m.db.RecreateTables(context.TODO(), m.tables())
+}
+
+// Rollback implements modules.RollbackModule.
+// The stream record history and the payment transfers after the height are removed. Payment accounts, stream records
+// and outflows only keep their latest state, so a modules.StaleRowsError is returned if any of them was updated after
+// the height.
+func (m *Module) Rollback(ctx context.Context, height uint64) error {
+	block, err := m.db.GetBlock(ctx, height)
+	if err != nil {
+		return err
+	}
+	if block == nil {
+		return fmt.Errorf("block %d not found", height)
+	}
+
+	_, err = m.db.DeleteAfter(ctx, &models.StreamRecordHistory{}, "height", int64(height))
+	if err != nil {
+		return err
+	}
+
+	_, err = m.db.DeleteAfter(ctx, &models.PaymentTransfer{}, "height", int64(height))
+	if err != nil {
+		return err
+	}
+
+	return modules.CheckNotUpdated(ctx, m.db, m.Name(),
+		modules.UpdatedAfter{Table: &models.PaymentAccount{}, Column: "update_at", Value: int64(height)},
+		modules.UpdatedAfter{Table: &models.StreamRecord{}, Column: "crud_timestamp", Value: int64(block.Timestamp)},
+	)
 }
@@ -14,24 +14,79 @@ import (
 	"github.com/forbole/juno/v4/common"
 	"github.com/forbole/juno/v4/log"
 	"github.com/forbole/juno/v4/models"
+	"github.com/forbole/juno/v4/sink"
+	"github.com/forbole/juno/v4/types"
 )
 
 var (
 	EventPaymentAccountUpdate = proto.MessageName(&paymenttypes.EventPaymentAccountUpdate{})
 	EventStreamRecordUpdate   = proto.MessageName(&paymenttypes.EventStreamRecordUpdate{})
+	EventDeposit              = proto.MessageName(&paymenttypes.EventDeposit{})
+	EventWithdraw             = proto.MessageName(&paymenttypes.EventWithdraw{})
+	EventForceSettle          = proto.MessageName(&paymenttypes.EventForceSettle{})
 )
 
 var paymentEvents = map[string]bool{
 	EventPaymentAccountUpdate: true,
 	EventStreamRecordUpdate:   true,
+	EventDeposit:              true,
+	EventWithdraw:             true,
+	EventForceSettle:          true,
 }
 
-func (m *Module) HandleEvent(ctx context.Context, block *tmctypes.ResultBlock, _ common.Hash, event sdk.Event) error {
+// blockEvents are the payment events emitted outside of the transactions. The end blocker settles the stream accounts
+// whose balance is running out, which updates their stream records along with the ones of their payees.
+var blockEvents = map[string]bool{
+	EventPaymentAccountUpdate: true,
+	EventStreamRecordUpdate:   true,
+}
+
+// HandleBlock implements modules.BlockModule.
+// The payment events emitted by the begin and end blockers are handled with an empty transaction hash.
+// Stream accounts whose balance is running out are force settled by the end blocker, so the settlements
+// emitted at the end of the block are indexed as auto settlements.
+func (m *Module) HandleBlock(
+	ctx context.Context, block *tmctypes.ResultBlock, results *tmctypes.ResultBlockResults, _ []*types.Tx, _ *tmctypes.ResultValidators,
+) error {
+	if results == nil {
+		return nil
+	}
+
+	for _, event := range results.BeginBlockEvents {
+		if !blockEvents[event.Type] {
+			continue
+		}
+
+		err := m.handleEvent(ctx, block, common.Hash{}, event, models.PaymentTransferAutoSettle)
+		if err != nil {
+			return err
+		}
+	}
+
+	for _, event := range results.EndBlockEvents {
+		if !blockEvents[event.Type] && event.Type != EventForceSettle {
+			continue
+		}
+
+		err := m.handleEvent(ctx, block, common.Hash{}, event, models.PaymentTransferAutoSettle)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (m *Module) HandleEvent(ctx context.Context, block *tmctypes.ResultBlock, txHash common.Hash, event sdk.Event) error {
 	if !paymentEvents[event.Type] {
 		return nil
 	}
 
-	typedEvent, err := sdk.ParseTypedEvent(abci.Event(event))
+	return m.handleEvent(ctx, block, txHash, abci.Event(event), models.PaymentTransferForceSettle)
+}
+
+// handleEvent handles the given payment event, indexing its settlements with the given kind
+func (m *Module) handleEvent(ctx context.Context, block *tmctypes.ResultBlock, txHash common.Hash, event abci.Event, settleKind string) error {
+	typedEvent, err := sdk.ParseTypedEvent(event)
 	if err != nil {
 		log.Errorw("parse typed events error", "module", m.Name(), "event", event, "err", err)
 		return err
@@ -44,20 +99,41 @@ func (m *Module) HandleEvent(ctx context.Context, block *tmctypes.ResultBlock, _
 			log.Errorw("type assert error", "type", "EventPaymentAccountUpdate", "event", typedEvent)
 			return errors.New("update payment account event assert error")
 		}
-		return m.handlePaymentAccountUpdate(ctx, block, paymentAccountUpdate)
+		return m.handlePaymentAccountUpdate(ctx, block, txHash, paymentAccountUpdate)
 	case EventStreamRecordUpdate:
 		streamRecordUpdate, ok := typedEvent.(*paymenttypes.EventStreamRecordUpdate)
 		if !ok {
 			log.Errorw("type assert error", "type", "EventStreamRecordUpdate", "event", typedEvent)
 			return errors.New("update stream record event assert error")
 		}
-		return m.handleEventStreamRecordUpdate(ctx, streamRecordUpdate)
+		return m.handleEventStreamRecordUpdate(ctx, block, txHash, streamRecordUpdate)
+	case EventDeposit:
+		deposit, ok := typedEvent.(*paymenttypes.EventDeposit)
+		if !ok {
+			log.Errorw("type assert error", "type", "EventDeposit", "event", typedEvent)
+			return errors.New("deposit event assert error")
+		}
+		return m.handleDeposit(ctx, block, txHash, deposit)
+	case EventWithdraw:
+		withdraw, ok := typedEvent.(*paymenttypes.EventWithdraw)
+		if !ok {
+			log.Errorw("type assert error", "type", "EventWithdraw", "event", typedEvent)
+			return errors.New("withdraw event assert error")
+		}
+		return m.handleWithdraw(ctx, block, txHash, withdraw)
+	case EventForceSettle:
+		forceSettle, ok := typedEvent.(*paymenttypes.EventForceSettle)
+		if !ok {
+			log.Errorw("type assert error", "type", "EventForceSettle", "event", typedEvent)
+			return errors.New("force settle event assert error")
+		}
+		return m.handleForceSettle(ctx, block, txHash, settleKind, forceSettle)
 	}
 
 	return nil
 }
 
-func (m *Module) handlePaymentAccountUpdate(ctx context.Context, block *tmctypes.ResultBlock, paymentAccountUpdate *paymenttypes.EventPaymentAccountUpdate) error {
+func (m *Module) handlePaymentAccountUpdate(ctx context.Context, block *tmctypes.ResultBlock, txHash common.Hash, paymentAccountUpdate *paymenttypes.EventPaymentAccountUpdate) error {
 	paymentAccount := &models.PaymentAccount{
 		Addr:       common.HexToAddress(paymentAccountUpdate.Addr),
 		Owner:      common.HexToAddress(paymentAccountUpdate.Owner),
@@ -66,10 +142,81 @@ func (m *Module) handlePaymentAccountUpdate(ctx context.Context, block *tmctypes
 		UpdateTime: block.Block.Time.UTC().Unix(),
 	}
 
-	return m.db.SavePaymentAccount(ctx, paymentAccount)
+	records := []*sink.Record{
+		sink.NewRecord(ModuleName, block.Block.Height, txHash, sink.OperationSave, paymentAccount),
+	}
+
+	// there is no dedicated event for disabling the refund, which is the only way for an account to become non refundable.
+	// The previous state of the account is read back from the database, which is why the module requires the sql sink.
+	if !paymentAccount.Refundable {
+		prev, err := m.db.GetPaymentAccount(ctx, paymentAccount.Addr)
+		if err != nil {
+			return err
+		}
+		if prev != nil && prev.Refundable {
+			records = append(records, sink.NewRecord(ModuleName, block.Block.Height, txHash, sink.OperationSave, &models.PaymentTransfer{
+				Account:     paymentAccount.Addr,
+				Kind:        models.PaymentTransferDisableRefund,
+				Direction:   models.PaymentTransferNone,
+				FromAddress: paymentAccount.Owner,
+				ToAddress:   paymentAccount.Addr,
+				Amount:      new(common.Big),
+				Height:      block.Block.Height,
+				TxHash:      txHash,
+			}))
+		}
+	}
+
+	return m.sink.Emit(ctx, records...)
+}
+
+func (m *Module) handleDeposit(ctx context.Context, block *tmctypes.ResultBlock, txHash common.Hash, deposit *paymenttypes.EventDeposit) error {
+	transfer := &models.PaymentTransfer{
+		Account:     common.HexToAddress(deposit.To),
+		Kind:        models.PaymentTransferDeposit,
+		Direction:   models.PaymentTransferIn,
+		FromAddress: common.HexToAddress(deposit.From),
+		ToAddress:   common.HexToAddress(deposit.To),
+		Amount:      (*common.Big)(deposit.Amount.BigInt()),
+		Height:      block.Block.Height,
+		TxHash:      txHash,
+	}
+
+	return m.sink.Emit(ctx, sink.NewRecord(ModuleName, block.Block.Height, txHash, sink.OperationSave, transfer))
 }
 
-func (m *Module) handleEventStreamRecordUpdate(ctx context.Context, streamRecordUpdate *paymenttypes.EventStreamRecordUpdate) error {
+func (m *Module) handleWithdraw(ctx context.Context, block *tmctypes.ResultBlock, txHash common.Hash, withdraw *paymenttypes.EventWithdraw) error {
+	transfer := &models.PaymentTransfer{
+		Account:     common.HexToAddress(withdraw.From),
+		Kind:        models.PaymentTransferWithdraw,
+		Direction:   models.PaymentTransferOut,
+		FromAddress: common.HexToAddress(withdraw.From),
+		ToAddress:   common.HexToAddress(withdraw.To),
+		Amount:      (*common.Big)(withdraw.Amount.BigInt()),
+		Height:      block.Block.Height,
+		TxHash:      txHash,
+	}
+
+	return m.sink.Emit(ctx, sink.NewRecord(ModuleName, block.Block.Height, txHash, sink.OperationSave, transfer))
+}
+
+// handleForceSettle indexes the given settlement, which moves the whole balance of the account to the governance account
+func (m *Module) handleForceSettle(ctx context.Context, block *tmctypes.ResultBlock, txHash common.Hash, kind string, forceSettle *paymenttypes.EventForceSettle) error {
+	transfer := &models.PaymentTransfer{
+		Account:     common.HexToAddress(forceSettle.Addr),
+		Kind:        kind,
+		Direction:   models.PaymentTransferOut,
+		FromAddress: common.HexToAddress(forceSettle.Addr),
+		ToAddress:   common.BytesToAddress(paymenttypes.GovernanceAddress),
+		Amount:      (*common.Big)(forceSettle.SettledBalance.BigInt()),
+		Height:      block.Block.Height,
+		TxHash:      txHash,
+	}
+
+	return m.sink.Emit(ctx, sink.NewRecord(ModuleName, block.Block.Height, txHash, sink.OperationSave, transfer))
+}
+
+func (m *Module) handleEventStreamRecordUpdate(ctx context.Context, block *tmctypes.ResultBlock, txHash common.Hash, streamRecordUpdate *paymenttypes.EventStreamRecordUpdate) error {
 	streamRecord := &models.StreamRecord{
 		Account:         common.HexToAddress(streamRecordUpdate.Account),
 		CrudTimestamp:   streamRecordUpdate.CrudTimestamp,
@@ -88,5 +235,33 @@ func (m *Module) handleEventStreamRecordUpdate(ctx context.Context, streamRecord
 
 	streamRecord.OutFlows = outflows
 
-	return m.db.SaveStreamRecord(ctx, streamRecord)
+	history := &models.StreamRecordHistory{
+		Account:         streamRecord.Account,
+		Height:          block.Block.Height,
+		TxHash:          txHash,
+		CrudTimestamp:   streamRecord.CrudTimestamp,
+		NetflowRate:     streamRecord.NetflowRate,
+		StaticBalance:   streamRecord.StaticBalance,
+		BufferBalance:   streamRecord.BufferBalance,
+		LockBalance:     streamRecord.LockBalance,
+		Status:          streamRecord.Status,
+		SettleTimestamp: streamRecord.SettleTimestamp,
+	}
+
+	// the event carries all the outflows of the account, so the previous ones are replaced
+	records := []*sink.Record{
+		sink.NewRecord(ModuleName, block.Block.Height, txHash, sink.OperationSave, streamRecord),
+		sink.NewRecord(ModuleName, block.Block.Height, txHash, sink.OperationSave, history),
+		sink.NewRecord(ModuleName, block.Block.Height, txHash, sink.OperationDelete, &models.StreamOutflow{FromAddress: streamRecord.Account}),
+	}
+	for _, outFlow := range streamRecordUpdate.OutFlows {
+		records = append(records, sink.NewRecord(ModuleName, block.Block.Height, txHash, sink.OperationSave, &models.StreamOutflow{
+			FromAddress: streamRecord.Account,
+			ToAddress:   common.HexToAddress(outFlow.ToAddress),
+			Rate:        (*common.Big)(outFlow.Rate.BigInt()),
+			UpdateAt:    block.Block.Height,
+		}))
+	}
+
+	return m.sink.Emit(ctx, records...)
 }
@@ -0,0 +1,224 @@
+package payment
+
+import (
+	"context"
+	"math/big"
+	"testing"
+	"time"
+
+	paymenttypes "github.com/bnb-chain/greenfield/x/payment/types"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/gogo/protobuf/proto"
+	"github.com/stretchr/testify/require"
+	abci "github.com/tendermint/tendermint/abci/types"
+	tmctypes "github.com/tendermint/tendermint/rpc/core/types"
+	tmtypes "github.com/tendermint/tendermint/types"
+
+	"github.com/forbole/juno/v4/common"
+	"github.com/forbole/juno/v4/database"
+	"github.com/forbole/juno/v4/models"
+	"github.com/forbole/juno/v4/sink"
+)
+
+const (
+	ownerAddress   = "0x1111111111111111111111111111111111111111"
+	accountAddress = "0x2222222222222222222222222222222222222222"
+)
+
+// stubDatabase serves the payment account read back by the handlers, any other method panicking
+type stubDatabase struct {
+	database.Database
+
+	account *models.PaymentAccount
+}
+
+func (db *stubDatabase) GetPaymentAccount(_ context.Context, addr common.Address) (*models.PaymentAccount, error) {
+	if db.account == nil || db.account.Addr != addr {
+		return nil, nil
+	}
+	return db.account, nil
+}
+
+// recordingSink keeps the emitted records
+type recordingSink struct {
+	records []*sink.Record
+}
+
+func (s *recordingSink) Emit(_ context.Context, records ...*sink.Record) error {
+	s.records = append(s.records, records...)
+	return nil
+}
+
+func (s *recordingSink) Close() error {
+	return nil
+}
+
+var testBlock = &tmctypes.ResultBlock{Block: &tmtypes.Block{Header: tmtypes.Header{Height: 10, Time: time.Unix(1000, 0)}}}
+
+// transfers returns the payment transfers among the given records
+func transfers(records []*sink.Record) []*models.PaymentTransfer {
+	var transfers []*models.PaymentTransfer
+	for _, record := range records {
+		if transfer, ok := record.Data.(*models.PaymentTransfer); ok {
+			transfers = append(transfers, transfer)
+		}
+	}
+	return transfers
+}
+
+func TestHandleTransfers(t *testing.T) {
+	owner := common.HexToAddress(ownerAddress)
+	account := common.HexToAddress(accountAddress)
+	txHash := common.HexToHash("0x01")
+
+	testCases := []struct {
+		name     string
+		event    proto.Message
+		expected *models.PaymentTransfer
+	}{
+		{
+			name:  "deposit",
+			event: &paymenttypes.EventDeposit{From: ownerAddress, To: accountAddress, Amount: sdk.NewInt(100)},
+			expected: &models.PaymentTransfer{
+				Account: account, Kind: models.PaymentTransferDeposit, Direction: models.PaymentTransferIn,
+				FromAddress: owner, ToAddress: account, Amount: (*common.Big)(big.NewInt(100)),
+			},
+		},
+		{
+			name:  "withdraw",
+			event: &paymenttypes.EventWithdraw{From: accountAddress, To: ownerAddress, Amount: sdk.NewInt(40)},
+			expected: &models.PaymentTransfer{
+				Account: account, Kind: models.PaymentTransferWithdraw, Direction: models.PaymentTransferOut,
+				FromAddress: account, ToAddress: owner, Amount: (*common.Big)(big.NewInt(40)),
+			},
+		},
+		{
+			name:  "force settle",
+			event: &paymenttypes.EventForceSettle{Addr: accountAddress, SettledBalance: sdk.NewInt(60)},
+			expected: &models.PaymentTransfer{
+				Account: account, Kind: models.PaymentTransferForceSettle, Direction: models.PaymentTransferOut,
+				FromAddress: account, ToAddress: common.BytesToAddress(paymenttypes.GovernanceAddress),
+				Amount: (*common.Big)(big.NewInt(60)),
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			event, err := sdk.TypedEventToEvent(tc.event)
+			require.NoError(t, err)
+
+			recorder := &recordingSink{}
+			require.NoError(t, NewModule(&stubDatabase{}, recorder).HandleEvent(context.Background(), testBlock, txHash, event))
+
+			tc.expected.Height = 10
+			tc.expected.TxHash = txHash
+			require.Equal(t, []*models.PaymentTransfer{tc.expected}, transfers(recorder.records))
+		})
+	}
+}
+
+func TestHandlePaymentAccountUpdate(t *testing.T) {
+	account := common.HexToAddress(accountAddress)
+
+	testCases := []struct {
+		name        string
+		prev        *models.PaymentAccount
+		refundable  bool
+		refundFlips bool
+	}{
+		{name: "new account", refundable: true},
+		{name: "new non refundable account"},
+		{name: "refund disabled", prev: &models.PaymentAccount{Addr: account, Refundable: true}, refundFlips: true},
+		{name: "refund already disabled", prev: &models.PaymentAccount{Addr: account}},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			event, err := sdk.TypedEventToEvent(&paymenttypes.EventPaymentAccountUpdate{
+				Addr: accountAddress, Owner: ownerAddress, Refundable: tc.refundable,
+			})
+			require.NoError(t, err)
+
+			recorder := &recordingSink{}
+			module := NewModule(&stubDatabase{account: tc.prev}, recorder)
+			require.NoError(t, module.HandleEvent(context.Background(), testBlock, common.Hash{}, event))
+
+			if !tc.refundFlips {
+				require.Empty(t, transfers(recorder.records))
+				return
+			}
+			require.Equal(t, []*models.PaymentTransfer{{
+				Account: account, Kind: models.PaymentTransferDisableRefund, Direction: models.PaymentTransferNone,
+				FromAddress: common.HexToAddress(ownerAddress), ToAddress: account, Amount: new(common.Big), Height: 10,
+			}}, transfers(recorder.records))
+		})
+	}
+}
+
+func TestHandleBlock_AutoSettle(t *testing.T) {
+	settle, err := sdk.TypedEventToEvent(&paymenttypes.EventForceSettle{Addr: accountAddress, SettledBalance: sdk.NewInt(60)})
+	require.NoError(t, err)
+	deposit, err := sdk.TypedEventToEvent(&paymenttypes.EventDeposit{From: ownerAddress, To: accountAddress, Amount: sdk.NewInt(100)})
+	require.NoError(t, err)
+
+	recorder := &recordingSink{}
+	module := NewModule(&stubDatabase{}, recorder)
+	require.NoError(t, module.HandleBlock(context.Background(), testBlock, nil, nil, nil))
+	require.Empty(t, recorder.records)
+
+	results := &tmctypes.ResultBlockResults{
+		// the settlements of the begin blocker are ignored, as well as the other events
+		BeginBlockEvents: []abci.Event{abci.Event(settle)},
+		EndBlockEvents:   []abci.Event{abci.Event(deposit), abci.Event(settle)},
+	}
+	require.NoError(t, module.HandleBlock(context.Background(), testBlock, results, nil, nil))
+
+	account := common.HexToAddress(accountAddress)
+	require.Equal(t, []*models.PaymentTransfer{{
+		Account: account, Kind: models.PaymentTransferAutoSettle, Direction: models.PaymentTransferOut,
+		FromAddress: account, ToAddress: common.BytesToAddress(paymenttypes.GovernanceAddress),
+		Amount: (*common.Big)(big.NewInt(60)), Height: 10,
+	}}, transfers(recorder.records))
+}
+
+func TestHandleBlock_StreamRecordUpdate(t *testing.T) {
+	payee := "0x3333333333333333333333333333333333333333"
+	update, err := sdk.TypedEventToEvent(&paymenttypes.EventStreamRecordUpdate{
+		Account:       accountAddress,
+		CrudTimestamp: 1000,
+		NetflowRate:   sdk.NewInt(-2),
+		StaticBalance: sdk.NewInt(40),
+		BufferBalance: sdk.ZeroInt(),
+		LockBalance:   sdk.ZeroInt(),
+		Status:        paymenttypes.STREAM_ACCOUNT_STATUS_ACTIVE,
+		OutFlows:      []paymenttypes.OutFlow{{ToAddress: payee, Rate: sdk.NewInt(2)}},
+	})
+	require.NoError(t, err)
+	accountUpdate, err := sdk.TypedEventToEvent(&paymenttypes.EventPaymentAccountUpdate{Addr: accountAddress, Owner: ownerAddress, Refundable: true})
+	require.NoError(t, err)
+
+	// the stream records settled by the end blocker are indexed along with their history and outflows
+	recorder := &recordingSink{}
+	results := &tmctypes.ResultBlockResults{EndBlockEvents: []abci.Event{abci.Event(update), abci.Event(accountUpdate)}}
+	require.NoError(t, NewModule(&stubDatabase{}, recorder).HandleBlock(context.Background(), testBlock, results, nil, nil))
+	require.Len(t, recorder.records, 5)
+
+	account := common.HexToAddress(accountAddress)
+	streamRecord := recorder.records[0].Data.(*models.StreamRecord)
+	require.Equal(t, account, streamRecord.Account)
+	require.Equal(t, big.NewInt(40), streamRecord.StaticBalance.Raw())
+
+	history := recorder.records[1].Data.(*models.StreamRecordHistory)
+	require.Equal(t, int64(10), history.Height)
+	require.Equal(t, common.Hash{}, history.TxHash)
+
+	require.Equal(t, sink.OperationDelete, recorder.records[2].Operation)
+	outflow := recorder.records[3].Data.(*models.StreamOutflow)
+	require.Equal(t, common.HexToAddress(payee), outflow.ToAddress)
+	require.Equal(t, big.NewInt(2), outflow.Rate.Raw())
+
+	paymentAccount := recorder.records[4].Data.(*models.PaymentAccount)
+	require.Equal(t, account, paymentAccount.Addr)
+	require.Equal(t, common.Hash{}, recorder.records[4].TxHash)
+}
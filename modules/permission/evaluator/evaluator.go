@@ -0,0 +1,368 @@
+package evaluator
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"regexp"
+	"time"
+
+	gnfdtypes "github.com/bnb-chain/greenfield/types"
+	"github.com/bnb-chain/greenfield/types/resource"
+	permissiontypes "github.com/bnb-chain/greenfield/x/permission/types"
+	storagetypes "github.com/bnb-chain/greenfield/x/storage/types"
+
+	"github.com/forbole/juno/v4/common"
+	"github.com/forbole/juno/v4/database"
+	"github.com/forbole/juno/v4/models"
+	"github.com/forbole/juno/v4/modules/permission"
+)
+
+var (
+	// publicBucketActions are the actions anyone can perform on a public bucket
+	publicBucketActions = map[permissiontypes.ActionType]bool{
+		permissiontypes.ACTION_GET_OBJECT:     true,
+		permissiontypes.ACTION_COPY_OBJECT:    true,
+		permissiontypes.ACTION_EXECUTE_OBJECT: true,
+		permissiontypes.ACTION_LIST_OBJECT:    true,
+	}
+
+	// publicObjectActions are the actions anyone can perform on a public object
+	publicObjectActions = map[permissiontypes.ActionType]bool{
+		permissiontypes.ACTION_GET_OBJECT:     true,
+		permissiontypes.ACTION_COPY_OBJECT:    true,
+		permissiontypes.ACTION_EXECUTE_OBJECT: true,
+	}
+
+	publicRead = storagetypes.VISIBILITY_TYPE_PUBLIC_READ.String()
+	inherit    = storagetypes.VISIBILITY_TYPE_INHERIT.String()
+)
+
+// Request represents the question of whether Principal can perform Action on a resource at Time
+type Request struct {
+	Principal    common.Address
+	Action       permissiontypes.ActionType
+	ResourceType resource.ResourceType
+	ResourceID   common.Hash
+	Time         time.Time
+
+	// WantedSize is the size of the object to be created, checked against the LimitSize of the statements
+	// allowing permissiontypes.ACTION_CREATE_OBJECT. It is ignored when nil.
+	WantedSize *uint64
+}
+
+// Decision represents the result of the evaluation of a Request.
+// Permission and Statement are the policy and statement that determined the effect, and are nil when
+// the effect is given by the visibility or the ownership of the resource, or when no statement matched.
+type Decision struct {
+	Effect     permissiontypes.Effect
+	Public     bool
+	Owner      bool
+	Permission *models.Permission
+	Statement  *models.Statements
+}
+
+// Allowed tells whether the decision allows the requested action
+func (d *Decision) Allowed() bool {
+	return d.Effect == permissiontypes.EFFECT_ALLOW
+}
+
+// Evaluator evaluates the permissions using the policies indexed by the permission module,
+// following the same rules applied by the chain:
+//   - anyone can perform the read-only actions on public buckets and objects;
+//   - the owner of the resource can perform any action;
+//   - the policy granted to the principal is evaluated first, and decides if any of its statements matches;
+//   - the policies granted to the groups the principal is a member of, without being expired, are evaluated next;
+//   - a statement denying the action always wins over the ones allowing it;
+//   - anything not explicitly allowed is denied.
+//
+// Objects are also evaluated against the policies of their bucket, whose statements match the object
+// through their resources, which are regular expressions over the object GRN. Objects inheriting their visibility
+// are public when their bucket is.
+type Evaluator struct {
+	db database.Database
+}
+
+// NewEvaluator builds a new Evaluator instance
+func NewEvaluator(db database.Database) *Evaluator {
+	return &Evaluator{
+		db: db,
+	}
+}
+
+// Evaluate returns the decision for the given request.
+// An error is returned if the resource does not exist or the policies cannot be read.
+func (e *Evaluator) Evaluate(ctx context.Context, req *Request) (*Decision, error) {
+	switch req.ResourceType {
+	case resource.RESOURCE_TYPE_BUCKET:
+		return e.evaluateBucket(ctx, req)
+	case resource.RESOURCE_TYPE_OBJECT:
+		return e.evaluateObject(ctx, req)
+	case resource.RESOURCE_TYPE_GROUP:
+		return e.evaluateGroup(ctx, req)
+	default:
+		return nil, fmt.Errorf("unsupported resource type %s", req.ResourceType)
+	}
+}
+
+func (e *Evaluator) evaluateBucket(ctx context.Context, req *Request) (*Decision, error) {
+	bucket, err := e.db.GetBucketByID(ctx, req.ResourceID)
+	if err != nil {
+		return nil, err
+	}
+	if bucket == nil || bucket.Removed {
+		return nil, fmt.Errorf("bucket %s not found", req.ResourceID.Hex())
+	}
+	if bucket.Visibility == publicRead && publicBucketActions[req.Action] {
+		return &Decision{Effect: permissiontypes.EFFECT_ALLOW, Public: true}, nil
+	}
+	if bucket.OwnerAddress == req.Principal {
+		return &Decision{Effect: permissiontypes.EFFECT_ALLOW, Owner: true}, nil
+	}
+
+	decision, err := e.verifyPolicies(ctx, req, "")
+	if err != nil {
+		return nil, err
+	}
+	return implicitDeny(decision), nil
+}
+
+func (e *Evaluator) evaluateObject(ctx context.Context, req *Request) (*Decision, error) {
+	object, err := e.db.GetObjectByID(ctx, req.ResourceID)
+	if err != nil {
+		return nil, err
+	}
+	if object == nil || object.Removed {
+		return nil, fmt.Errorf("object %s not found", req.ResourceID.Hex())
+	}
+
+	public := object.Visibility == publicRead
+	if object.Visibility == inherit && publicObjectActions[req.Action] {
+		bucket, err := e.db.GetBucketByID(ctx, object.BucketID)
+		if err != nil {
+			return nil, err
+		}
+		public = bucket != nil && bucket.Visibility == publicRead
+	}
+	if public && publicObjectActions[req.Action] {
+		return &Decision{Effect: permissiontypes.EFFECT_ALLOW, Public: true}, nil
+	}
+	if object.OwnerAddress == req.Principal {
+		return &Decision{Effect: permissiontypes.EFFECT_ALLOW, Owner: true}, nil
+	}
+
+	bucketReq := *req
+	bucketReq.ResourceType = resource.RESOURCE_TYPE_BUCKET
+	bucketReq.ResourceID = object.BucketID
+	grn := gnfdtypes.NewObjectGRN(object.BucketName, object.ObjectName).String()
+
+	bucketDecision, err := e.verifyPolicies(ctx, &bucketReq, grn)
+	if err != nil {
+		return nil, err
+	}
+	if bucketDecision.Effect == permissiontypes.EFFECT_DENY {
+		return bucketDecision, nil
+	}
+
+	objectDecision, err := e.verifyPolicies(ctx, req, "")
+	if err != nil {
+		return nil, err
+	}
+	if objectDecision.Effect != permissiontypes.EFFECT_UNSPECIFIED {
+		return objectDecision, nil
+	}
+	return implicitDeny(bucketDecision), nil
+}
+
+func (e *Evaluator) evaluateGroup(ctx context.Context, req *Request) (*Decision, error) {
+	group, err := e.db.GetGroup(ctx, req.ResourceID)
+	if err != nil {
+		return nil, err
+	}
+	if group == nil || group.Removed {
+		return nil, fmt.Errorf("group %s not found", req.ResourceID.Hex())
+	}
+	if group.Owner == req.Principal {
+		return &Decision{Effect: permissiontypes.EFFECT_ALLOW, Owner: true}, nil
+	}
+
+	decision, err := e.verifyPolicies(ctx, req, "")
+	if err != nil {
+		return nil, err
+	}
+	return implicitDeny(decision), nil
+}
+
+// verifyPolicies evaluates the policies attached to the resource of the given request, which are granted either to
+// the principal or to the groups it is a member of. The sub-resource is the GRN of the object being accessed through
+// the policies of its bucket, and is empty otherwise.
+// The returned effect is permissiontypes.EFFECT_UNSPECIFIED if no policy decides about the request.
+func (e *Evaluator) verifyPolicies(ctx context.Context, req *Request, subResource string) (*Decision, error) {
+	permissions, err := e.db.ListPoliciesByResource(ctx, req.ResourceType.String(), req.ResourceID)
+	if err != nil {
+		return nil, err
+	}
+	if len(permissions) == 0 {
+		return &Decision{Effect: permissiontypes.EFFECT_UNSPECIFIED}, nil
+	}
+
+	policyIDs := make([]common.Hash, len(permissions))
+	for i, p := range permissions {
+		policyIDs[i] = p.PolicyID
+	}
+	statements, err := e.db.GetStatements(ctx, policyIDs)
+	if err != nil {
+		return nil, err
+	}
+	byPolicyID := make(map[common.Hash][]*models.Statements, len(permissions))
+	for _, s := range statements {
+		byPolicyID[s.PolicyID] = append(byPolicyID[s.PolicyID], s)
+	}
+
+	// the policy granted to the principal itself decides first
+	for _, p := range permissions {
+		if p.PrincipalType != int32(permissiontypes.PRINCIPAL_TYPE_GNFD_ACCOUNT) || !sameAddress(p.PrincipalValue, req.Principal) {
+			continue
+		}
+		effect, statement := evalPolicy(p, byPolicyID[p.PolicyID], req, subResource)
+		if effect != permissiontypes.EFFECT_UNSPECIFIED {
+			return &Decision{Effect: effect, Permission: p, Statement: statement}, nil
+		}
+	}
+
+	var allowed *Decision
+	for _, p := range permissions {
+		if p.PrincipalType != int32(permissiontypes.PRINCIPAL_TYPE_GNFD_GROUP) {
+			continue
+		}
+		effect, statement := evalPolicy(p, byPolicyID[p.PolicyID], req, subResource)
+		if effect == permissiontypes.EFFECT_UNSPECIFIED {
+			continue
+		}
+
+		member, err := e.isGroupMember(ctx, p.PrincipalValue, req.Principal, req.Time)
+		if err != nil {
+			return nil, err
+		}
+		if !member {
+			continue
+		}
+
+		if effect == permissiontypes.EFFECT_DENY {
+			return &Decision{Effect: effect, Permission: p, Statement: statement}, nil
+		}
+		if allowed == nil {
+			allowed = &Decision{Effect: effect, Permission: p, Statement: statement}
+		}
+	}
+	if allowed != nil {
+		return allowed, nil
+	}
+	return &Decision{Effect: permissiontypes.EFFECT_UNSPECIFIED}, nil
+}
+
+// isGroupMember tells whether the given account is a member, at the given time, of the group with the given id,
+// which is the decimal value used by group principals
+func (e *Evaluator) isGroupMember(ctx context.Context, groupID string, account common.Address, t time.Time) (bool, error) {
+	id, ok := new(big.Int).SetString(groupID, 10)
+	if !ok {
+		return false, nil
+	}
+
+	member, err := e.db.GetGroupMember(ctx, common.BigToHash(id), account)
+	if err != nil {
+		return false, err
+	}
+	return member != nil && member.IsActive(t.Unix()), nil
+}
+
+// evalPolicy evaluates the given policy along with its statements, returning the statement deciding its effect.
+// A statement denying the action wins over the ones allowing it, and expired policies and statements are ignored.
+func evalPolicy(p *models.Permission, statements []*models.Statements, req *Request, subResource string) (permissiontypes.Effect, *models.Statements) {
+	if isExpired(p.ExpirationTime, req.Time) {
+		return permissiontypes.EFFECT_UNSPECIFIED, nil
+	}
+
+	var allowed *models.Statements
+	for _, s := range statements {
+		if isExpired(s.ExpirationTime, req.Time) {
+			continue
+		}
+
+		switch evalStatement(s, req, subResource) {
+		case permissiontypes.EFFECT_DENY:
+			return permissiontypes.EFFECT_DENY, s
+		case permissiontypes.EFFECT_ALLOW:
+			if allowed == nil {
+				allowed = s
+			}
+		}
+	}
+	if allowed != nil {
+		return permissiontypes.EFFECT_ALLOW, allowed
+	}
+	return permissiontypes.EFFECT_UNSPECIFIED, nil
+}
+
+// evalStatement evaluates the given statement.
+// A sub-resource only matches the statements listing resources, and is matched against them as regular expressions.
+// A LimitSize, even zero, denies the creation of objects bigger than it.
+func evalStatement(s *models.Statements, req *Request, subResource string) permissiontypes.Effect {
+	if subResource != "" {
+		if !matchesResource(s.Resources, subResource) {
+			return permissiontypes.EFFECT_UNSPECIFIED
+		}
+	}
+
+	mask, ok := permission.ActionMask(req.Action)
+	if !ok {
+		return permissiontypes.EFFECT_UNSPECIFIED
+	}
+	allMask, _ := permission.ActionMask(permissiontypes.ACTION_TYPE_ALL)
+	if s.ActionValue&(mask|allMask) == 0 {
+		return permissiontypes.EFFECT_UNSPECIFIED
+	}
+
+	effect := permissiontypes.Effect(permissiontypes.Effect_value[s.Effect])
+	if effect == permissiontypes.EFFECT_DENY {
+		return permissiontypes.EFFECT_DENY
+	}
+
+	if req.Action == permissiontypes.ACTION_CREATE_OBJECT && s.LimitSize != nil && req.WantedSize != nil &&
+		*req.WantedSize > *s.LimitSize {
+		return permissiontypes.EFFECT_DENY
+	}
+	return effect
+}
+
+// matchesResource tells whether the given resource matches any of the given patterns
+func matchesResource(patterns []string, resource string) bool {
+	for _, pattern := range patterns {
+		reg, err := regexp.Compile(pattern)
+		if err != nil {
+			continue
+		}
+		if reg.MatchString(resource) {
+			return true
+		}
+	}
+	return false
+}
+
+// isExpired tells whether the given expiration time, in seconds, is before t. A zero expiration time never expires.
+func isExpired(expirationTime int64, t time.Time) bool {
+	return expirationTime != 0 && expirationTime < t.Unix()
+}
+
+// sameAddress tells whether the given principal value is the given address
+func sameAddress(value string, address common.Address) bool {
+	return common.IsHexAddress(value) && common.HexToAddress(value) == address
+}
+
+// implicitDeny turns the given unspecified decision into a denial, as anything not explicitly allowed is denied
+func implicitDeny(decision *Decision) *Decision {
+	if decision.Effect == permissiontypes.EFFECT_UNSPECIFIED {
+		decision.Effect = permissiontypes.EFFECT_DENY
+	}
+	return decision
+}
@@ -0,0 +1,335 @@
+package evaluator_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/bnb-chain/greenfield/types/resource"
+	permissiontypes "github.com/bnb-chain/greenfield/x/permission/types"
+	storagetypes "github.com/bnb-chain/greenfield/x/storage/types"
+	"github.com/stretchr/testify/require"
+
+	"github.com/forbole/juno/v4/common"
+	"github.com/forbole/juno/v4/database"
+	"github.com/forbole/juno/v4/models"
+	"github.com/forbole/juno/v4/modules/permission"
+	"github.com/forbole/juno/v4/modules/permission/evaluator"
+)
+
+var (
+	owner   = common.HexToAddress("0x1000000000000000000000000000000000000001")
+	alice   = common.HexToAddress("0x2000000000000000000000000000000000000002")
+	bob     = common.HexToAddress("0x3000000000000000000000000000000000000003")
+	carol   = common.HexToAddress("0x4000000000000000000000000000000000000004")
+	bucket  = common.HexToHash("0x01")
+	object  = common.HexToHash("0x02")
+	group   = common.HexToHash("0x03")
+	evalNow = time.Unix(1000, 0)
+)
+
+// mockDatabase is a database.Database serving the given rows, only implementing the methods used by the evaluator
+type mockDatabase struct {
+	database.Database
+
+	permissions []*models.Permission
+	statements  []*models.Statements
+	members     []*models.GroupMember
+
+	bucketVisibility storagetypes.VisibilityType
+	objectVisibility storagetypes.VisibilityType
+}
+
+func (db *mockDatabase) GetBucketByID(_ context.Context, bucketID common.Hash) (*models.Bucket, error) {
+	if bucketID != bucket {
+		return nil, nil
+	}
+	return &models.Bucket{BucketID: bucket, BucketName: "bucket", OwnerAddress: owner, Visibility: db.bucketVisibility.String()}, nil
+}
+
+func (db *mockDatabase) GetObjectByID(_ context.Context, objectID common.Hash) (*models.Object, error) {
+	if objectID != object {
+		return nil, nil
+	}
+	return &models.Object{
+		ObjectID: object, BucketID: bucket, BucketName: "bucket", ObjectName: "dir/file", OwnerAddress: owner,
+		Visibility: db.objectVisibility.String(),
+	}, nil
+}
+
+func (db *mockDatabase) GetGroup(_ context.Context, groupID common.Hash) (*models.Group, error) {
+	if groupID != group {
+		return nil, nil
+	}
+	return &models.Group{GroupID: group, Owner: owner, GroupName: "group"}, nil
+}
+
+func (db *mockDatabase) GetGroupMember(_ context.Context, groupID common.Hash, member common.Address) (*models.GroupMember, error) {
+	for _, groupMember := range db.members {
+		if groupMember.GroupID == groupID && groupMember.Member == member {
+			return groupMember, nil
+		}
+	}
+	return nil, nil
+}
+
+func (db *mockDatabase) ListPoliciesByResource(_ context.Context, resourceType string, resourceID common.Hash) ([]*models.Permission, error) {
+	var permissions []*models.Permission
+	for _, p := range db.permissions {
+		if p.ResourceType == resourceType && p.ResourceID == resourceID && !p.Removed {
+			permissions = append(permissions, p)
+		}
+	}
+	return permissions, nil
+}
+
+func (db *mockDatabase) GetStatements(_ context.Context, policyIDs []common.Hash) ([]*models.Statements, error) {
+	var statements []*models.Statements
+	for _, s := range db.statements {
+		for _, id := range policyIDs {
+			if s.PolicyID == id && !s.Removed {
+				statements = append(statements, s)
+			}
+		}
+	}
+	return statements, nil
+}
+
+func accountPolicy(policyID common.Hash, principal common.Address, resourceType resource.ResourceType, resourceID common.Hash) *models.Permission {
+	return &models.Permission{
+		PrincipalType:  int32(permissiontypes.PRINCIPAL_TYPE_GNFD_ACCOUNT),
+		PrincipalValue: principal.Hex(),
+		ResourceType:   resourceType.String(),
+		ResourceID:     resourceID,
+		PolicyID:       policyID,
+	}
+}
+
+func statement(policyID common.Hash, effect permissiontypes.Effect, actions ...permissiontypes.ActionType) *models.Statements {
+	value := 0
+	for _, action := range actions {
+		mask, _ := permission.ActionMask(action)
+		value |= mask
+	}
+	return &models.Statements{PolicyID: policyID, Effect: effect.String(), ActionValue: value}
+}
+
+func size(value uint64) *uint64 {
+	return &value
+}
+
+func TestEvaluator_Evaluate(t *testing.T) {
+	alicePolicy := accountPolicy(common.HexToHash("0x11"), alice, resource.RESOURCE_TYPE_BUCKET, bucket)
+
+	groupPolicy := &models.Permission{
+		PrincipalType:  int32(permissiontypes.PRINCIPAL_TYPE_GNFD_GROUP),
+		PrincipalValue: "3",
+		ResourceType:   resource.RESOURCE_TYPE_BUCKET.String(),
+		ResourceID:     bucket,
+		PolicyID:       common.HexToHash("0x12"),
+	}
+
+	objectPolicy := accountPolicy(common.HexToHash("0x13"), bob, resource.RESOURCE_TYPE_OBJECT, object)
+	objectPolicy.ExpirationTime = evalNow.Unix() - 1
+
+	listObjects := statement(alicePolicy.PolicyID, permissiontypes.EFFECT_ALLOW, permissiontypes.ACTION_LIST_OBJECT)
+	createObject := statement(alicePolicy.PolicyID, permissiontypes.EFFECT_ALLOW, permissiontypes.ACTION_CREATE_OBJECT)
+	createObject.LimitSize = size(100)
+	createNothing := statement(common.HexToHash("0x14"), permissiontypes.EFFECT_ALLOW, permissiontypes.ACTION_CREATE_OBJECT)
+	createNothing.LimitSize = size(0)
+	bobPolicy := accountPolicy(createNothing.PolicyID, bob, resource.RESOURCE_TYPE_BUCKET, bucket)
+	getDir := statement(alicePolicy.PolicyID, permissiontypes.EFFECT_ALLOW, permissiontypes.ACTION_GET_OBJECT)
+	getDir.Resources = []string{"grn:o::bucket/dir/.*"}
+	groupAll := statement(groupPolicy.PolicyID, permissiontypes.EFFECT_ALLOW, permissiontypes.ACTION_TYPE_ALL)
+	groupDenyDelete := statement(groupPolicy.PolicyID, permissiontypes.EFFECT_DENY, permissiontypes.ACTION_DELETE_BUCKET)
+	expiredGet := statement(objectPolicy.PolicyID, permissiontypes.EFFECT_ALLOW, permissiontypes.ACTION_GET_OBJECT)
+
+	db := &mockDatabase{
+		permissions: []*models.Permission{alicePolicy, groupPolicy, objectPolicy, bobPolicy},
+		statements:  []*models.Statements{listObjects, createObject, getDir, groupAll, groupDenyDelete, expiredGet, createNothing},
+		members: []*models.GroupMember{
+			{GroupID: group, Member: alice},
+			{GroupID: group, Member: bob, Removed: true},
+			{GroupID: group, Member: carol, ExpirationTime: evalNow.Unix()},
+		},
+	}
+	e := evaluator.NewEvaluator(db)
+
+	testCases := []struct {
+		name      string
+		req       *evaluator.Request
+		effect    permissiontypes.Effect
+		owner     bool
+		statement *models.Statements
+	}{
+		{
+			name:   "owner is allowed",
+			req:    &evaluator.Request{Principal: owner, Action: permissiontypes.ACTION_DELETE_BUCKET, ResourceType: resource.RESOURCE_TYPE_BUCKET, ResourceID: bucket},
+			effect: permissiontypes.EFFECT_ALLOW,
+			owner:  true,
+		},
+		{
+			name:      "account policy allows",
+			req:       &evaluator.Request{Principal: alice, Action: permissiontypes.ACTION_LIST_OBJECT, ResourceType: resource.RESOURCE_TYPE_BUCKET, ResourceID: bucket},
+			effect:    permissiontypes.EFFECT_ALLOW,
+			statement: listObjects,
+		},
+		{
+			name:      "group policy allows through wildcard action",
+			req:       &evaluator.Request{Principal: alice, Action: permissiontypes.ACTION_UPDATE_BUCKET_INFO, ResourceType: resource.RESOURCE_TYPE_BUCKET, ResourceID: bucket},
+			effect:    permissiontypes.EFFECT_ALLOW,
+			statement: groupAll,
+		},
+		{
+			name:      "deny wins over allow",
+			req:       &evaluator.Request{Principal: alice, Action: permissiontypes.ACTION_DELETE_BUCKET, ResourceType: resource.RESOURCE_TYPE_BUCKET, ResourceID: bucket},
+			effect:    permissiontypes.EFFECT_DENY,
+			statement: groupDenyDelete,
+		},
+		{
+			name:   "removed group member is denied",
+			req:    &evaluator.Request{Principal: bob, Action: permissiontypes.ACTION_UPDATE_BUCKET_INFO, ResourceType: resource.RESOURCE_TYPE_BUCKET, ResourceID: bucket},
+			effect: permissiontypes.EFFECT_DENY,
+		},
+		{
+			name:   "expired group member is denied",
+			req:    &evaluator.Request{Principal: carol, Action: permissiontypes.ACTION_UPDATE_BUCKET_INFO, ResourceType: resource.RESOURCE_TYPE_BUCKET, ResourceID: bucket},
+			effect: permissiontypes.EFFECT_DENY,
+		},
+		{
+			name:   "group owner is allowed",
+			req:    &evaluator.Request{Principal: owner, Action: permissiontypes.ACTION_DELETE_GROUP, ResourceType: resource.RESOURCE_TYPE_GROUP, ResourceID: group},
+			effect: permissiontypes.EFFECT_ALLOW,
+			owner:  true,
+		},
+		{
+			name:      "size within limit is allowed",
+			req:       &evaluator.Request{Principal: alice, Action: permissiontypes.ACTION_CREATE_OBJECT, ResourceType: resource.RESOURCE_TYPE_BUCKET, ResourceID: bucket, WantedSize: size(100)},
+			effect:    permissiontypes.EFFECT_ALLOW,
+			statement: createObject,
+		},
+		{
+			name:      "size over limit is denied",
+			req:       &evaluator.Request{Principal: alice, Action: permissiontypes.ACTION_CREATE_OBJECT, ResourceType: resource.RESOURCE_TYPE_BUCKET, ResourceID: bucket, WantedSize: size(101)},
+			effect:    permissiontypes.EFFECT_DENY,
+			statement: createObject,
+		},
+		{
+			name:      "zero limit denies any size",
+			req:       &evaluator.Request{Principal: bob, Action: permissiontypes.ACTION_CREATE_OBJECT, ResourceType: resource.RESOURCE_TYPE_BUCKET, ResourceID: bucket, WantedSize: size(1)},
+			effect:    permissiontypes.EFFECT_DENY,
+			statement: createNothing,
+		},
+		{
+			name:      "bucket statement matches object resource",
+			req:       &evaluator.Request{Principal: alice, Action: permissiontypes.ACTION_GET_OBJECT, ResourceType: resource.RESOURCE_TYPE_OBJECT, ResourceID: object},
+			effect:    permissiontypes.EFFECT_ALLOW,
+			statement: getDir,
+		},
+		{
+			name:   "expired policy is ignored",
+			req:    &evaluator.Request{Principal: bob, Action: permissiontypes.ACTION_GET_OBJECT, ResourceType: resource.RESOURCE_TYPE_OBJECT, ResourceID: object},
+			effect: permissiontypes.EFFECT_DENY,
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			tc.req.Time = evalNow
+			decision, err := e.Evaluate(context.Background(), tc.req)
+			require.NoError(t, err)
+			require.Equal(t, tc.effect, decision.Effect)
+			require.Equal(t, tc.owner, decision.Owner)
+			require.Equal(t, tc.statement, decision.Statement)
+		})
+	}
+
+	_, err := e.Evaluate(context.Background(), &evaluator.Request{Principal: alice, ResourceType: resource.RESOURCE_TYPE_BUCKET, ResourceID: object})
+	require.Error(t, err)
+}
+
+func TestEvaluator_PublicRead(t *testing.T) {
+	testCases := []struct {
+		name             string
+		bucketVisibility storagetypes.VisibilityType
+		objectVisibility storagetypes.VisibilityType
+		action           permissiontypes.ActionType
+		resourceType     resource.ResourceType
+		public           bool
+	}{
+		{
+			name:             "public bucket allows listing",
+			bucketVisibility: storagetypes.VISIBILITY_TYPE_PUBLIC_READ,
+			action:           permissiontypes.ACTION_LIST_OBJECT,
+			resourceType:     resource.RESOURCE_TYPE_BUCKET,
+			public:           true,
+		},
+		{
+			name:             "public bucket denies writing",
+			bucketVisibility: storagetypes.VISIBILITY_TYPE_PUBLIC_READ,
+			action:           permissiontypes.ACTION_DELETE_BUCKET,
+			resourceType:     resource.RESOURCE_TYPE_BUCKET,
+		},
+		{
+			name:             "private bucket denies listing",
+			bucketVisibility: storagetypes.VISIBILITY_TYPE_PRIVATE,
+			action:           permissiontypes.ACTION_LIST_OBJECT,
+			resourceType:     resource.RESOURCE_TYPE_BUCKET,
+		},
+		{
+			name:             "public object allows reading",
+			bucketVisibility: storagetypes.VISIBILITY_TYPE_PRIVATE,
+			objectVisibility: storagetypes.VISIBILITY_TYPE_PUBLIC_READ,
+			action:           permissiontypes.ACTION_GET_OBJECT,
+			resourceType:     resource.RESOURCE_TYPE_OBJECT,
+			public:           true,
+		},
+		{
+			name:             "public object denies deleting",
+			objectVisibility: storagetypes.VISIBILITY_TYPE_PUBLIC_READ,
+			action:           permissiontypes.ACTION_DELETE_OBJECT,
+			resourceType:     resource.RESOURCE_TYPE_OBJECT,
+		},
+		{
+			name:             "object inheriting from a public bucket allows reading",
+			bucketVisibility: storagetypes.VISIBILITY_TYPE_PUBLIC_READ,
+			objectVisibility: storagetypes.VISIBILITY_TYPE_INHERIT,
+			action:           permissiontypes.ACTION_GET_OBJECT,
+			resourceType:     resource.RESOURCE_TYPE_OBJECT,
+			public:           true,
+		},
+		{
+			name:             "object inheriting from a private bucket denies reading",
+			bucketVisibility: storagetypes.VISIBILITY_TYPE_PRIVATE,
+			objectVisibility: storagetypes.VISIBILITY_TYPE_INHERIT,
+			action:           permissiontypes.ACTION_GET_OBJECT,
+			resourceType:     resource.RESOURCE_TYPE_OBJECT,
+		},
+		{
+			name:             "private object in a public bucket denies reading",
+			bucketVisibility: storagetypes.VISIBILITY_TYPE_PUBLIC_READ,
+			objectVisibility: storagetypes.VISIBILITY_TYPE_PRIVATE,
+			action:           permissiontypes.ACTION_GET_OBJECT,
+			resourceType:     resource.RESOURCE_TYPE_OBJECT,
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			e := evaluator.NewEvaluator(&mockDatabase{bucketVisibility: tc.bucketVisibility, objectVisibility: tc.objectVisibility})
+
+			resourceID := bucket
+			if tc.resourceType == resource.RESOURCE_TYPE_OBJECT {
+				resourceID = object
+			}
+			decision, err := e.Evaluate(context.Background(), &evaluator.Request{
+				Principal: carol, Action: tc.action, ResourceType: tc.resourceType, ResourceID: resourceID, Time: evalNow,
+			})
+			require.NoError(t, err)
+			require.Equal(t, tc.public, decision.Public)
+			require.Equal(t, tc.public, decision.Allowed())
+		})
+	}
+}
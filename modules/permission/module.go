@@ -2,12 +2,15 @@ package permission
 
 import (
 	"context"
+	"fmt"
 
 	"gorm.io/gorm/schema"
 
 	"github.com/forbole/juno/v4/database"
+	"github.com/forbole/juno/v4/log"
 	"github.com/forbole/juno/v4/models"
 	"github.com/forbole/juno/v4/modules"
+	"github.com/forbole/juno/v4/sink"
 )
 
 const (
@@ -17,17 +20,20 @@ const (
 var (
 	_ modules.Module              = &Module{}
 	_ modules.PrepareTablesModule = &Module{}
+	_ modules.RollbackModule      = &Module{}
 )
 
 // Module represents the payment module
 type Module struct {
-	db database.Database
+	db   database.Database
+	sink sink.Sink
 }
 
 // NewModule builds a new Module instance
-func NewModule(db database.Database) *Module {
+func NewModule(db database.Database, sink sink.Sink) *Module {
 	return &Module{
-		db: db,
+		db:   db,
+		sink: sink,
 	}
 }
 
@@ -45,3 +51,29 @@ func (m *Module) PrepareTables() error {
 func (m *Module) RecreateTables() error {
 	return m.db.RecreateTables(context.TODO(), []schema.Tabler{&models.Permission{}, &models.Statements{}})
 }
+
+// Rollback implements modules.RollbackModule
+func (m *Module) Rollback(ctx context.Context, height uint64) error {
+	block, err := m.db.GetBlock(ctx, height)
+	if err != nil {
+		return err
+	}
+	if block == nil {
+		return fmt.Errorf("block %d not found", height)
+	}
+
+	deleted, err := m.db.DeletePoliciesCreatedAfter(ctx, int64(block.Timestamp))
+	if err != nil {
+		return err
+	}
+
+	err = modules.CheckNotUpdated(ctx, m.db, m.Name(), modules.UpdatedAfter{
+		Table: &models.Permission{}, Column: "update_timestamp", Value: int64(block.Timestamp),
+	})
+	if err != nil {
+		return err
+	}
+
+	log.Infow("rolled back", "module", m.Name(), "height", height, "deleted", deleted)
+	return nil
+}
@@ -13,6 +13,7 @@ import (
 	"github.com/forbole/juno/v4/common"
 	"github.com/forbole/juno/v4/log"
 	"github.com/forbole/juno/v4/models"
+	"github.com/forbole/juno/v4/sink"
 )
 
 var (
@@ -40,7 +41,16 @@ var actionTypeMap = map[permissiontypes.ActionType]int{
 	//permissiontypes.ACTION_GROUP_MEMBER:        11,
 }
 
-func (m *Module) HandleEvent(ctx context.Context, block *tmctypes.ResultBlock, _ common.Hash, event sdk.Event) error {
+// ActionMask returns the bit representing the given action inside models.Statements.ActionValue
+func ActionMask(action permissiontypes.ActionType) (int, bool) {
+	value, ok := actionTypeMap[action]
+	if !ok {
+		return 0, false
+	}
+	return 1 << value, true
+}
+
+func (m *Module) HandleEvent(ctx context.Context, block *tmctypes.ResultBlock, txHash common.Hash, event sdk.Event) error {
 	if !policyEvents[event.Type] {
 		return nil
 	}
@@ -57,20 +67,20 @@ func (m *Module) HandleEvent(ctx context.Context, block *tmctypes.ResultBlock, _
 			log.Errorw("type assert error", "type", "EventCreateObject", "event", typedEvent)
 			return errors.New("put policy event assert error")
 		}
-		return m.handlePutPolicy(ctx, block, putPolicy)
+		return m.handlePutPolicy(ctx, block, txHash, putPolicy)
 	case EventDeletePolicy:
 		deletePolicy, ok := typedEvent.(*permissiontypes.EventDeletePolicy)
 		if !ok {
 			log.Errorw("type assert error", "type", "EventCancelCreateObject", "event", typedEvent)
 			return errors.New("cancel delete policy event assert error")
 		}
-		return m.handleDeletePolicy(ctx, block, deletePolicy)
+		return m.handleDeletePolicy(ctx, block, txHash, deletePolicy)
 	}
 
 	return nil
 }
 
-func (m *Module) handlePutPolicy(ctx context.Context, block *tmctypes.ResultBlock, policy *permissiontypes.EventPutPolicy) error {
+func (m *Module) handlePutPolicy(ctx context.Context, block *tmctypes.ResultBlock, txHash common.Hash, policy *permissiontypes.EventPutPolicy) error {
 	var expireTime int64
 	if policy.ExpirationTime == nil {
 		expireTime = 0
@@ -98,7 +108,7 @@ func (m *Module) handlePutPolicy(ctx context.Context, block *tmctypes.ResultBloc
 			actionValue |= 1 << value
 		}
 		s := &models.Statements{
-			PolicyID:    common.HexToHash(policy.PolicyId.String()),
+			PolicyID:    p.PolicyID,
 			Effect:      statement.Effect.String(),
 			ActionValue: actionValue,
 		}
@@ -106,7 +116,8 @@ func (m *Module) handlePutPolicy(ctx context.Context, block *tmctypes.ResultBloc
 			s.ExpirationTime = statement.ExpirationTime.UTC().Unix()
 		}
 		if statement.LimitSize != nil {
-			s.LimitSize = statement.LimitSize.Value
+			limitSize := statement.LimitSize.Value
+			s.LimitSize = &limitSize
 		}
 		if len(statement.Resources) != 0 {
 			s.Resources = statement.Resources
@@ -114,34 +125,45 @@ func (m *Module) handlePutPolicy(ctx context.Context, block *tmctypes.ResultBloc
 		statements = append(statements, s)
 	}
 
-	// begin transaction
-	tx := m.db.Begin(ctx)
-	err1 := tx.SavePermission(ctx, p)
-	err2 := tx.MultiSaveStatement(ctx, statements)
-	err3 := tx.Commit()
-	if err1 != nil || err2 != nil || err3 != nil {
-		tx.Rollback()
-		log.Errorw("failed to save policy", "permission err", err1, "statement err", err2, "commit err", err3)
-		return errors.New("save policy transaction failed")
+	if err := m.sink.Emit(ctx, sink.NewRecord(ModuleName, block.Block.Height, txHash, sink.OperationSave, p)); err != nil {
+		log.Errorw("failed to save policy", "policy_id", p.PolicyID, "err", err)
+		return err
+	}
+	// putting an existing policy keeps its id and replaces all its statements
+	err := m.sink.Emit(ctx, sink.NewRecord(ModuleName, block.Block.Height, txHash, sink.OperationDelete, &models.Statements{PolicyID: p.PolicyID}))
+	if err != nil {
+		log.Errorw("failed to remove previous policy statements", "policy_id", p.PolicyID, "err", err)
+		return err
+	}
+	if len(statements) == 0 {
+		return nil
+	}
+	records := make([]*sink.Record, 0, len(statements))
+	for _, s := range statements {
+		records = append(records, sink.NewRecord(ModuleName, block.Block.Height, txHash, sink.OperationSave, s))
+	}
+	if err := m.sink.Emit(ctx, records...); err != nil {
+		log.Errorw("failed to save policy statements", "policy_id", p.PolicyID, "err", err)
+		return err
 	}
 	return nil
 }
 
-func (m *Module) handleDeletePolicy(ctx context.Context, block *tmctypes.ResultBlock, event *permissiontypes.EventDeletePolicy) error {
-	// begin transaction
-	tx := m.db.Begin(ctx)
+func (m *Module) handleDeletePolicy(ctx context.Context, block *tmctypes.ResultBlock, txHash common.Hash, event *permissiontypes.EventDeletePolicy) error {
 	policyIDHash := common.BigToHash(event.PolicyId.BigInt())
-	err1 := tx.UpdatePermission(ctx, &models.Permission{
+	err := m.sink.Emit(ctx, sink.NewRecord(ModuleName, block.Block.Height, txHash, sink.OperationUpdate, &models.Permission{
 		PolicyID:        policyIDHash,
 		Removed:         true,
 		UpdateTimestamp: block.Block.Time.Unix(),
-	})
-	err2 := tx.RemoveStatements(ctx, policyIDHash)
-	err3 := tx.Commit()
-	if err1 != nil || err2 != nil || err3 != nil {
-		tx.Rollback()
-		log.Errorw("failed to delete policy", "permission err", err1, "statement err", err2, "commit err", err3)
-		return errors.New("delete policy transaction failed")
+	}))
+	if err != nil {
+		log.Errorw("failed to delete policy", "policy_id", policyIDHash, "err", err)
+		return err
+	}
+	err = m.sink.Emit(ctx, sink.NewRecord(ModuleName, block.Block.Height, txHash, sink.OperationDelete, &models.Statements{PolicyID: policyIDHash}))
+	if err != nil {
+		log.Errorw("failed to delete policy statements", "policy_id", policyIDHash, "err", err)
+		return err
 	}
 	return nil
 }
@@ -1,7 +1,9 @@
 package pruning
 
 import (
+	"context"
 	"fmt"
+
 	"github.com/forbole/juno/v4/log"
 
 	tmctypes "github.com/tendermint/tendermint/rpc/core/types"
@@ -10,9 +12,10 @@ import (
 	"github.com/forbole/juno/v4/types"
 )
 
-// HandleBlock implements modules.BlockModule
+// HandleBlock implements modules.BlockModule.
+// The heights are pruned within the database transaction of the given height, carried by ctx.
 func (m *Module) HandleBlock(
-	block *tmctypes.ResultBlock, _ *tmctypes.ResultBlockResults, _ []*types.Tx, _ *tmctypes.ResultValidators,
+	ctx context.Context, block *tmctypes.ResultBlock, _ *tmctypes.ResultBlockResults, _ []*types.Tx, _ *tmctypes.ResultValidators,
 ) error {
 	if block.Block.Height%m.cfg.Interval != 0 {
 		// Not an interval height, so just skip
@@ -25,7 +28,7 @@ func (m *Module) HandleBlock(
 	}
 
 	// Get last pruned height
-	var height, err = pruningDb.GetLastPruned()
+	var height, err = pruningDb.GetLastPruned(ctx)
 	if err != nil {
 		return err
 	}
@@ -41,11 +44,11 @@ func (m *Module) HandleBlock(
 
 		// Prune the height
 		log.Debugw("pruning", "module", "pruning", "height", height)
-		err = pruningDb.Prune(height)
+		err = pruningDb.Prune(ctx, height)
 		if err != nil {
 			return fmt.Errorf("error while pruning height %d: %s", height, err.Error())
 		}
 	}
 
-	return pruningDb.StoreLastPruned(height)
+	return pruningDb.StoreLastPruned(ctx, height)
 }
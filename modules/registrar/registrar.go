@@ -7,18 +7,24 @@ import (
 	"github.com/forbole/juno/v4/database"
 	"github.com/forbole/juno/v4/log"
 	"github.com/forbole/juno/v4/modules"
+	"github.com/forbole/juno/v4/modules/bank"
 	"github.com/forbole/juno/v4/modules/block"
 	"github.com/forbole/juno/v4/modules/bucket"
+	"github.com/forbole/juno/v4/modules/crosschain"
 	"github.com/forbole/juno/v4/modules/epoch"
+	"github.com/forbole/juno/v4/modules/gov"
 	"github.com/forbole/juno/v4/modules/group"
 	"github.com/forbole/juno/v4/modules/messages"
 	"github.com/forbole/juno/v4/modules/object"
 	"github.com/forbole/juno/v4/modules/payment"
 	"github.com/forbole/juno/v4/modules/permission"
 	"github.com/forbole/juno/v4/modules/pruning"
+	"github.com/forbole/juno/v4/modules/sp"
+	"github.com/forbole/juno/v4/modules/staking"
 	"github.com/forbole/juno/v4/modules/telemetry"
 	"github.com/forbole/juno/v4/modules/validator"
 	"github.com/forbole/juno/v4/node"
+	"github.com/forbole/juno/v4/sink"
 	"github.com/forbole/juno/v4/types/config"
 )
 
@@ -28,19 +34,21 @@ type Context struct {
 	SDKConfig      *sdk.Config
 	EncodingConfig *params.EncodingConfig
 	Database       database.Database
+	Sink           sink.Sink
 	Proxy          node.Node
 }
 
 // NewContext allows to build a new Context instance
 func NewContext(
 	parsingConfig config.Config, sdkConfig *sdk.Config, encodingConfig *params.EncodingConfig,
-	database database.Database, proxy node.Node,
+	database database.Database, sink sink.Sink, proxy node.Node,
 ) Context {
 	return Context{
 		JunoConfig:     parsingConfig,
 		SDKConfig:      sdkConfig,
 		EncodingConfig: encodingConfig,
 		Database:       database,
+		Sink:           sink,
 		Proxy:          proxy,
 	}
 }
@@ -87,16 +95,22 @@ func NewDefaultRegistrar(parser messages.MessageAddressesParser) *DefaultRegistr
 func (r *DefaultRegistrar) BuildModules(ctx Context) modules.Modules {
 	return modules.Modules{
 		block.NewModule(ctx.Database),
-		validator.NewModule(ctx.Database),
-		bucket.NewModule(ctx.Database),
-		group.NewModule(ctx.Database),
-		object.NewModule(ctx.Database),
+		validator.NewModule(ctx.JunoConfig, ctx.Database, ctx.Sink),
+		bucket.NewModule(ctx.JunoConfig, ctx.Database, ctx.Sink),
+		group.NewModule(ctx.JunoConfig, ctx.Database, ctx.Sink),
+		object.NewModule(ctx.JunoConfig, ctx.Database, ctx.Sink),
 		pruning.NewModule(ctx.JunoConfig, ctx.Database),
 		telemetry.NewModule(ctx.JunoConfig),
 		epoch.NewModule(ctx.Database),
-		payment.NewModule(ctx.Database),
-		permission.NewModule(ctx.Database),
-		group.NewModule(ctx.Database),
+		payment.NewModule(ctx.Database, ctx.Sink),
+		permission.NewModule(ctx.Database, ctx.Sink),
+		sp.NewModule(ctx.Database, ctx.Sink, ctx.EncodingConfig.Marshaler),
+		crosschain.NewModule(ctx.Database, ctx.Sink),
+		gov.NewModule(ctx.JunoConfig, ctx.Database, ctx.Sink),
+		staking.NewModule(ctx.JunoConfig, ctx.Database, ctx.Sink, ctx.EncodingConfig.InterfaceRegistry),
+		bank.NewModule(ctx.JunoConfig, ctx.Database, ctx.Sink, ctx.EncodingConfig.Marshaler),
+		messages.NewModule(r.parser, ctx.EncodingConfig.Marshaler, ctx.Database, ctx.Sink),
+		group.NewModule(ctx.JunoConfig, ctx.Database, ctx.Sink),
 	}
 }
 
@@ -0,0 +1,49 @@
+package modules
+
+import (
+	"context"
+	"fmt"
+
+	"gorm.io/gorm/schema"
+
+	"github.com/forbole/juno/v4/database"
+)
+
+// StaleRowsError is returned by RollbackModule.Rollback when some rows updated after the rollback height cannot be
+// restored to their state at that height. The tables of the module must then be recreated and its heights reprocessed.
+type StaleRowsError struct {
+	Module string
+	Rows   int64
+}
+
+// Error implements error
+func (e *StaleRowsError) Error() string {
+	return fmt.Sprintf("%d rows of module %s were updated after the rollback height and cannot be restored",
+		e.Rows, e.Module)
+}
+
+// UpdatedAfter identifies the rows of a table that were updated after the rollback height,
+// which are the ones having the given column greater than the given value
+type UpdatedAfter struct {
+	Table  schema.Tabler
+	Column string
+	Value  int64
+}
+
+// CheckNotUpdated returns a StaleRowsError if any of the given tables has rows updated after the rollback height.
+// It is used by the modules updating rows in place without keeping their previous state.
+func CheckNotUpdated(ctx context.Context, db database.Database, module string, tables ...UpdatedAfter) error {
+	var stale int64
+	for _, table := range tables {
+		rows, err := db.CountAfter(ctx, table.Table, table.Column, table.Value)
+		if err != nil {
+			return err
+		}
+		stale += rows
+	}
+
+	if stale > 0 {
+		return &StaleRowsError{Module: module, Rows: stale}
+	}
+	return nil
+}
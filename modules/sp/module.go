@@ -0,0 +1,87 @@
+package sp
+
+import (
+	"context"
+
+	"github.com/cosmos/cosmos-sdk/codec"
+	"gorm.io/gorm/schema"
+
+	"github.com/forbole/juno/v4/database"
+	"github.com/forbole/juno/v4/log"
+	"github.com/forbole/juno/v4/models"
+	"github.com/forbole/juno/v4/modules"
+	"github.com/forbole/juno/v4/sink"
+)
+
+const (
+	ModuleName = "sp"
+)
+
+var (
+	_ modules.Module              = &Module{}
+	_ modules.PrepareTablesModule = &Module{}
+	_ modules.RollbackModule      = &Module{}
+	_ modules.ReadBackModule      = &Module{}
+	_ modules.GenesisModule       = &Module{}
+)
+
+// Module represents the storage provider module
+type Module struct {
+	db   database.Database
+	sink sink.Sink
+	cdc  codec.JSONCodec
+}
+
+// NewModule builds a new Module instance.
+// The codec is used to read the sp and gensp genesis states.
+func NewModule(db database.Database, sink sink.Sink, cdc codec.JSONCodec) *Module {
+	return &Module{
+		db:   db,
+		sink: sink,
+		cdc:  cdc,
+	}
+}
+
+// Name implements modules.Module
+func (m *Module) Name() string {
+	return ModuleName
+}
+
+// ReadsBack implements modules.ReadBackModule.
+// The storage providers are read back when edited or deposited to.
+func (m *Module) ReadsBack() bool {
+	return true
+}
+
+// PrepareTables implements
+func (m *Module) PrepareTables() error {
+	return m.db.PrepareTables(context.TODO(), []schema.Tabler{&models.StorageProvider{}, &models.StoragePrice{}})
+}
+
+// RecreateTables implements
+func (m *Module) RecreateTables() error {
+	return m.db.RecreateTables(context.TODO(), []schema.Tabler{&models.StorageProvider{}, &models.StoragePrice{}})
+}
+
+// Rollback implements modules.RollbackModule
+func (m *Module) Rollback(ctx context.Context, height uint64) error {
+	deleted, err := m.db.DeleteAfter(ctx, &models.StorageProvider{}, "create_at", int64(height))
+	if err != nil {
+		return err
+	}
+
+	_, err = m.db.DeleteAfter(ctx, &models.StoragePrice{}, "height", int64(height))
+	if err != nil {
+		return err
+	}
+
+	err = modules.CheckNotUpdated(ctx, m.db, m.Name(), modules.UpdatedAfter{
+		Table: &models.StorageProvider{}, Column: "update_at", Value: int64(height),
+	})
+	if err != nil {
+		return err
+	}
+
+	log.Infow("rolled back", "module", m.Name(), "height", height, "deleted", deleted)
+	return nil
+}
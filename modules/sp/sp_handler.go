@@ -0,0 +1,300 @@
+package sp
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	gensptypes "github.com/bnb-chain/greenfield/x/gensp/types"
+	sptypes "github.com/bnb-chain/greenfield/x/sp/types"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	txtypes "github.com/cosmos/cosmos-sdk/types/tx"
+	"github.com/gogo/protobuf/proto"
+	abci "github.com/tendermint/tendermint/abci/types"
+	tmctypes "github.com/tendermint/tendermint/rpc/core/types"
+	tmtypes "github.com/tendermint/tendermint/types"
+
+	"github.com/forbole/juno/v4/common"
+	"github.com/forbole/juno/v4/log"
+	"github.com/forbole/juno/v4/models"
+	"github.com/forbole/juno/v4/sink"
+)
+
+var (
+	EventCreateStorageProvider       = proto.MessageName(&sptypes.EventCreateStorageProvider{})
+	EventEditStorageProvider         = proto.MessageName(&sptypes.EventEditStorageProvider{})
+	EventDeposit                     = proto.MessageName(&sptypes.EventDeposit{})
+	EventSpStoragePriceUpdate        = proto.MessageName(&sptypes.EventSpStoragePriceUpdate{})
+	EventSecondarySpStorePriceUpdate = proto.MessageName(&sptypes.EventSecondarySpStorePriceUpdate{})
+)
+
+var spEvents = map[string]bool{
+	EventCreateStorageProvider:       true,
+	EventEditStorageProvider:         true,
+	EventDeposit:                     true,
+	EventSpStoragePriceUpdate:        true,
+	EventSecondarySpStorePriceUpdate: true,
+}
+
+// HandleGenesis implements modules.GenesisModule.
+// The storage providers of the genesis state, along with the ones created by the genesis transactions of the gensp
+// module, are stored as created at height zero, since the events emitted while initializing the chain belong to
+// no block.
+func (m *Module) HandleGenesis(doc *tmtypes.GenesisDoc, appState map[string]json.RawMessage) error {
+	var genState sptypes.GenesisState
+	err := m.cdc.UnmarshalJSON(appState[sptypes.ModuleName], &genState)
+	if err != nil {
+		return fmt.Errorf("error while unmarshalling sp genesis state: %s", err)
+	}
+
+	var genspState gensptypes.GenesisState
+	if appState[gensptypes.ModuleName] != nil {
+		err = m.cdc.UnmarshalJSON(appState[gensptypes.ModuleName], &genspState)
+		if err != nil {
+			return fmt.Errorf("error while unmarshalling gensp genesis state: %s", err)
+		}
+	}
+
+	genesisTime := doc.GenesisTime.UTC().Unix()
+	newStorageProvider := func(operatorAddress string) *models.StorageProvider {
+		return &models.StorageProvider{
+			OperatorAddress: common.HexToAddress(operatorAddress),
+			DepositDenom:    genState.Params.DepositDenom,
+			CreateTime:      genesisTime,
+			UpdateTime:      genesisTime,
+		}
+	}
+
+	var sps []*models.StorageProvider
+	var prices []*models.StoragePrice
+	for _, genSp := range genState.StorageProviders {
+		sp := newStorageProvider(genSp.OperatorAddress)
+		sp.FundingAddress = common.HexToAddress(genSp.FundingAddress)
+		sp.SealAddress = common.HexToAddress(genSp.SealAddress)
+		sp.ApprovalAddress = common.HexToAddress(genSp.ApprovalAddress)
+		sp.Endpoint = genSp.Endpoint
+		sp.TotalDeposit = (*common.Big)(genSp.TotalDeposit.BigInt())
+		sps = append(sps, sp)
+	}
+	for _, genPrice := range genState.SpStoragePriceList {
+		prices = append(prices, &models.StoragePrice{
+			SpAddress:     common.HexToAddress(genPrice.SpAddress),
+			UpdateTimeSec: genPrice.UpdateTimeSec,
+			ReadPrice:     genPrice.ReadPrice.String(),
+			FreeReadQuota: genPrice.FreeReadQuota,
+			StorePrice:    genPrice.StorePrice.String(),
+		})
+	}
+
+	// the genesis transactions are delivered after the genesis state of the sp module is initialized
+	for _, genTx := range genspState.GenspTxs {
+		var tx txtypes.Tx
+		err = m.cdc.UnmarshalJSON(genTx, &tx)
+		if err != nil {
+			return fmt.Errorf("error while unmarshalling gensp transaction: %s", err)
+		}
+
+		for _, msg := range tx.GetMsgs() {
+			createStorageProvider, ok := msg.(*sptypes.MsgCreateStorageProvider)
+			if !ok {
+				continue
+			}
+
+			sp := newStorageProvider(createStorageProvider.SpAddress)
+			sp.FundingAddress = common.HexToAddress(createStorageProvider.FundingAddress)
+			sp.SealAddress = common.HexToAddress(createStorageProvider.SealAddress)
+			sp.ApprovalAddress = common.HexToAddress(createStorageProvider.ApprovalAddress)
+			sp.Endpoint = createStorageProvider.Endpoint
+			sp.DepositDenom = createStorageProvider.Deposit.Denom
+			sp.TotalDeposit = (*common.Big)(createStorageProvider.Deposit.Amount.BigInt())
+			sps = append(sps, sp)
+
+			prices = append(prices, &models.StoragePrice{
+				SpAddress:     sp.OperatorAddress,
+				UpdateTimeSec: genesisTime,
+				ReadPrice:     createStorageProvider.ReadPrice.String(),
+				FreeReadQuota: createStorageProvider.FreeReadQuota,
+				StorePrice:    createStorageProvider.StorePrice.String(),
+			})
+		}
+	}
+
+	// the storage providers keep their latest price
+	for _, price := range prices {
+		for _, sp := range sps {
+			if sp.OperatorAddress == price.SpAddress {
+				sp.ReadPrice, sp.FreeReadQuota, sp.StorePrice = price.ReadPrice, price.FreeReadQuota, price.StorePrice
+				sp.PriceUpdateTime = price.UpdateTimeSec
+			}
+		}
+	}
+
+	var records []*sink.Record
+	for _, sp := range sps {
+		records = append(records, sink.NewRecord(ModuleName, 0, common.Hash{}, sink.OperationSave, sp))
+	}
+	for _, price := range prices {
+		records = append(records, sink.NewRecord(ModuleName, 0, common.Hash{}, sink.OperationSave, price))
+	}
+	return m.sink.Emit(context.Background(), records...)
+}
+
+func (m *Module) HandleEvent(ctx context.Context, block *tmctypes.ResultBlock, txHash common.Hash, event sdk.Event) error {
+	if !spEvents[event.Type] {
+		return nil
+	}
+
+	typedEvent, err := sdk.ParseTypedEvent(abci.Event(event))
+	if err != nil {
+		log.Errorw("parse typed events error", "module", m.Name(), "event", event, "err", err)
+		return err
+	}
+
+	switch event.Type {
+	case EventCreateStorageProvider:
+		createStorageProvider, ok := typedEvent.(*sptypes.EventCreateStorageProvider)
+		if !ok {
+			log.Errorw("type assert error", "type", "EventCreateStorageProvider", "event", typedEvent)
+			return errors.New("create storage provider event assert error")
+		}
+		return m.handleCreateStorageProvider(ctx, block, txHash, createStorageProvider)
+	case EventEditStorageProvider:
+		editStorageProvider, ok := typedEvent.(*sptypes.EventEditStorageProvider)
+		if !ok {
+			log.Errorw("type assert error", "type", "EventEditStorageProvider", "event", typedEvent)
+			return errors.New("edit storage provider event assert error")
+		}
+		return m.handleEditStorageProvider(ctx, block, txHash, editStorageProvider)
+	case EventDeposit:
+		deposit, ok := typedEvent.(*sptypes.EventDeposit)
+		if !ok {
+			log.Errorw("type assert error", "type", "EventDeposit", "event", typedEvent)
+			return errors.New("deposit event assert error")
+		}
+		return m.handleDeposit(ctx, block, txHash, deposit)
+	case EventSpStoragePriceUpdate:
+		priceUpdate, ok := typedEvent.(*sptypes.EventSpStoragePriceUpdate)
+		if !ok {
+			log.Errorw("type assert error", "type", "EventSpStoragePriceUpdate", "event", typedEvent)
+			return errors.New("sp storage price update event assert error")
+		}
+		return m.handleSpStoragePriceUpdate(ctx, block, txHash, priceUpdate)
+	case EventSecondarySpStorePriceUpdate:
+		priceUpdate, ok := typedEvent.(*sptypes.EventSecondarySpStorePriceUpdate)
+		if !ok {
+			log.Errorw("type assert error", "type", "EventSecondarySpStorePriceUpdate", "event", typedEvent)
+			return errors.New("secondary sp store price update event assert error")
+		}
+		return m.handleSecondarySpStorePriceUpdate(ctx, block, txHash, priceUpdate)
+	}
+
+	return nil
+}
+
+func (m *Module) handleCreateStorageProvider(ctx context.Context, block *tmctypes.ResultBlock, txHash common.Hash, createStorageProvider *sptypes.EventCreateStorageProvider) error {
+	sp := &models.StorageProvider{
+		OperatorAddress: common.HexToAddress(createStorageProvider.SpAddress),
+		FundingAddress:  common.HexToAddress(createStorageProvider.FundingAddress),
+		SealAddress:     common.HexToAddress(createStorageProvider.SealAddress),
+		ApprovalAddress: common.HexToAddress(createStorageProvider.ApprovalAddress),
+		Endpoint:        createStorageProvider.Endpoint,
+
+		CreateAt:     block.Block.Height,
+		CreateTxHash: txHash,
+		CreateTime:   block.Block.Time.UTC().Unix(),
+		UpdateAt:     block.Block.Height,
+		UpdateTxHash: txHash,
+		UpdateTime:   block.Block.Time.UTC().Unix(),
+	}
+	if createStorageProvider.TotalDeposit != nil {
+		sp.DepositDenom = createStorageProvider.TotalDeposit.Denom
+		sp.TotalDeposit = (*common.Big)(createStorageProvider.TotalDeposit.Amount.BigInt())
+	}
+
+	return m.sink.Emit(ctx, sink.NewRecord(ModuleName, block.Block.Height, txHash, sink.OperationSave, sp))
+}
+
+func (m *Module) handleEditStorageProvider(ctx context.Context, block *tmctypes.ResultBlock, txHash common.Hash, editStorageProvider *sptypes.EventEditStorageProvider) error {
+	// the event only contains the endpoints, so the storage provider is found using the previous one
+	sp, err := m.db.GetStorageProviderByEndpoint(ctx, editStorageProvider.OldEndpoint)
+	if err != nil {
+		return err
+	}
+	if sp == nil {
+		return fmt.Errorf("storage provider with endpoint %s not found", editStorageProvider.OldEndpoint)
+	}
+
+	return m.sink.Emit(ctx, sink.NewRecord(ModuleName, block.Block.Height, txHash, sink.OperationUpdate, &models.StorageProvider{
+		OperatorAddress: sp.OperatorAddress,
+		Endpoint:        editStorageProvider.NewEndpoint,
+		UpdateAt:        block.Block.Height,
+		UpdateTxHash:    txHash,
+		UpdateTime:      block.Block.Time.UTC().Unix(),
+	}))
+}
+
+func (m *Module) handleDeposit(ctx context.Context, block *tmctypes.ResultBlock, txHash common.Hash, deposit *sptypes.EventDeposit) error {
+	totalDeposit, err := sdk.ParseCoinNormalized(deposit.TotalDeposit)
+	if err != nil {
+		return fmt.Errorf("invalid total deposit %s: %s", deposit.TotalDeposit, err)
+	}
+
+	sp, err := m.db.GetStorageProviderByFundingAddress(ctx, common.HexToAddress(deposit.FundingAddress))
+	if err != nil {
+		return err
+	}
+	if sp == nil {
+		return fmt.Errorf("storage provider with funding address %s not found", deposit.FundingAddress)
+	}
+
+	return m.sink.Emit(ctx, sink.NewRecord(ModuleName, block.Block.Height, txHash, sink.OperationUpdate, &models.StorageProvider{
+		OperatorAddress: sp.OperatorAddress,
+		DepositDenom:    totalDeposit.Denom,
+		TotalDeposit:    (*common.Big)(totalDeposit.Amount.BigInt()),
+		UpdateAt:        block.Block.Height,
+		UpdateTxHash:    txHash,
+		UpdateTime:      block.Block.Time.UTC().Unix(),
+	}))
+}
+
+func (m *Module) handleSpStoragePriceUpdate(ctx context.Context, block *tmctypes.ResultBlock, txHash common.Hash, priceUpdate *sptypes.EventSpStoragePriceUpdate) error {
+	spAddress := common.HexToAddress(priceUpdate.SpAddress)
+
+	price := &models.StoragePrice{
+		SpAddress:     spAddress,
+		UpdateTimeSec: priceUpdate.UpdateTimeSec,
+		ReadPrice:     priceUpdate.ReadPrice.String(),
+		FreeReadQuota: priceUpdate.FreeReadQuota,
+		StorePrice:    priceUpdate.StorePrice.String(),
+		Height:        block.Block.Height,
+		TxHash:        txHash,
+	}
+
+	sp := &models.StorageProvider{
+		OperatorAddress: spAddress,
+		ReadPrice:       price.ReadPrice,
+		FreeReadQuota:   price.FreeReadQuota,
+		StorePrice:      price.StorePrice,
+		PriceUpdateTime: price.UpdateTimeSec,
+		UpdateAt:        block.Block.Height,
+		UpdateTxHash:    txHash,
+		UpdateTime:      block.Block.Time.UTC().Unix(),
+	}
+
+	return m.sink.Emit(ctx,
+		sink.NewRecord(ModuleName, block.Block.Height, txHash, sink.OperationSave, price),
+		sink.NewRecord(ModuleName, block.Block.Height, txHash, sink.OperationUpdate, sp),
+	)
+}
+
+func (m *Module) handleSecondarySpStorePriceUpdate(ctx context.Context, block *tmctypes.ResultBlock, txHash common.Hash, priceUpdate *sptypes.EventSecondarySpStorePriceUpdate) error {
+	price := &models.StoragePrice{
+		UpdateTimeSec: priceUpdate.UpdateTimeSec,
+		StorePrice:    priceUpdate.StorePrice.String(),
+		Height:        block.Block.Height,
+		TxHash:        txHash,
+	}
+
+	return m.sink.Emit(ctx, sink.NewRecord(ModuleName, block.Block.Height, txHash, sink.OperationSave, price))
+}
@@ -0,0 +1,255 @@
+package sp
+
+import (
+	"context"
+	"encoding/json"
+	"math/big"
+	"testing"
+	"time"
+
+	gensptypes "github.com/bnb-chain/greenfield/x/gensp/types"
+	sptypes "github.com/bnb-chain/greenfield/x/sp/types"
+	"github.com/cosmos/cosmos-sdk/codec"
+	codectypes "github.com/cosmos/cosmos-sdk/codec/types"
+	"github.com/cosmos/cosmos-sdk/std"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	txtypes "github.com/cosmos/cosmos-sdk/types/tx"
+	"github.com/gogo/protobuf/proto"
+	"github.com/stretchr/testify/require"
+	tmctypes "github.com/tendermint/tendermint/rpc/core/types"
+	tmtypes "github.com/tendermint/tendermint/types"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+
+	"github.com/forbole/juno/v4/common"
+	"github.com/forbole/juno/v4/database"
+	"github.com/forbole/juno/v4/models"
+	"github.com/forbole/juno/v4/sink"
+)
+
+const (
+	operatorAddress = "0x1111111111111111111111111111111111111111"
+	fundingAddress  = "0x2222222222222222222222222222222222222222"
+)
+
+// stubDatabase serves the storage provider looked up by the handlers, any other method panicking
+type stubDatabase struct {
+	database.Database
+
+	sp *models.StorageProvider
+}
+
+func (db *stubDatabase) GetStorageProviderByEndpoint(_ context.Context, endpoint string) (*models.StorageProvider, error) {
+	if db.sp == nil || db.sp.Endpoint != endpoint {
+		return nil, nil
+	}
+	return db.sp, nil
+}
+
+func (db *stubDatabase) GetStorageProviderByFundingAddress(_ context.Context, address common.Address) (*models.StorageProvider, error) {
+	if db.sp == nil || db.sp.FundingAddress != address {
+		return nil, nil
+	}
+	return db.sp, nil
+}
+
+// recordingSink keeps the emitted records
+type recordingSink struct {
+	records []*sink.Record
+}
+
+func (s *recordingSink) Emit(_ context.Context, records ...*sink.Record) error {
+	s.records = append(s.records, records...)
+	return nil
+}
+
+func (s *recordingSink) Close() error {
+	return nil
+}
+
+// handle makes the module handle the given event at height 10, returning the emitted records
+func handle(t *testing.T, db *stubDatabase, event proto.Message) ([]*sink.Record, error) {
+	t.Helper()
+
+	sdkEvent, err := sdk.TypedEventToEvent(event)
+	require.NoError(t, err)
+
+	recorder := &recordingSink{}
+	block := &tmctypes.ResultBlock{Block: &tmtypes.Block{Header: tmtypes.Header{Height: 10, Time: time.Unix(1000, 0)}}}
+	err = NewModule(db, recorder, nil).HandleEvent(context.Background(), block, common.HexToHash("0x01"), sdkEvent)
+	return recorder.records, err
+}
+
+func TestHandleCreateStorageProvider(t *testing.T) {
+	records, err := handle(t, &stubDatabase{}, &sptypes.EventCreateStorageProvider{
+		SpAddress:      operatorAddress,
+		FundingAddress: fundingAddress,
+		Endpoint:       "https://sp.example",
+		TotalDeposit:   &sdk.Coin{Denom: "BNB", Amount: sdk.NewInt(100)},
+	})
+	require.NoError(t, err)
+	require.Len(t, records, 1)
+	require.Equal(t, sink.OperationSave, records[0].Operation)
+
+	sp := records[0].Data.(*models.StorageProvider)
+	require.Equal(t, common.HexToAddress(operatorAddress), sp.OperatorAddress)
+	require.Equal(t, common.HexToAddress(fundingAddress), sp.FundingAddress)
+	require.Equal(t, "https://sp.example", sp.Endpoint)
+	require.Equal(t, "BNB", sp.DepositDenom)
+	require.Equal(t, big.NewInt(100), (*big.Int)(sp.TotalDeposit))
+	require.Equal(t, int64(10), sp.CreateAt)
+	require.Equal(t, int64(1000), sp.CreateTime)
+}
+
+func TestHandleEditStorageProvider(t *testing.T) {
+	db := &stubDatabase{sp: &models.StorageProvider{
+		OperatorAddress: common.HexToAddress(operatorAddress),
+		Endpoint:        "https://old.example",
+	}}
+
+	records, err := handle(t, db, &sptypes.EventEditStorageProvider{
+		OldEndpoint: "https://old.example",
+		NewEndpoint: "https://new.example",
+	})
+	require.NoError(t, err)
+	require.Len(t, records, 1)
+	require.Equal(t, sink.OperationUpdate, records[0].Operation)
+
+	sp := records[0].Data.(*models.StorageProvider)
+	require.Equal(t, common.HexToAddress(operatorAddress), sp.OperatorAddress)
+	require.Equal(t, "https://new.example", sp.Endpoint)
+	require.Equal(t, int64(10), sp.UpdateAt)
+
+	_, err = handle(t, db, &sptypes.EventEditStorageProvider{OldEndpoint: "https://unknown.example"})
+	require.Error(t, err)
+}
+
+func TestHandleDeposit(t *testing.T) {
+	db := &stubDatabase{sp: &models.StorageProvider{
+		OperatorAddress: common.HexToAddress(operatorAddress),
+		FundingAddress:  common.HexToAddress(fundingAddress),
+	}}
+
+	records, err := handle(t, db, &sptypes.EventDeposit{
+		FundingAddress: fundingAddress,
+		Deposit:        "50BNB",
+		TotalDeposit:   "150BNB",
+	})
+	require.NoError(t, err)
+	require.Len(t, records, 1)
+
+	sp := records[0].Data.(*models.StorageProvider)
+	require.Equal(t, common.HexToAddress(operatorAddress), sp.OperatorAddress)
+	require.Equal(t, "BNB", sp.DepositDenom)
+	require.Equal(t, big.NewInt(150), (*big.Int)(sp.TotalDeposit))
+
+	_, err = handle(t, db, &sptypes.EventDeposit{FundingAddress: operatorAddress, TotalDeposit: "150BNB"})
+	require.Error(t, err)
+
+	_, err = handle(t, db, &sptypes.EventDeposit{FundingAddress: fundingAddress, TotalDeposit: "invalid"})
+	require.Error(t, err)
+}
+
+func TestHandleStoragePriceUpdate(t *testing.T) {
+	records, err := handle(t, &stubDatabase{}, &sptypes.EventSpStoragePriceUpdate{
+		SpAddress:     operatorAddress,
+		UpdateTimeSec: 900,
+		ReadPrice:     sdk.NewDec(2),
+		FreeReadQuota: 1024,
+		StorePrice:    sdk.NewDec(3),
+	})
+	require.NoError(t, err)
+	require.Len(t, records, 2)
+
+	price := records[0].Data.(*models.StoragePrice)
+	require.Equal(t, sink.OperationSave, records[0].Operation)
+	require.Equal(t, common.HexToAddress(operatorAddress), price.SpAddress)
+	require.Equal(t, sdk.NewDec(2).String(), price.ReadPrice)
+	require.Equal(t, int64(10), price.Height)
+
+	sp := records[1].Data.(*models.StorageProvider)
+	require.Equal(t, sink.OperationUpdate, records[1].Operation)
+	require.Equal(t, price.StorePrice, sp.StorePrice)
+	require.Equal(t, int64(900), sp.PriceUpdateTime)
+
+	records, err = handle(t, &stubDatabase{}, &sptypes.EventSecondarySpStorePriceUpdate{
+		UpdateTimeSec: 900,
+		StorePrice:    sdk.NewDec(1),
+	})
+	require.NoError(t, err)
+	require.Len(t, records, 1)
+	require.Equal(t, common.Address{}, records[0].Data.(*models.StoragePrice).SpAddress)
+}
+
+func TestHandleGenesis(t *testing.T) {
+	registry := codectypes.NewInterfaceRegistry()
+	std.RegisterInterfaces(registry)
+	sptypes.RegisterInterfaces(registry)
+	cdc := codec.NewProtoCodec(registry)
+
+	genState := sptypes.DefaultGenesis()
+	genState.StorageProviders = []sptypes.StorageProvider{{
+		OperatorAddress: operatorAddress,
+		FundingAddress:  fundingAddress,
+		Endpoint:        "https://genesis.example",
+		TotalDeposit:    sdk.NewInt(100),
+	}}
+	genState.SpStoragePriceList = []sptypes.SpStoragePrice{{
+		SpAddress:     operatorAddress,
+		UpdateTimeSec: 500,
+		ReadPrice:     sdk.NewDec(2),
+		StorePrice:    sdk.NewDec(3),
+	}}
+
+	// the storage provider created by a genesis transaction
+	msg, err := codectypes.NewAnyWithValue(&sptypes.MsgCreateStorageProvider{
+		SpAddress:      "0x3333333333333333333333333333333333333333",
+		FundingAddress: "0x4444444444444444444444444444444444444444",
+		Endpoint:       "https://gentx.example",
+		Deposit:        sdk.NewInt64Coin("BNB", 200),
+		ReadPrice:      sdk.NewDec(4),
+		StorePrice:     sdk.NewDec(5),
+	})
+	require.NoError(t, err)
+	genTx, err := cdc.MarshalJSON(&txtypes.Tx{Body: &txtypes.TxBody{Messages: []*codectypes.Any{msg}}, AuthInfo: &txtypes.AuthInfo{}})
+	require.NoError(t, err)
+
+	appState := map[string]json.RawMessage{
+		sptypes.ModuleName:    cdc.MustMarshalJSON(genState),
+		gensptypes.ModuleName: cdc.MustMarshalJSON(gensptypes.NewGenesisState([]json.RawMessage{genTx})),
+	}
+
+	gormDB, err := gorm.Open(sqlite.Open("file::memory:"), &gorm.Config{Logger: logger.Default.LogMode(logger.Silent)})
+	require.NoError(t, err)
+	require.NoError(t, gormDB.AutoMigrate(&models.StorageProvider{}, &models.StoragePrice{}))
+	db := &database.Impl{Db: gormDB}
+	module := NewModule(db, sink.NewSQLSink(db), cdc)
+
+	require.NoError(t, module.HandleGenesis(&tmtypes.GenesisDoc{GenesisTime: time.Unix(100, 0)}, appState))
+
+	ctx := context.Background()
+	sp, err := db.GetStorageProviderByFundingAddress(ctx, common.HexToAddress(fundingAddress))
+	require.NoError(t, err)
+	require.NotNil(t, sp)
+	require.Equal(t, common.HexToAddress(operatorAddress), sp.OperatorAddress)
+	require.Equal(t, sdk.NewDec(3).String(), sp.StorePrice)
+	require.Equal(t, int64(100), sp.CreateTime)
+
+	sp, err = db.GetStorageProviderByEndpoint(ctx, "https://gentx.example")
+	require.NoError(t, err)
+	require.NotNil(t, sp)
+	require.Equal(t, "BNB", sp.DepositDenom)
+	require.Equal(t, big.NewInt(200), sp.TotalDeposit.Raw())
+	require.Equal(t, sdk.NewDec(4).String(), sp.ReadPrice)
+
+	// the deposits to the genesis storage providers are indexed
+	deposit, err := sdk.TypedEventToEvent(&sptypes.EventDeposit{FundingAddress: fundingAddress, Deposit: "50BNB", TotalDeposit: "150BNB"})
+	require.NoError(t, err)
+	block := &tmctypes.ResultBlock{Block: &tmtypes.Block{Header: tmtypes.Header{Height: 1, Time: time.Unix(1000, 0)}}}
+	require.NoError(t, module.HandleEvent(ctx, block, common.HexToHash("0x01"), deposit))
+
+	sp, err = db.GetStorageProviderByFundingAddress(ctx, common.HexToAddress(fundingAddress))
+	require.NoError(t, err)
+	require.Equal(t, big.NewInt(150), sp.TotalDeposit.Raw())
+}
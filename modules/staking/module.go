@@ -0,0 +1,105 @@
+package staking
+
+import (
+	"context"
+	"sync"
+
+	codectypes "github.com/cosmos/cosmos-sdk/codec/types"
+	slashingtypes "github.com/cosmos/cosmos-sdk/x/slashing/types"
+	stakingtypes "github.com/cosmos/cosmos-sdk/x/staking/types"
+	"gorm.io/gorm/schema"
+
+	"github.com/forbole/juno/v4/database"
+	"github.com/forbole/juno/v4/models"
+	"github.com/forbole/juno/v4/modules"
+	"github.com/forbole/juno/v4/node/remote"
+	"github.com/forbole/juno/v4/sink"
+	"github.com/forbole/juno/v4/types/config"
+)
+
+const (
+	ModuleName = "staking"
+)
+
+var (
+	_ modules.Module              = &Module{}
+	_ modules.PrepareTablesModule = &Module{}
+	_ modules.RollbackModule      = &Module{}
+	_ modules.ReadBackModule      = &Module{}
+	_ modules.MessageModule       = &Module{}
+	_ modules.EventModule         = &Module{}
+	_ modules.BlockModule         = &Module{}
+	_ modules.FastSyncModule      = &Module{}
+)
+
+// Module represents the staking module, which keeps the validator tables up to date with the staking and
+// slashing state of the validators. Every row holds the latest known state, and its height is the one it was read at.
+// The state of the validators is queried through the gRPC endpoint of the node, so a remote node is required.
+type Module struct {
+	db       database.Database
+	sink     sink.Sink
+	unpacker codectypes.AnyUnpacker
+
+	grpcCfg        *remote.GRPCConfig
+	clientOnce     sync.Once
+	stakingClient  stakingtypes.QueryClient
+	slashingClient slashingtypes.QueryClient
+}
+
+// NewModule builds a new Module instance.
+// The unpacker is used to read the consensus public keys of the validators returned by the node.
+func NewModule(cfg config.Config, db database.Database, sink sink.Sink, unpacker codectypes.AnyUnpacker) *Module {
+	m := &Module{
+		db:       db,
+		sink:     sink,
+		unpacker: unpacker,
+	}
+	if details, ok := cfg.Node.Details.(*remote.Details); ok {
+		m.grpcCfg = details.GRPC
+	}
+	return m
+}
+
+// Name implements modules.Module
+func (m *Module) Name() string {
+	return ModuleName
+}
+
+// ReadsBack implements modules.ReadBackModule.
+// The voting powers are read back to detect the validators leaving the active set.
+func (m *Module) ReadsBack() bool {
+	return true
+}
+
+// tables returns the tables the module writes to
+func (m *Module) tables() []schema.Tabler {
+	return []schema.Tabler{
+		&models.ValidatorInfo{},
+		&models.ValidatorDescription{},
+		&models.ValidatorCommission{},
+		&models.ValidatorVotingPower{},
+		&models.ValidatorStatus{},
+		&models.ValidatorSigningInfo{},
+	}
+}
+
+// PrepareTables implements
+func (m *Module) PrepareTables() error {
+	return m.db.PrepareTables(context.TODO(), m.tables())
+}
+
+// RecreateTables implements
+func (m *Module) RecreateTables() error {
+	return m.db.RecreateTables(context.TODO(), m.tables())
+}
+
+// Rollback implements modules.RollbackModule.
+// The tables only hold the latest state of the validators, so nothing is deleted and a modules.StaleRowsError is
+// returned if any of them was updated after the height.
+func (m *Module) Rollback(ctx context.Context, height uint64) error {
+	var tables []modules.UpdatedAfter
+	for _, table := range m.tables() {
+		tables = append(tables, modules.UpdatedAfter{Table: table, Column: "height", Value: int64(height)})
+	}
+	return modules.CheckNotUpdated(ctx, m.db, m.Name(), tables...)
+}
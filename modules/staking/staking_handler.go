@@ -0,0 +1,524 @@
+package staking
+
+import (
+	"context"
+	"fmt"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/cosmos/cosmos-sdk/types/query"
+	slashingtypes "github.com/cosmos/cosmos-sdk/x/slashing/types"
+	stakingtypes "github.com/cosmos/cosmos-sdk/x/staking/types"
+	abci "github.com/tendermint/tendermint/abci/types"
+	tmctypes "github.com/tendermint/tendermint/rpc/core/types"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/forbole/juno/v4/common"
+	"github.com/forbole/juno/v4/log"
+	"github.com/forbole/juno/v4/models"
+	"github.com/forbole/juno/v4/node/remote"
+	"github.com/forbole/juno/v4/sink"
+	"github.com/forbole/juno/v4/types"
+)
+
+// stakingEvents are the staking events changing the state of the validators they carry
+var stakingEvents = map[string]bool{
+	stakingtypes.EventTypeCreateValidator:           true,
+	stakingtypes.EventTypeDelegate:                  true,
+	stakingtypes.EventTypeUnbond:                    true,
+	stakingtypes.EventTypeRedelegate:                true,
+	stakingtypes.EventTypeCancelUnbondingDelegation: true,
+}
+
+// HandleMsg implements modules.MessageModule.
+// Only the messages whose events do not carry the validator are handled here, the others being handled by HandleEvent.
+func (m *Module) HandleMsg(ctx context.Context, block *tmctypes.ResultBlock, _ int, msg sdk.Msg, tx *types.Tx) error {
+	if !tx.Successful() {
+		return nil
+	}
+
+	txHash := common.HexToHash(tx.TxHash)
+	switch msg := msg.(type) {
+	case *stakingtypes.MsgEditValidator:
+		_, err := m.refreshValidator(ctx, block.Block.Height, txHash, msg.ValidatorAddress)
+		return err
+	case *slashingtypes.MsgUnjail:
+		validatorAddress, err := m.refreshValidator(ctx, block.Block.Height, txHash, msg.ValidatorAddr)
+		if err != nil || validatorAddress == nil {
+			return err
+		}
+		return m.refreshSigningInfo(ctx, block.Block.Height, txHash, *validatorAddress)
+	}
+
+	return nil
+}
+
+// HandleEvent implements modules.EventModule
+func (m *Module) HandleEvent(ctx context.Context, block *tmctypes.ResultBlock, txHash common.Hash, event sdk.Event) error {
+	if !stakingEvents[event.Type] {
+		return nil
+	}
+
+	operators, err := eventValidators(abci.Event(event))
+	if err != nil {
+		return err
+	}
+	for _, operator := range operators {
+		_, err = m.refreshValidator(ctx, block.Block.Height, txHash, operator)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// HandleBlock implements modules.BlockModule.
+// The validators are created by the governance proposals executed by the end blocker, and slashed by the begin blocker,
+// so the events of both are handled here. The voting powers are read from the validator set, whose changes
+// also refresh the status of the validators entering or leaving it.
+func (m *Module) HandleBlock(
+	ctx context.Context, block *tmctypes.ResultBlock, results *tmctypes.ResultBlockResults, _ []*types.Tx, vals *tmctypes.ResultValidators,
+) error {
+	height := block.Block.Height
+
+	var operators []string
+	var slashed []common.Address
+	if results != nil {
+		for _, events := range [][]abci.Event{results.BeginBlockEvents, results.EndBlockEvents} {
+			for _, event := range events {
+				switch {
+				case stakingEvents[event.Type]:
+					eventOperators, err := eventValidators(event)
+					if err != nil {
+						return err
+					}
+					operators = append(operators, eventOperators...)
+				case event.Type == slashingtypes.EventTypeSlash || event.Type == slashingtypes.EventTypeLiveness:
+					validatorAddress, err := slashedValidator(event)
+					if err != nil {
+						return err
+					}
+					slashed = append(slashed, validatorAddress)
+				}
+			}
+		}
+	}
+
+	// the validator set is not provided by every caller
+	var changed []common.Address
+	if vals != nil {
+		var err error
+		changed, err = m.handleValidatorSet(ctx, height, vals)
+		if err != nil {
+			return err
+		}
+	}
+
+	var unknown []common.Address
+	for _, validatorAddress := range append(slashed, changed...) {
+		info, err := m.db.GetValidatorInfo(ctx, validatorAddress)
+		if err != nil {
+			return err
+		}
+		if info == nil {
+			unknown = append(unknown, validatorAddress)
+			continue
+		}
+		operators = append(operators, info.OperatorAddress.Hex())
+	}
+
+	// the genesis validators have no info stored until they are first refreshed
+	if len(unknown) > 0 {
+		found, err := m.findOperators(ctx, height, unknown)
+		if err != nil {
+			return err
+		}
+		operators = append(operators, found...)
+	}
+
+	refreshed := make(map[string]bool, len(operators))
+	for _, operator := range operators {
+		key := common.HexToAddress(operator).Hex()
+		if refreshed[key] {
+			continue
+		}
+		refreshed[key] = true
+
+		_, err := m.refreshValidator(ctx, height, common.Hash{}, operator)
+		if err != nil {
+			return err
+		}
+	}
+
+	for _, validatorAddress := range slashed {
+		err := m.refreshSigningInfo(ctx, height, common.Hash{}, validatorAddress)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// handleValidatorSet stores the voting powers of the given validator set that changed since the last block,
+// and zeroes the ones of the validators that left it.
+// It returns the consensus addresses of the validators entering or leaving the set.
+func (m *Module) handleValidatorSet(ctx context.Context, height int64, vals *tmctypes.ResultValidators) ([]common.Address, error) {
+	stored, err := m.db.GetActiveVotingPowers(ctx)
+	if err != nil {
+		return nil, err
+	}
+	previous := make(map[common.Address]uint64, len(stored))
+	for _, power := range stored {
+		previous[power.ValidatorAddress] = power.VotingPower
+	}
+
+	var changed []common.Address
+	var records []*sink.Record
+	current := make(map[common.Address]bool, len(vals.Validators))
+	for _, val := range vals.Validators {
+		validatorAddress := common.BytesToAddress(val.Address)
+		current[validatorAddress] = true
+
+		power, ok := previous[validatorAddress]
+		if ok && power == uint64(val.VotingPower) {
+			continue
+		}
+		if !ok {
+			changed = append(changed, validatorAddress)
+		}
+		records = append(records, sink.NewRecord(ModuleName, height, common.Hash{}, sink.OperationSave, &models.ValidatorVotingPower{
+			ValidatorAddress: validatorAddress,
+			VotingPower:      uint64(val.VotingPower),
+			Height:           uint64(height),
+		}))
+	}
+	for _, power := range stored {
+		if current[power.ValidatorAddress] {
+			continue
+		}
+		changed = append(changed, power.ValidatorAddress)
+		records = append(records, sink.NewRecord(ModuleName, height, common.Hash{}, sink.OperationSave, &models.ValidatorVotingPower{
+			ValidatorAddress: power.ValidatorAddress,
+			Height:           uint64(height),
+		}))
+	}
+
+	return changed, m.sink.Emit(ctx, records...)
+}
+
+// refreshValidator stores the state of the validator with the given operator address at the given height,
+// returning its consensus address. Validators not found were removed once fully unbonded, so they are skipped.
+func (m *Module) refreshValidator(ctx context.Context, height int64, txHash common.Hash, operator string) (*common.Address, error) {
+	client, err := m.stakingQueryClient()
+	if err != nil {
+		return nil, err
+	}
+
+	res, err := client.Validator(remote.GetHeightRequestContext(ctx, height), &stakingtypes.QueryValidatorRequest{ValidatorAddr: operator})
+	if status.Code(err) == codes.NotFound {
+		log.Warnw("validator not found", "module", m.Name(), "operator", operator, "height", height)
+		return nil, nil
+	}
+	if err != nil {
+		log.Errorw("query validator error", "module", m.Name(), "operator", operator, "height", height, "err", err)
+		return nil, err
+	}
+
+	validatorAddress, err := m.consensusAddress(&res.Validator)
+	if err != nil {
+		return nil, err
+	}
+	return &validatorAddress, m.sink.Emit(ctx, validatorRecords(validatorAddress, &res.Validator, height, txHash)...)
+}
+
+// findOperators returns the operator addresses of the validators having the given consensus addresses at the given
+// height, which are looked up among all the validators. Validators not found are skipped.
+func (m *Module) findOperators(ctx context.Context, height int64, validatorAddresses []common.Address) ([]string, error) {
+	client, err := m.stakingQueryClient()
+	if err != nil {
+		return nil, err
+	}
+
+	missing := make(map[common.Address]bool, len(validatorAddresses))
+	for _, validatorAddress := range validatorAddresses {
+		missing[validatorAddress] = true
+	}
+
+	var operators []string
+	var nextKey []byte
+	for len(missing) > 0 {
+		res, err := client.Validators(remote.GetHeightRequestContext(ctx, height), &stakingtypes.QueryValidatorsRequest{
+			Pagination: &query.PageRequest{Key: nextKey, Limit: 100},
+		})
+		if err != nil {
+			log.Errorw("query validators error", "module", m.Name(), "height", height, "err", err)
+			return nil, err
+		}
+
+		for i := range res.Validators {
+			validatorAddress, err := m.consensusAddress(&res.Validators[i])
+			if err != nil {
+				return nil, err
+			}
+			if missing[validatorAddress] {
+				operators = append(operators, res.Validators[i].OperatorAddress)
+				delete(missing, validatorAddress)
+			}
+		}
+
+		if res.Pagination == nil || len(res.Pagination.NextKey) == 0 {
+			break
+		}
+		nextKey = res.Pagination.NextKey
+	}
+
+	for validatorAddress := range missing {
+		log.Warnw("validator not found", "module", m.Name(), "validator", validatorAddress.Hex(), "height", height)
+	}
+	return operators, nil
+}
+
+// refreshSigningInfo stores the signing info of the validator with the given consensus address at the given height
+func (m *Module) refreshSigningInfo(ctx context.Context, height int64, txHash common.Hash, validatorAddress common.Address) error {
+	client, err := m.slashingQueryClient()
+	if err != nil {
+		return err
+	}
+
+	res, err := client.SigningInfo(remote.GetHeightRequestContext(ctx, height), &slashingtypes.QuerySigningInfoRequest{
+		ConsAddress: sdk.ConsAddress(validatorAddress.Bytes()).String(),
+	})
+	if status.Code(err) == codes.NotFound {
+		log.Warnw("signing info not found", "module", m.Name(), "validator", validatorAddress.Hex(), "height", height)
+		return nil
+	}
+	if err != nil {
+		log.Errorw("query signing info error", "module", m.Name(), "validator", validatorAddress.Hex(), "height", height, "err", err)
+		return err
+	}
+
+	return m.sink.Emit(ctx, sink.NewRecord(ModuleName, height, txHash, sink.OperationSave, newSigningInfo(&res.ValSigningInfo, height)))
+}
+
+// consensusAddress returns the consensus address of the given validator, derived from its consensus public key
+func (m *Module) consensusAddress(v *stakingtypes.Validator) (common.Address, error) {
+	err := v.UnpackInterfaces(m.unpacker)
+	if err != nil {
+		return common.Address{}, fmt.Errorf("invalid consensus pubkey of validator %s: %s", v.OperatorAddress, err)
+	}
+	consAddr, err := v.GetConsAddr()
+	if err != nil {
+		return common.Address{}, fmt.Errorf("invalid consensus pubkey of validator %s: %s", v.OperatorAddress, err)
+	}
+	return common.BytesToAddress(consAddr), nil
+}
+
+// stakingQueryClient returns the client used to query the validators
+func (m *Module) stakingQueryClient() (stakingtypes.QueryClient, error) {
+	err := m.connect()
+	if err != nil {
+		return nil, err
+	}
+	return m.stakingClient, nil
+}
+
+// slashingQueryClient returns the client used to query the signing infos
+func (m *Module) slashingQueryClient() (slashingtypes.QueryClient, error) {
+	err := m.connect()
+	if err != nil {
+		return nil, err
+	}
+	return m.slashingClient, nil
+}
+
+// connect builds the query clients, which are only built when first needed
+func (m *Module) connect() error {
+	if m.grpcCfg == nil {
+		return fmt.Errorf("the %s module requires a remote node", m.Name())
+	}
+
+	var err error
+	m.clientOnce.Do(func() {
+		conn, connErr := remote.CreateGrpcConnection(m.grpcCfg)
+		if connErr != nil {
+			err = fmt.Errorf("error while connecting to the gRPC endpoint: %s", connErr)
+			return
+		}
+		m.stakingClient = stakingtypes.NewQueryClient(conn)
+		m.slashingClient = slashingtypes.NewQueryClient(conn)
+	})
+	if err != nil {
+		return err
+	}
+	if m.stakingClient == nil {
+		return fmt.Errorf("the gRPC endpoint of the %s module is not available", m.Name())
+	}
+	return nil
+}
+
+// DownloadState implements modules.FastSyncModule.
+// The voting powers are computed from the tokens of the bonded validators, as the validator set is not available.
+func (m *Module) DownloadState(height int64) error {
+	stakingClient, err := m.stakingQueryClient()
+	if err != nil {
+		return err
+	}
+	slashingClient, err := m.slashingQueryClient()
+	if err != nil {
+		return err
+	}
+
+	ctx := context.Background()
+	var nextKey []byte
+	for {
+		res, err := stakingClient.Validators(remote.GetHeightRequestContext(ctx, height), &stakingtypes.QueryValidatorsRequest{
+			Pagination: &query.PageRequest{Key: nextKey, Limit: 100},
+		})
+		if err != nil {
+			log.Errorw("query validators error", "module", m.Name(), "height", height, "err", err)
+			return err
+		}
+
+		var records []*sink.Record
+		for i := range res.Validators {
+			v := &res.Validators[i]
+			validatorAddress, err := m.consensusAddress(v)
+			if err != nil {
+				return err
+			}
+			records = append(records, validatorRecords(validatorAddress, v, height, common.Hash{})...)
+			records = append(records, sink.NewRecord(ModuleName, height, common.Hash{}, sink.OperationSave, &models.ValidatorVotingPower{
+				ValidatorAddress: validatorAddress,
+				VotingPower:      uint64(v.GetConsensusPower(sdk.DefaultPowerReduction)),
+				Height:           uint64(height),
+			}))
+		}
+
+		err = m.sink.Emit(ctx, records...)
+		if err != nil {
+			return err
+		}
+
+		if res.Pagination == nil || len(res.Pagination.NextKey) == 0 {
+			break
+		}
+		nextKey = res.Pagination.NextKey
+	}
+
+	nextKey = nil
+	for {
+		res, err := slashingClient.SigningInfos(remote.GetHeightRequestContext(ctx, height), &slashingtypes.QuerySigningInfosRequest{
+			Pagination: &query.PageRequest{Key: nextKey, Limit: 100},
+		})
+		if err != nil {
+			log.Errorw("query signing infos error", "module", m.Name(), "height", height, "err", err)
+			return err
+		}
+
+		records := make([]*sink.Record, len(res.Info))
+		for i := range res.Info {
+			records[i] = sink.NewRecord(ModuleName, height, common.Hash{}, sink.OperationSave, newSigningInfo(&res.Info[i], height))
+		}
+
+		err = m.sink.Emit(ctx, records...)
+		if err != nil {
+			return err
+		}
+
+		if res.Pagination == nil || len(res.Pagination.NextKey) == 0 {
+			break
+		}
+		nextKey = res.Pagination.NextKey
+	}
+
+	log.Infow("downloaded state", "module", m.Name(), "height", height)
+	return nil
+}
+
+// eventValidators returns the operator addresses of the validators carried by the given staking event
+func eventValidators(event abci.Event) ([]string, error) {
+	keys := []string{stakingtypes.AttributeKeyValidator}
+	if event.Type == stakingtypes.EventTypeRedelegate {
+		keys = []string{stakingtypes.AttributeKeySrcValidator, stakingtypes.AttributeKeyDstValidator}
+	}
+
+	operators := make([]string, len(keys))
+	for i, key := range keys {
+		attr, err := types.FindAttributeByKey(event, key)
+		if err != nil {
+			return nil, err
+		}
+		operators[i] = string(attr.Value)
+	}
+	return operators, nil
+}
+
+// slashedValidator returns the consensus address of the validator carried by the given slashing event.
+// The events emitted when jailing a validator for double signing only carry its address as the jailed attribute.
+func slashedValidator(event abci.Event) (common.Address, error) {
+	attr, err := types.FindAttributeByKey(event, slashingtypes.AttributeKeyAddress)
+	if err != nil {
+		attr, err = types.FindAttributeByKey(event, slashingtypes.AttributeKeyJailed)
+		if err != nil {
+			return common.Address{}, err
+		}
+	}
+	return common.HexToAddress(string(attr.Value)), nil
+}
+
+// validatorRecords builds the records storing the info, description, commission and status of the given validator
+func validatorRecords(validatorAddress common.Address, v *stakingtypes.Validator, height int64, txHash common.Hash) []*sink.Record {
+	info := &models.ValidatorInfo{
+		ValidatorAddress:    validatorAddress,
+		OperatorAddress:     common.HexToAddress(v.OperatorAddress),
+		SelfDelegateAddress: common.HexToAddress(v.SelfDelAddress),
+		MaxChangeRate:       v.Commission.MaxChangeRate.String(),
+		MaxRate:             v.Commission.MaxRate.String(),
+		Height:              uint64(height),
+	}
+	description := &models.ValidatorDescription{
+		ValidatorAddress: validatorAddress,
+		Moniker:          v.Description.Moniker,
+		Identity:         v.Description.Identity,
+		Website:          v.Description.Website,
+		SecurityContact:  v.Description.SecurityContact,
+		Details:          v.Description.Details,
+		Height:           uint64(height),
+	}
+	commission := &models.ValidatorCommission{
+		ValidatorAddress:  validatorAddress,
+		Commission:        v.Commission.Rate.String(),
+		MinSelfDelegation: v.MinSelfDelegation.String(),
+		Height:            uint64(height),
+	}
+	validatorStatus := &models.ValidatorStatus{
+		ValidatorAddress: validatorAddress,
+		Status:           int(v.Status),
+		Jailed:           v.Jailed,
+		Height:           uint64(height),
+	}
+
+	return []*sink.Record{
+		sink.NewRecord(ModuleName, height, txHash, sink.OperationSave, info),
+		sink.NewRecord(ModuleName, height, txHash, sink.OperationSave, description),
+		sink.NewRecord(ModuleName, height, txHash, sink.OperationSave, commission),
+		sink.NewRecord(ModuleName, height, txHash, sink.OperationSave, validatorStatus),
+	}
+}
+
+// newSigningInfo builds the row describing the given signing info
+func newSigningInfo(info *slashingtypes.ValidatorSigningInfo, height int64) *models.ValidatorSigningInfo {
+	signingInfo := &models.ValidatorSigningInfo{
+		ValidatorAddress:    common.HexToAddress(info.Address),
+		StartHeight:         uint64(info.StartHeight),
+		IndexOffset:         uint64(info.IndexOffset),
+		Tombstoned:          info.Tombstoned,
+		MissedBlocksCounter: uint64(info.MissedBlocksCounter),
+		Height:              uint64(height),
+	}
+	// validators never jailed have the zero unix time
+	if info.JailedUntil.Unix() > 0 {
+		signingInfo.JailedUntil = uint64(info.JailedUntil.UTC().Unix())
+	}
+	return signingInfo
+}
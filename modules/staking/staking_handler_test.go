@@ -0,0 +1,181 @@
+package staking
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	codectypes "github.com/cosmos/cosmos-sdk/codec/types"
+	cryptocodec "github.com/cosmos/cosmos-sdk/crypto/codec"
+	"github.com/cosmos/cosmos-sdk/crypto/keys/ed25519"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/cosmos/cosmos-sdk/types/query"
+	slashingtypes "github.com/cosmos/cosmos-sdk/x/slashing/types"
+	stakingtypes "github.com/cosmos/cosmos-sdk/x/staking/types"
+	"github.com/stretchr/testify/require"
+	abci "github.com/tendermint/tendermint/abci/types"
+	tmctypes "github.com/tendermint/tendermint/rpc/core/types"
+	tmtypes "github.com/tendermint/tendermint/types"
+	"google.golang.org/grpc"
+
+	"github.com/forbole/juno/v4/common"
+	"github.com/forbole/juno/v4/database"
+	"github.com/forbole/juno/v4/models"
+	"github.com/forbole/juno/v4/node/remote"
+	"github.com/forbole/juno/v4/sink"
+)
+
+const (
+	srcOperator = "0x1111111111111111111111111111111111111111"
+	dstOperator = "0x2222222222222222222222222222222222222222"
+	consAddress = "0x3333333333333333333333333333333333333333"
+)
+
+func TestEventValidators(t *testing.T) {
+	operators, err := eventValidators(abci.Event{
+		Type: stakingtypes.EventTypeRedelegate,
+		Attributes: []abci.EventAttribute{
+			{Key: []byte(stakingtypes.AttributeKeySrcValidator), Value: []byte(srcOperator)},
+			{Key: []byte(stakingtypes.AttributeKeyDstValidator), Value: []byte(dstOperator)},
+		},
+	})
+	require.NoError(t, err)
+	require.Equal(t, []string{srcOperator, dstOperator}, operators)
+
+	operators, err = eventValidators(abci.Event{
+		Type:       stakingtypes.EventTypeDelegate,
+		Attributes: []abci.EventAttribute{{Key: []byte(stakingtypes.AttributeKeyValidator), Value: []byte(srcOperator)}},
+	})
+	require.NoError(t, err)
+	require.Equal(t, []string{srcOperator}, operators)
+
+	_, err = eventValidators(abci.Event{Type: stakingtypes.EventTypeUnbond})
+	require.Error(t, err)
+}
+
+func TestSlashedValidator(t *testing.T) {
+	validatorAddress, err := slashedValidator(abci.Event{
+		Type:       slashingtypes.EventTypeLiveness,
+		Attributes: []abci.EventAttribute{{Key: []byte(slashingtypes.AttributeKeyAddress), Value: []byte(consAddress)}},
+	})
+	require.NoError(t, err)
+	require.Equal(t, common.HexToAddress(consAddress), validatorAddress)
+
+	validatorAddress, err = slashedValidator(abci.Event{
+		Type:       slashingtypes.EventTypeSlash,
+		Attributes: []abci.EventAttribute{{Key: []byte(slashingtypes.AttributeKeyJailed), Value: []byte(consAddress)}},
+	})
+	require.NoError(t, err)
+	require.Equal(t, common.HexToAddress(consAddress), validatorAddress)
+}
+
+func TestNewSigningInfo(t *testing.T) {
+	info := &slashingtypes.ValidatorSigningInfo{
+		Address:             consAddress,
+		StartHeight:         10,
+		IndexOffset:         25,
+		JailedUntil:         time.Unix(0, 0),
+		MissedBlocksCounter: 3,
+	}
+	require.Equal(t, &models.ValidatorSigningInfo{
+		ValidatorAddress:    common.HexToAddress(consAddress),
+		StartHeight:         10,
+		IndexOffset:         25,
+		MissedBlocksCounter: 3,
+		Height:              40,
+	}, newSigningInfo(info, 40))
+
+	info.JailedUntil = time.Unix(5000, 0)
+	info.Tombstoned = true
+	signingInfo := newSigningInfo(info, 40)
+	require.Equal(t, uint64(5000), signingInfo.JailedUntil)
+	require.True(t, signingInfo.Tombstoned)
+}
+
+// stubDatabase stores no validator, any other method panicking
+type stubDatabase struct {
+	database.Database
+}
+
+func (stubDatabase) GetActiveVotingPowers(context.Context) ([]*models.ValidatorVotingPower, error) {
+	return nil, nil
+}
+
+func (stubDatabase) GetValidatorInfo(context.Context, common.Address) (*models.ValidatorInfo, error) {
+	return nil, nil
+}
+
+// recordingSink keeps the emitted records
+type recordingSink struct {
+	records []*sink.Record
+}
+
+func (s *recordingSink) Emit(_ context.Context, records ...*sink.Record) error {
+	s.records = append(s.records, records...)
+	return nil
+}
+
+func (s *recordingSink) Close() error {
+	return nil
+}
+
+// stubStakingClient serves the given validators, any other query panicking
+type stubStakingClient struct {
+	stakingtypes.QueryClient
+
+	validators []stakingtypes.Validator
+}
+
+func (c *stubStakingClient) Validators(context.Context, *stakingtypes.QueryValidatorsRequest, ...grpc.CallOption) (*stakingtypes.QueryValidatorsResponse, error) {
+	return &stakingtypes.QueryValidatorsResponse{Validators: c.validators, Pagination: &query.PageResponse{}}, nil
+}
+
+func (c *stubStakingClient) Validator(_ context.Context, req *stakingtypes.QueryValidatorRequest, _ ...grpc.CallOption) (*stakingtypes.QueryValidatorResponse, error) {
+	for _, v := range c.validators {
+		if common.HexToAddress(v.OperatorAddress) == common.HexToAddress(req.ValidatorAddr) {
+			return &stakingtypes.QueryValidatorResponse{Validator: v}, nil
+		}
+	}
+	return nil, errors.New("validator not found")
+}
+
+func TestHandleBlock_GenesisValidator(t *testing.T) {
+	registry := codectypes.NewInterfaceRegistry()
+	cryptocodec.RegisterInterfaces(registry)
+
+	pubKey := ed25519.GenPrivKey().PubKey()
+	consensusPubkey, err := codectypes.NewAnyWithValue(pubKey)
+	require.NoError(t, err)
+	validator := stakingtypes.Validator{
+		OperatorAddress:   srcOperator,
+		ConsensusPubkey:   consensusPubkey,
+		Status:            stakingtypes.Bonded,
+		Tokens:            sdk.NewInt(100),
+		DelegatorShares:   sdk.NewDec(100),
+		Description:       stakingtypes.Description{Moniker: "genesis"},
+		Commission:        stakingtypes.NewCommission(sdk.ZeroDec(), sdk.OneDec(), sdk.ZeroDec()),
+		MinSelfDelegation: sdk.OneInt(),
+	}
+
+	recorder := &recordingSink{}
+	m := &Module{db: stubDatabase{}, sink: recorder, unpacker: registry, grpcCfg: &remote.GRPCConfig{}}
+	m.clientOnce.Do(func() {
+		m.stakingClient = &stubStakingClient{validators: []stakingtypes.Validator{validator}}
+	})
+
+	// the genesis validators entering the set have no info stored yet
+	block := &tmctypes.ResultBlock{Block: &tmtypes.Block{Header: tmtypes.Header{Height: 1}}}
+	vals := &tmctypes.ResultValidators{Validators: []*tmtypes.Validator{{Address: pubKey.Address(), VotingPower: 100}}}
+	require.NoError(t, m.HandleBlock(context.Background(), block, nil, nil, vals))
+
+	var infos []*models.ValidatorInfo
+	for _, record := range recorder.records {
+		if info, ok := record.Data.(*models.ValidatorInfo); ok {
+			infos = append(infos, info)
+		}
+	}
+	require.Len(t, infos, 1)
+	require.Equal(t, common.BytesToAddress(pubKey.Address()), infos[0].ValidatorAddress)
+	require.Equal(t, common.HexToAddress(srcOperator), infos[0].OperatorAddress)
+}
@@ -0,0 +1,49 @@
+package validator
+
+import (
+	"fmt"
+
+	"gopkg.in/yaml.v3"
+)
+
+// DefaultUptimeWindow is the default number of heights the uptime of the validators is computed over
+const DefaultUptimeWindow = 10000
+
+// Config represents the configuration of the uptime aggregate, whose window is the number of heights
+// the blocks signed and missed by each validator are counted over
+type Config struct {
+	Window int64 `yaml:"window"`
+}
+
+// NewConfig allows to build a new Config instance
+func NewConfig(window int64) *Config {
+	return &Config{
+		Window: window,
+	}
+}
+
+// DefaultConfig returns the default Config instance
+func DefaultConfig() *Config {
+	return NewConfig(DefaultUptimeWindow)
+}
+
+// ParseConfig allows to parse a byte array as a Config instance.
+// If the uptime section is missing, the default configuration is returned.
+func ParseConfig(bz []byte) (*Config, error) {
+	type T struct {
+		Config *Config `yaml:"uptime"`
+	}
+	var cfg T
+	err := yaml.Unmarshal(bz, &cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	if cfg.Config == nil {
+		return DefaultConfig(), nil
+	}
+	if cfg.Config.Window <= 0 {
+		return nil, fmt.Errorf("invalid uptime window %d", cfg.Config.Window)
+	}
+	return cfg.Config, nil
+}
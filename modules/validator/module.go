@@ -6,30 +6,61 @@ import (
 	"gorm.io/gorm/schema"
 
 	"github.com/forbole/juno/v4/database"
+	"github.com/forbole/juno/v4/log"
 	"github.com/forbole/juno/v4/models"
 	"github.com/forbole/juno/v4/modules"
+	"github.com/forbole/juno/v4/sink"
+	"github.com/forbole/juno/v4/types/config"
+)
+
+const (
+	ModuleName = "validator"
 )
 
 var (
 	_ modules.Module              = &Module{}
 	_ modules.PrepareTablesModule = &Module{}
+	_ modules.RollbackModule      = &Module{}
+	_ modules.ReadBackModule      = &Module{}
+	_ modules.CommitModule        = &Module{}
 )
 
-// Module represents the basic module which is required by both explorer and storage-provider
+// Module represents the basic module which is required by both explorer and storage-provider.
+// It also maintains the uptime of the validators over the window given by the uptime configuration.
 type Module struct {
-	db database.Database
+	cfg  *Config
+	db   database.Database
+	sink sink.Sink
 }
 
 // NewModule builds a new Module instance
-func NewModule(db database.Database) *Module {
+func NewModule(cfg config.Config, db database.Database, sink sink.Sink) *Module {
+	bz, err := cfg.GetBytes()
+	if err != nil {
+		panic(err)
+	}
+
+	uptimeCfg, err := ParseConfig(bz)
+	if err != nil {
+		panic(err)
+	}
+
 	return &Module{
-		db: db,
+		cfg:  uptimeCfg,
+		db:   db,
+		sink: sink,
 	}
 }
 
 // Name implements modules.Module
 func (m *Module) Name() string {
-	return "validator"
+	return ModuleName
+}
+
+// ReadsBack implements modules.ReadBackModule.
+// The uptimes are read back to move the rolling window of each validator forward.
+func (m *Module) ReadsBack() bool {
+	return true
 }
 
 // PrepareTables implements
@@ -41,7 +72,8 @@ func (m *Module) PrepareTables() error {
 		&models.ValidatorCommission{},
 		&models.ValidatorVotingPower{},
 		&models.ValidatorStatus{},
-		&models.ValidatorSigningInfo{}})
+		&models.ValidatorSigningInfo{},
+		&models.ValidatorUptime{}})
 }
 
 // RecreateTables implements
@@ -53,5 +85,20 @@ func (m *Module) RecreateTables() error {
 		&models.ValidatorCommission{},
 		&models.ValidatorVotingPower{},
 		&models.ValidatorStatus{},
-		&models.ValidatorSigningInfo{}})
+		&models.ValidatorSigningInfo{},
+		&models.ValidatorUptime{}})
+}
+
+// Rollback implements modules.RollbackModule.
+// The uptimes only hold the latest state of their rolling window, which cannot be rewound, so the rows updated after
+// the height are deleted. Since the rows of the whole validator set are updated at every height, this usually resets
+// the windows of all the validators, which start over from the first reprocessed height.
+func (m *Module) Rollback(ctx context.Context, height uint64) error {
+	deleted, err := m.db.DeleteAfter(ctx, &models.ValidatorUptime{}, "height", int64(height))
+	if err != nil {
+		return err
+	}
+
+	log.Infow("rolled back", "module", m.Name(), "height", height, "deleted", deleted)
+	return nil
 }
@@ -0,0 +1,91 @@
+package validator
+
+import (
+	"context"
+
+	tmctypes "github.com/tendermint/tendermint/rpc/core/types"
+
+	"github.com/forbole/juno/v4/common"
+	"github.com/forbole/juno/v4/models"
+	"github.com/forbole/juno/v4/sink"
+)
+
+// HandleCommit implements modules.CommitModule.
+// Each validator of the set records whether it signed the committed height, which is only recorded once, so that
+// reprocessing a height does not count it again.
+func (m *Module) HandleCommit(ctx context.Context, block *tmctypes.ResultBlock, vals *tmctypes.ResultValidators) error {
+	commit := block.Block.LastCommit
+	if commit == nil {
+		return nil
+	}
+
+	signed := make(map[common.Address]bool, len(commit.Signatures))
+	for _, commitSig := range commit.Signatures {
+		if !commitSig.Absent() {
+			signed[common.BytesToAddress(commitSig.ValidatorAddress)] = true
+		}
+	}
+
+	validatorAddresses := make([]common.Address, len(vals.Validators))
+	for i, val := range vals.Validators {
+		validatorAddresses[i] = common.BytesToAddress(val.Address)
+	}
+	stored, err := m.db.GetValidatorUptimes(ctx, validatorAddresses)
+	if err != nil {
+		return err
+	}
+	uptimes := make(map[common.Address]*models.ValidatorUptime, len(stored))
+	for _, uptime := range stored {
+		uptimes[uptime.ValidatorAddress] = uptime
+	}
+
+	records := make([]*sink.Record, 0, len(validatorAddresses))
+	for _, validatorAddress := range validatorAddresses {
+		uptime, ok := uptimes[validatorAddress]
+		if !ok {
+			uptime = &models.ValidatorUptime{ValidatorAddress: validatorAddress}
+		}
+		if uptime.Height >= commit.Height {
+			continue
+		}
+
+		recordCommit(uptime, m.cfg.Window, !signed[validatorAddress])
+		// the rows are upserted by validator address, so new and existing rows can be written together
+		uptime.ID = 0
+		uptime.Height = commit.Height
+		records = append(records, sink.NewRecord(ModuleName, commit.Height, common.Hash{}, sink.OperationSave, uptime))
+	}
+
+	return m.sink.Emit(ctx, records...)
+}
+
+// recordCommit records whether the validator missed the next height of its rolling window, evicting the oldest
+// height once the window is full. The window starts over when its size changes.
+func recordCommit(uptime *models.ValidatorUptime, window int64, missed bool) {
+	if uptime.Window != window || int64(len(uptime.MissedBlocksBitmap)) != (window+7)/8 {
+		uptime.Window = window
+		uptime.IndexOffset = 0
+		uptime.SignedBlocks = 0
+		uptime.MissedBlocks = 0
+		uptime.MissedBlocksBitmap = make([]byte, (window+7)/8)
+	}
+
+	index := uptime.IndexOffset % window
+	bit := byte(1) << (index % 8)
+	if uptime.IndexOffset >= window {
+		if uptime.MissedBlocksBitmap[index/8]&bit != 0 {
+			uptime.MissedBlocks--
+		} else {
+			uptime.SignedBlocks--
+		}
+	}
+
+	if missed {
+		uptime.MissedBlocksBitmap[index/8] |= bit
+		uptime.MissedBlocks++
+	} else {
+		uptime.MissedBlocksBitmap[index/8] &^= bit
+		uptime.SignedBlocks++
+	}
+	uptime.IndexOffset++
+}
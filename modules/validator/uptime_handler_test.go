@@ -0,0 +1,121 @@
+package validator
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	tmctypes "github.com/tendermint/tendermint/rpc/core/types"
+	tmtypes "github.com/tendermint/tendermint/types"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+
+	"github.com/forbole/juno/v4/common"
+	"github.com/forbole/juno/v4/database"
+	"github.com/forbole/juno/v4/models"
+	"github.com/forbole/juno/v4/sink"
+)
+
+func TestRecordCommit(t *testing.T) {
+	uptime := &models.ValidatorUptime{}
+
+	// fill a window of 3 heights: signed, missed, signed
+	recordCommit(uptime, 3, false)
+	recordCommit(uptime, 3, true)
+	recordCommit(uptime, 3, false)
+	require.Equal(t, int64(2), uptime.SignedBlocks)
+	require.Equal(t, int64(1), uptime.MissedBlocks)
+	require.InDelta(t, 2.0/3.0, uptime.Uptime(), 1e-9)
+
+	// the oldest height, which was signed, leaves the window
+	recordCommit(uptime, 3, true)
+	require.Equal(t, int64(1), uptime.SignedBlocks)
+	require.Equal(t, int64(2), uptime.MissedBlocks)
+
+	// the missed height leaves the window
+	recordCommit(uptime, 3, false)
+	require.Equal(t, int64(2), uptime.SignedBlocks)
+	require.Equal(t, int64(1), uptime.MissedBlocks)
+	require.Equal(t, int64(5), uptime.IndexOffset)
+
+	// a new window size starts over
+	recordCommit(uptime, 10, true)
+	require.Equal(t, int64(10), uptime.Window)
+	require.Equal(t, int64(0), uptime.SignedBlocks)
+	require.Equal(t, int64(1), uptime.MissedBlocks)
+	require.Equal(t, int64(1), uptime.IndexOffset)
+	require.Len(t, uptime.MissedBlocksBitmap, 2)
+}
+
+func TestParseConfig(t *testing.T) {
+	cfg, err := ParseConfig([]byte(`
+uptime:
+  window: 100
+`))
+	require.NoError(t, err)
+	require.Equal(t, int64(100), cfg.Window)
+
+	cfg, err = ParseConfig([]byte(`invalid_field: yes`))
+	require.NoError(t, err)
+	require.Equal(t, DefaultConfig(), cfg)
+
+	_, err = ParseConfig([]byte(`
+uptime:
+  window: 0
+`))
+	require.Error(t, err)
+}
+
+func TestRollback_Uptimes(t *testing.T) {
+	gormDB, err := gorm.Open(sqlite.Open("file::memory:"), &gorm.Config{Logger: logger.Default.LogMode(logger.Silent)})
+	require.NoError(t, err)
+	require.NoError(t, gormDB.AutoMigrate(&models.ValidatorUptime{}))
+
+	db := &database.Impl{Db: gormDB}
+	m := &Module{cfg: &Config{Window: 10}, db: db, sink: sink.NewSQLSink(db)}
+	ctx := context.Background()
+
+	validatorAddress := common.HexToAddress("0x01")
+	vals := &tmctypes.ResultValidators{Validators: []*tmtypes.Validator{{Address: validatorAddress.Bytes()}}}
+	commit := func(height int64, signed bool) {
+		flag := tmtypes.BlockIDFlagAbsent
+		if signed {
+			flag = tmtypes.BlockIDFlagCommit
+		}
+		block := &tmctypes.ResultBlock{Block: &tmtypes.Block{LastCommit: &tmtypes.Commit{
+			Height:     height,
+			Signatures: []tmtypes.CommitSig{{BlockIDFlag: flag, ValidatorAddress: validatorAddress.Bytes()}},
+		}}}
+		require.NoError(t, m.HandleCommit(ctx, block, vals))
+	}
+	uptime := func() *models.ValidatorUptime {
+		uptimes, err := db.GetValidatorUptimes(ctx, []common.Address{validatorAddress})
+		require.NoError(t, err)
+		if len(uptimes) == 0 {
+			return nil
+		}
+		return uptimes[0]
+	}
+
+	commit(1, true)
+	commit(2, false)
+	require.Equal(t, int64(2), uptime().Height)
+
+	// the forked height is forgotten, so that the reprocessed one is recorded
+	require.NoError(t, m.Rollback(ctx, 1))
+	require.Nil(t, uptime())
+
+	commit(2, true)
+	require.Equal(t, int64(2), uptime().Height)
+	require.Equal(t, int64(1), uptime().SignedBlocks)
+	require.Equal(t, int64(0), uptime().MissedBlocks)
+
+	// the heights already recorded are never counted again
+	commit(2, true)
+	require.Equal(t, int64(1), uptime().SignedBlocks)
+
+	// rolling back to the recorded height keeps the uptimes
+	require.NoError(t, m.Rollback(ctx, 2))
+	require.Equal(t, int64(2), uptime().Height)
+}
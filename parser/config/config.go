@@ -12,6 +12,7 @@ type Config struct {
 	ParseGenesis    bool           `yaml:"parse_genesis"`
 	FastSync        bool           `yaml:"fast_sync,omitempty"`
 	ConcurrentSync  bool           `yaml:"concurrent_sync,omitempty"`
+	Retry           RetryConfig    `yaml:"retry,omitempty"`
 }
 
 // NewParsingConfig allows to build a new Config instance
@@ -22,6 +23,7 @@ func NewParsingConfig(
 	startHeight uint64, fastSync bool,
 	avgBlockTime *time.Duration,
 	concurrentSync bool,
+	retry RetryConfig,
 ) Config {
 	return Config{
 		Workers:         workers,
@@ -33,6 +35,7 @@ func NewParsingConfig(
 		FastSync:        fastSync,
 		AvgBlockTime:    avgBlockTime,
 		ConcurrentSync:  concurrentSync,
+		Retry:           retry,
 	}
 }
 
@@ -49,5 +52,72 @@ func DefaultParsingConfig() Config {
 		false,
 		&avgBlockTime,
 		false,
+		DefaultRetryConfig(),
 	)
 }
+
+// ---------------------------------------------------------------------------------------------------------------------
+
+const (
+	DefaultRetryBaseDelay   = 3 * time.Second
+	DefaultRetryMultiplier  = 2
+	DefaultRetryMaxDelay    = 5 * time.Minute
+	DefaultRetryMaxAttempts = 10
+)
+
+// RetryConfig contains the policy used to retry the heights that failed to be processed.
+// Any field left empty falls back to its default value.
+type RetryConfig struct {
+	BaseDelay   time.Duration `yaml:"base_delay,omitempty"`
+	Multiplier  float64       `yaml:"multiplier,omitempty"`
+	MaxDelay    time.Duration `yaml:"max_delay,omitempty"`
+	MaxAttempts uint          `yaml:"max_attempts,omitempty"`
+}
+
+// NewRetryConfig allows to build a new RetryConfig instance
+func NewRetryConfig(baseDelay time.Duration, multiplier float64, maxDelay time.Duration, maxAttempts uint) RetryConfig {
+	return RetryConfig{
+		BaseDelay:   baseDelay,
+		Multiplier:  multiplier,
+		MaxDelay:    maxDelay,
+		MaxAttempts: maxAttempts,
+	}
+}
+
+// DefaultRetryConfig returns the default instance of RetryConfig
+func DefaultRetryConfig() RetryConfig {
+	return NewRetryConfig(DefaultRetryBaseDelay, DefaultRetryMultiplier, DefaultRetryMaxDelay, DefaultRetryMaxAttempts)
+}
+
+// GetMaxAttempts returns the max number of times a height should be processed before giving up on it
+func (cfg RetryConfig) GetMaxAttempts() uint {
+	if cfg.MaxAttempts == 0 {
+		return DefaultRetryMaxAttempts
+	}
+	return cfg.MaxAttempts
+}
+
+// Delay returns the time to wait before the given retry attempt, starting from 1.
+// The delay grows exponentially from the base delay, and never exceeds the max delay.
+func (cfg RetryConfig) Delay(attempt uint) time.Duration {
+	baseDelay, multiplier, maxDelay := cfg.BaseDelay, cfg.Multiplier, cfg.MaxDelay
+	if baseDelay <= 0 {
+		baseDelay = DefaultRetryBaseDelay
+	}
+	if multiplier < 1 {
+		multiplier = DefaultRetryMultiplier
+	}
+	if maxDelay <= 0 {
+		maxDelay = DefaultRetryMaxDelay
+	}
+
+	delay := float64(baseDelay)
+	for i := uint(1); i < attempt && delay < float64(maxDelay); i++ {
+		delay *= multiplier
+	}
+
+	if delay > float64(maxDelay) {
+		return maxDelay
+	}
+	return time.Duration(delay)
+}
@@ -0,0 +1,25 @@
+package config_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/forbole/juno/v4/parser/config"
+)
+
+func TestRetryConfig_Delay(t *testing.T) {
+	cfg := config.NewRetryConfig(time.Second, 2, 10*time.Second, 5)
+	require.Equal(t, time.Second, cfg.Delay(1))
+	require.Equal(t, 2*time.Second, cfg.Delay(2))
+	require.Equal(t, 8*time.Second, cfg.Delay(4))
+	require.Equal(t, 10*time.Second, cfg.Delay(5))
+	require.Equal(t, 10*time.Second, cfg.Delay(100))
+	require.Equal(t, uint(5), cfg.GetMaxAttempts())
+
+	cfg = config.RetryConfig{}
+	require.Equal(t, config.DefaultRetryBaseDelay, cfg.Delay(1))
+	require.Equal(t, config.DefaultRetryMaxDelay, cfg.Delay(100))
+	require.Equal(t, uint(config.DefaultRetryMaxAttempts), cfg.GetMaxAttempts())
+}
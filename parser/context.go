@@ -6,6 +6,7 @@ import (
 	"github.com/forbole/juno/v4/database"
 	"github.com/forbole/juno/v4/modules"
 	"github.com/forbole/juno/v4/node"
+	"github.com/forbole/juno/v4/sink"
 )
 
 // Context represents the context that is shared among different workers
@@ -13,6 +14,7 @@ type Context struct {
 	EncodingConfig *params.EncodingConfig
 	Node           node.Node
 	Database       database.Database
+	Sink           sink.Sink
 	Indexer        Indexer
 	Modules        []modules.Module
 }
@@ -22,6 +24,7 @@ func NewContext(
 	encodingConfig *params.EncodingConfig,
 	proxy node.Node,
 	db database.Database,
+	sink sink.Sink,
 	modules []modules.Module,
 	indexer Indexer,
 ) *Context {
@@ -29,6 +32,7 @@ func NewContext(
 		EncodingConfig: encodingConfig,
 		Node:           proxy,
 		Database:       db,
+		Sink:           sink,
 		Indexer:        indexer,
 		Modules:        modules,
 	}
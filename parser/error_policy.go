@@ -0,0 +1,205 @@
+package parser
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+
+	"github.com/forbole/juno/v4/common"
+	"github.com/forbole/juno/v4/database"
+	"github.com/forbole/juno/v4/log"
+	"github.com/forbole/juno/v4/models"
+	"github.com/forbole/juno/v4/modules"
+	"github.com/forbole/juno/v4/sink"
+	"github.com/forbole/juno/v4/types/config"
+)
+
+const (
+	// handlerSavePoint is the name of the savepoint used to discard the changes of a failed handler
+	handlerSavePoint = "juno_handler"
+
+	// handlerRetryAttempts is the number of times a handler is called when using config.ErrorPolicyRetry
+	handlerRetryAttempts = 3
+)
+
+// handleWithPolicy calls the given handler of module, applying the error policy configured for the module if it fails.
+// The changes made by a failed handler are discarded unless the policy is config.ErrorPolicyHalt, in which case
+// the whole height fails anyway. The event is the one being handled, if any, and is used to quarantine it.
+// The handler is called with the database transaction carried by ctx. When ctx carries none, as when a single part of
+// a height is processed again, the handler is called within a dedicated transaction, so that its changes can still be
+// discarded using a savepoint of the same connection.
+// An error is returned only if the processing of the height should fail.
+func (i *Impl) handleWithPolicy(
+	ctx context.Context, module modules.Module, height int64, txHash common.Hash, event *sdk.Event,
+	handler func(ctx context.Context) error,
+) error {
+	policy := config.Cfg.Chain.GetErrorPolicy(module.Name())
+	if policy == config.ErrorPolicyHalt || (policy == config.ErrorPolicyQuarantine && event == nil) {
+		return handler(ctx)
+	}
+
+	if _, ok := database.TxFromContext(ctx); !ok {
+		return i.handleWithinTx(ctx, module, height, txHash, event, handler)
+	}
+
+	err := i.DB.SavePoint(ctx, handlerSavePoint)
+	if err != nil {
+		return fmt.Errorf("failed to set savepoint: %s", err)
+	}
+
+	// the records held for the sinks not backed by the database are discarded along with the database changes
+	buffer, _ := sink.BufferFromContext(ctx)
+	emitted := buffer.Len()
+
+	err = handler(ctx)
+	if err == nil {
+		return nil
+	}
+
+	if policy == config.ErrorPolicyRetry {
+		for attempt := uint(1); attempt < handlerRetryAttempts && err != nil; attempt++ {
+			delay := config.Cfg.Parser.Retry.Delay(attempt)
+			log.Errorw("error while handling, retrying", "module", module.Name(), "height", height,
+				"attempt", attempt, "delay", delay, "err", err)
+
+			rollbackErr := i.DB.RollbackTo(ctx, handlerSavePoint)
+			if rollbackErr != nil {
+				return fmt.Errorf("failed to rollback to savepoint: %s", rollbackErr)
+			}
+			buffer.Truncate(emitted)
+
+			time.Sleep(delay)
+			err = handler(ctx)
+		}
+		return err
+	}
+
+	rollbackErr := i.DB.RollbackTo(ctx, handlerSavePoint)
+	if rollbackErr != nil {
+		return fmt.Errorf("failed to rollback to savepoint: %s", rollbackErr)
+	}
+	buffer.Truncate(emitted)
+
+	if policy == config.ErrorPolicyQuarantine {
+		quarantineErr := i.quarantine(ctx, module, height, txHash, event, err)
+		if quarantineErr != nil {
+			return fmt.Errorf("failed to quarantine event %s: %s", event.Type, quarantineErr)
+		}
+		return nil
+	}
+
+	log.Errorw("error while handling, skipping", "module", module.Name(), "height", height, "err", err)
+	return nil
+}
+
+// handleWithinTx calls handleWithPolicy within a dedicated database transaction, which is committed unless
+// the processing of the height should fail
+func (i *Impl) handleWithinTx(
+	ctx context.Context, module modules.Module, height int64, txHash common.Hash, event *sdk.Event,
+	handler func(ctx context.Context) error,
+) error {
+	dbTx := i.DB.Begin(ctx)
+	if dbTx.Db.Error != nil {
+		return fmt.Errorf("failed to begin database transaction: %s", dbTx.Db.Error)
+	}
+
+	committed := false
+	defer func() {
+		if !committed {
+			dbTx.Rollback()
+		}
+	}()
+
+	buffer := sink.NewBuffer()
+	err := i.handleWithPolicy(sink.WithBuffer(database.WithTx(ctx, dbTx), buffer), module, height, txHash, event, handler)
+	if err != nil {
+		return err
+	}
+
+	committed = true
+	err = dbTx.Commit()
+	if err != nil {
+		return err
+	}
+
+	flushRecords(ctx, buffer, height)
+	return nil
+}
+
+// quarantine stores the given event, which module failed to handle with the given error, so that it can be replayed later
+func (i *Impl) quarantine(
+	ctx context.Context, module modules.Module, height int64, txHash common.Hash, event *sdk.Event, err error,
+) error {
+	bz, marshalErr := json.Marshal(event)
+	if marshalErr != nil {
+		return marshalErr
+	}
+
+	log.Errorw("quarantining event", "module", module.Name(), "height", height, "tx_hash", txHash,
+		"event", event.Type, "err", err)
+
+	return i.DB.SaveQuarantinedEvent(ctx, &models.QuarantinedEvent{
+		Module:     module.Name(),
+		Height:     height,
+		TxHash:     txHash,
+		EventType:  event.Type,
+		Event:      string(bz),
+		Attempts:   1,
+		LastError:  err.Error(),
+		UpdateTime: time.Now().UTC().Unix(),
+	})
+}
+
+// ReplayEvent handles again the given quarantined event using the given module, removing it from the quarantined events
+// if it succeeds. The block the event belongs to is fetched from the node, and all the changes are stored within
+// a single database transaction.
+func ReplayEvent(ctx *Context, module modules.EventModule, quarantined *models.QuarantinedEvent) error {
+	if replayModule, ok := module.(modules.ReplayModule); ok && !replayModule.CanReplay() {
+		return fmt.Errorf("the events of the %s module cannot be replayed, reprocess the module from height %d "+
+			"using the parse modules command instead", quarantined.Module, quarantined.Height)
+	}
+
+	var event sdk.Event
+	err := json.Unmarshal([]byte(quarantined.Event), &event)
+	if err != nil {
+		return fmt.Errorf("failed to decode event: %s", err)
+	}
+
+	block, err := ctx.Node.Block(quarantined.Height)
+	if err != nil {
+		return fmt.Errorf("failed to get block from node: %s", err)
+	}
+
+	dbTx := ctx.Database.Begin(context.Background())
+	if dbTx.Db.Error != nil {
+		return fmt.Errorf("failed to begin database transaction: %s", dbTx.Db.Error)
+	}
+	buffer := sink.NewBuffer()
+	txCtx := sink.WithBuffer(database.WithTx(context.Background(), dbTx), buffer)
+
+	err = module.HandleEvent(txCtx, block, quarantined.TxHash, event)
+	if err != nil {
+		dbTx.Rollback()
+		return err
+	}
+
+	err = ctx.Database.DeleteQuarantinedEvent(txCtx, quarantined.ID)
+	if err != nil {
+		dbTx.Rollback()
+		return fmt.Errorf("failed to delete quarantined event: %s", err)
+	}
+
+	err = dbTx.Commit()
+	if err != nil {
+		return err
+	}
+
+	err = buffer.Flush(context.Background())
+	if err != nil {
+		return fmt.Errorf("failed to deliver the records of the replayed event: %s", err)
+	}
+	return nil
+}
@@ -0,0 +1,113 @@
+package parser
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/stretchr/testify/require"
+	tmctypes "github.com/tendermint/tendermint/rpc/core/types"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+
+	"github.com/forbole/juno/v4/common"
+	"github.com/forbole/juno/v4/database"
+	"github.com/forbole/juno/v4/models"
+	"github.com/forbole/juno/v4/sink"
+	"github.com/forbole/juno/v4/types/config"
+)
+
+// testModule is a module doing nothing but having a name
+type testModule struct{}
+
+func (testModule) Name() string {
+	return "test"
+}
+
+// recordingSink counts the delivered records
+type recordingSink struct {
+	delivered int
+}
+
+func (s *recordingSink) Emit(_ context.Context, records ...*sink.Record) error {
+	s.delivered += len(records)
+	return nil
+}
+
+func (s *recordingSink) Close() error {
+	return nil
+}
+
+// noReplayModule is an event module whose events cannot be replayed
+type noReplayModule struct {
+	testModule
+}
+
+func (noReplayModule) HandleEvent(context.Context, *tmctypes.ResultBlock, common.Hash, sdk.Event) error {
+	return nil
+}
+
+func (noReplayModule) CanReplay() bool {
+	return false
+}
+
+func TestReplayEvent_CannotReplay(t *testing.T) {
+	err := ReplayEvent(&Context{}, noReplayModule{}, &models.QuarantinedEvent{Module: "test", Height: 5})
+	require.ErrorContains(t, err, "cannot be replayed")
+}
+
+func TestHandleWithPolicy_NoTx(t *testing.T) {
+	testCases := []struct {
+		name        string
+		policy      config.ErrorPolicy
+		handlerErr  error
+		blocks      int
+		quarantined int
+	}{
+		{name: "successful handler", policy: config.ErrorPolicyLog, blocks: 1},
+		{name: "failed handler logged", policy: config.ErrorPolicyLog, handlerErr: errors.New("failed")},
+		{name: "failed handler quarantined", policy: config.ErrorPolicyQuarantine, handlerErr: errors.New("failed"), quarantined: 1},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			gormDB, err := gorm.Open(sqlite.Open("file::memory:"), &gorm.Config{Logger: logger.Default.LogMode(logger.Silent)})
+			require.NoError(t, err)
+			sqlDB, err := gormDB.DB()
+			require.NoError(t, err)
+			// every connection to an in-memory database opens a new one
+			sqlDB.SetMaxOpenConns(1)
+			require.NoError(t, gormDB.AutoMigrate(&models.Block{}, &models.QuarantinedEvent{}))
+
+			config.Cfg.Chain.OnError = map[string]config.ErrorPolicy{"test": tc.policy}
+			defer func() { config.Cfg.Chain.OnError = nil }()
+
+			recorder := &recordingSink{}
+			moduleSink := sink.NewBufferedSink(recorder)
+
+			indexer := &Impl{DB: &database.Impl{Db: gormDB}}
+			err = indexer.handleWithPolicy(context.Background(), testModule{}, 1, common.Hash{}, &sdk.Event{Type: "test"},
+				func(ctx context.Context) error {
+					tx, ok := database.TxFromContext(ctx)
+					require.True(t, ok)
+					require.NoError(t, tx.Db.Create(&models.Block{Header: models.Header{Height: 1}}).Error)
+					require.NoError(t, moduleSink.Emit(ctx, sink.NewRecord("test", 1, common.Hash{}, sink.OperationSave, &models.Block{})))
+					require.Zero(t, recorder.delivered)
+					return tc.handlerErr
+				},
+			)
+			require.NoError(t, err)
+
+			var blocks, quarantined int64
+			require.NoError(t, gormDB.Model(&models.Block{}).Count(&blocks).Error)
+			require.NoError(t, gormDB.Model(&models.QuarantinedEvent{}).Count(&quarantined).Error)
+			require.Equal(t, int64(tc.blocks), blocks)
+			require.Equal(t, int64(tc.quarantined), quarantined)
+
+			// the records are only delivered along with the committed changes
+			require.Equal(t, tc.blocks, recorder.delivered)
+		})
+	}
+}
@@ -0,0 +1,92 @@
+package parser
+
+import (
+	"context"
+	"fmt"
+
+	tmctypes "github.com/tendermint/tendermint/rpc/core/types"
+
+	"github.com/forbole/juno/v4/common"
+	"github.com/forbole/juno/v4/log"
+)
+
+// ForkError is returned when the parent hash of a block does not match the hash stored for the previous height,
+// meaning that the indexed data diverges from the chain the node is following.
+type ForkError struct {
+	// Height is the height of the block whose parent hash does not match
+	Height uint64
+	// StoredHash is the hash stored inside the database for Height - 1
+	StoredHash common.Hash
+	// ParentHash is the hash of the parent of Height reported by the node
+	ParentHash common.Hash
+	// ForkHeight is the last height whose stored hash matches the node one
+	ForkHeight uint64
+}
+
+func (e *ForkError) Error() string {
+	return fmt.Sprintf("chain fork detected at height %d: stored hash of height %d is %s, but the node reports %s; "+
+		"last common height is %d, run `juno rollback --to-height %d` and restart the parser",
+		e.Height, e.Height-1, e.StoredHash.Hex(), e.ParentHash.Hex(), e.ForkHeight, e.ForkHeight)
+}
+
+// checkParentHash verifies that the parent hash of the given block matches the hash stored for the previous height.
+// If the previous height has not been stored yet, no check is performed.
+func (i *Impl) checkParentHash(ctx context.Context, block *tmctypes.ResultBlock) error {
+	height := uint64(block.Block.Height)
+	if height <= 1 {
+		return nil
+	}
+
+	parent, err := i.DB.GetBlock(ctx, height-1)
+	if err != nil {
+		return fmt.Errorf("failed to get parent block from database: %s", err)
+	}
+	if parent == nil {
+		return nil
+	}
+
+	parentHash := common.HexToHash(block.Block.LastBlockID.Hash.String())
+	if parent.Hash == parentHash {
+		return nil
+	}
+
+	forkHeight, err := i.findForkHeight(ctx, height-2)
+	if err != nil {
+		return err
+	}
+
+	log.Errorw("chain fork detected", "height", height, "stored_parent_hash", parent.Hash.Hex(),
+		"parent_hash", parentHash.Hex(), "fork_height", forkHeight)
+
+	return &ForkError{
+		Height:     height,
+		StoredHash: parent.Hash,
+		ParentHash: parentHash,
+		ForkHeight: forkHeight,
+	}
+}
+
+// findForkHeight walks back from the given height and returns the first height whose stored hash
+// matches the one reported by the node.
+func (i *Impl) findForkHeight(ctx context.Context, from uint64) (uint64, error) {
+	for height := from; height > 0; height-- {
+		stored, err := i.DB.GetBlock(ctx, height)
+		if err != nil {
+			return 0, fmt.Errorf("failed to get block %d from database: %s", height, err)
+		}
+		if stored == nil {
+			continue
+		}
+
+		block, err := i.Node.Block(int64(height))
+		if err != nil {
+			return 0, fmt.Errorf("failed to get block %d from node: %s", height, err)
+		}
+
+		if stored.Hash == common.HexToHash(block.Block.Hash().String()) {
+			return height, nil
+		}
+	}
+
+	return 0, nil
+}
@@ -19,6 +19,7 @@ import (
 	"github.com/forbole/juno/v4/models"
 	"github.com/forbole/juno/v4/modules"
 	"github.com/forbole/juno/v4/node"
+	"github.com/forbole/juno/v4/sink"
 	"github.com/forbole/juno/v4/types"
 )
 
@@ -27,25 +28,38 @@ type Indexer interface {
 	// It returns an error if any export process fails.
 	Process(height uint64) error
 
+	// Fetch gets from the node all the data of the block at the given height, without storing anything.
+	// It is safe to call Fetch concurrently for different heights.
+	Fetch(height uint64) (*BlockData, error)
+
+	// Apply exports the given block data inside the database, within a single database transaction.
+	// Blocks must be applied in height order, since handlers may depend on the state left by previous heights.
+	Apply(data *BlockData) error
+
+	// Reprocess calls the handlers of the modules on the given block data, within a single database transaction,
+	// without storing the block and its transactions again.
+	Reprocess(data *BlockData) error
+
 	// Processed tells whether the current Indexer has already processed the given height of Block
 	// An error is returned if the operation fails.
 	Processed(ctx context.Context, height uint64) (bool, error)
 
 	// ExportBlock accepts a finalized block and persists then inside the database.
 	// An error is returned if write fails.
-	ExportBlock(block *tmctypes.ResultBlock, events *tmctypes.ResultBlockResults, txs []*types.Tx, vals *tmctypes.ResultValidators) error
+	ExportBlock(ctx context.Context, block *tmctypes.ResultBlock, events *tmctypes.ResultBlockResults, txs []*types.Tx, vals *tmctypes.ResultValidators) error
 
 	// ExportTxs accepts a slice of transactions and persists then inside the database.
 	// An error is returned if write fails.
-	ExportTxs(block *tmctypes.ResultBlock, txs []*types.Tx) error
+	ExportTxs(ctx context.Context, block *tmctypes.ResultBlock, txs []*types.Tx) error
 
 	// ExportValidators accepts ResultValidators and persists validators inside the database.
 	// An error is returned if write fails.
-	ExportValidators(block *tmctypes.ResultBlock, vals *tmctypes.ResultValidators) error
+	ExportValidators(ctx context.Context, block *tmctypes.ResultBlock, vals *tmctypes.ResultValidators) error
 
-	// ExportCommit accepts ResultValidators and persists validator commit signatures inside the database.
+	// ExportCommit accepts the validator set of the height committed by the last commit of the given block,
+	// and persists the validator commit signatures inside the database.
 	// An error is returned if write fails.
-	ExportCommit(block *tmctypes.ResultBlock, vals *tmctypes.ResultValidators) error
+	ExportCommit(ctx context.Context, block *tmctypes.ResultBlock, vals *tmctypes.ResultValidators) error
 
 	// ExportEvents accepts a slice of transactions and get events in order to save in database.
 	ExportEvents(ctx context.Context, block *tmctypes.ResultBlock, events *tmctypes.ResultBlockResults) error
@@ -54,17 +68,27 @@ type Indexer interface {
 	// in the order in which they have been registered.
 	HandleGenesis(genesisDoc *tmtypes.GenesisDoc, appState map[string]json.RawMessage) error
 
-	HandleBlock(block *tmctypes.ResultBlock, events *tmctypes.ResultBlockResults, txs []*types.Tx, vals *tmctypes.ResultValidators)
+	// HandleBlock accepts the block and calls the block handlers.
+	// An error is returned if any handler fails, unless the error policy of its module allows to go on.
+	HandleBlock(ctx context.Context, block *tmctypes.ResultBlock, events *tmctypes.ResultBlockResults, txs []*types.Tx, vals *tmctypes.ResultValidators) error
+
+	// HandleCommit accepts the block and the validator set of the height committed by its last commit,
+	// and calls the commit handlers.
+	// An error is returned if any handler fails, unless the error policy of its module allows to go on.
+	HandleCommit(ctx context.Context, block *tmctypes.ResultBlock, vals *tmctypes.ResultValidators) error
 
 	// HandleTx accepts the transaction and calls the tx handlers.
-	HandleTx(tx *types.Tx)
+	// An error is returned if any handler fails, unless the error policy of its module allows to go on.
+	HandleTx(ctx context.Context, tx *types.Tx) error
 
 	// HandleMessage accepts the transaction and handles messages contained
 	// inside the transaction.
-	HandleMessage(block *tmctypes.ResultBlock, index int, msg sdk.Msg, tx *types.Tx)
+	// An error is returned if any handler fails, unless the error policy of its module allows to go on.
+	HandleMessage(ctx context.Context, block *tmctypes.ResultBlock, index int, msg sdk.Msg, tx *types.Tx) error
 
 	// HandleEvent accepts the transaction and handles events contained inside the transaction.
-	HandleEvent(ctx context.Context, block *tmctypes.ResultBlock, txHash common.Hash, event sdk.Event)
+	// An error is returned if any handler fails, unless the error policy of its module allows to go on.
+	HandleEvent(ctx context.Context, block *tmctypes.ResultBlock, txHash common.Hash, event sdk.Event) error
 
 	// ExportEpoch accepts a finalized block height and block hash then inside the database.
 	ExportEpoch(block *tmctypes.ResultBlock) error
@@ -86,6 +110,17 @@ func DefaultIndexer(codec codec.Codec, proxy node.Node, db database.Database, mo
 	}
 }
 
+// BlockData contains all the data of a block fetched from the node.
+// Validators is the validator set of the block height, while CommitValidators is the one of the height committed
+// by the last commit of the block, which is nil for the first block.
+type BlockData struct {
+	Block            *tmctypes.ResultBlock
+	BlockResults     *tmctypes.ResultBlockResults
+	Txs              []*types.Tx
+	Validators       *tmctypes.ResultValidators
+	CommitValidators *tmctypes.ResultValidators
+}
+
 type Impl struct {
 	Ctx context.Context
 
@@ -114,38 +149,71 @@ func (i *Impl) HandleGenesis(genesisDoc *tmtypes.GenesisDoc, appState map[string
 	return nil
 }
 
-func (i *Impl) HandleBlock(block *tmctypes.ResultBlock, events *tmctypes.ResultBlockResults, txs []*types.Tx, vals *tmctypes.ResultValidators) {
+func (i *Impl) HandleBlock(ctx context.Context, block *tmctypes.ResultBlock, events *tmctypes.ResultBlockResults, txs []*types.Tx, vals *tmctypes.ResultValidators) error {
 	for _, module := range i.Modules {
 		if blockModule, ok := module.(modules.BlockModule); ok {
-			err := blockModule.HandleBlock(block, events, txs, vals)
+			err := i.handleWithPolicy(ctx, module, block.Block.Height, common.Hash{}, nil, func(ctx context.Context) error {
+				return blockModule.HandleBlock(ctx, block, events, txs, vals)
+			})
 			if err != nil {
 				log.Errorw("error while handling block", "module", module.Name(), "height", block.Block.Height, "err", err)
+				return fmt.Errorf("module %s failed to handle block %d: %s", module.Name(), block.Block.Height, err)
+			}
+		}
+	}
+
+	return nil
+}
+
+func (i *Impl) HandleCommit(ctx context.Context, block *tmctypes.ResultBlock, vals *tmctypes.ResultValidators) error {
+	if vals == nil {
+		return nil
+	}
+
+	for _, module := range i.Modules {
+		if commitModule, ok := module.(modules.CommitModule); ok {
+			err := i.handleWithPolicy(ctx, module, block.Block.Height, common.Hash{}, nil, func(ctx context.Context) error {
+				return commitModule.HandleCommit(ctx, block, vals)
+			})
+			if err != nil {
+				log.Errorw("error while handling commit", "module", module.Name(), "height", block.Block.Height, "err", err)
+				return fmt.Errorf("module %s failed to handle commit of block %d: %s", module.Name(), block.Block.Height, err)
 			}
 		}
 	}
+
+	return nil
 }
 
-func (i *Impl) HandleTx(tx *types.Tx) {
+func (i *Impl) HandleTx(ctx context.Context, tx *types.Tx) error {
 	// Call the tx handlers
 	for _, module := range i.Modules {
 		if transactionModule, ok := module.(modules.TransactionModule); ok {
-			err := transactionModule.HandleTx(tx)
+			err := i.handleWithPolicy(ctx, module, tx.Height, common.HexToHash(tx.TxHash), nil, func(ctx context.Context) error {
+				return transactionModule.HandleTx(ctx, tx)
+			})
 			if err != nil {
 				log.Errorw("error while handling transaction", "module", module.Name(), "height", tx.Height,
 					"txHash", tx.TxHash, "err", err)
+				return fmt.Errorf("module %s failed to handle tx %s: %s", module.Name(), tx.TxHash, err)
 			}
 		}
 	}
+
+	return nil
 }
 
-func (i *Impl) HandleMessage(block *tmctypes.ResultBlock, index int, msg sdk.Msg, tx *types.Tx) {
+func (i *Impl) HandleMessage(ctx context.Context, block *tmctypes.ResultBlock, index int, msg sdk.Msg, tx *types.Tx) error {
 	// Allow modules to handle the message
 	for _, module := range i.Modules {
 		if messageModule, ok := module.(modules.MessageModule); ok {
-			err := messageModule.HandleMsg(block, index, msg, tx)
+			err := i.handleWithPolicy(ctx, module, tx.Height, common.HexToHash(tx.TxHash), nil, func(ctx context.Context) error {
+				return messageModule.HandleMsg(ctx, block, index, msg, tx)
+			})
 			if err != nil {
 				log.Errorw("error while handling message", "module", module, "height", tx.Height,
 					"txHash", tx.TxHash, "msg", proto.MessageName(msg), "err", err)
+				return fmt.Errorf("module %s failed to handle message %d of tx %s: %s", module.Name(), index, tx.TxHash, err)
 			}
 		}
 	}
@@ -161,27 +229,37 @@ func (i *Impl) HandleMessage(block *tmctypes.ResultBlock, index int, msg sdk.Msg
 
 			for _, module := range i.Modules {
 				if messageModule, ok := module.(modules.AuthzMessageModule); ok {
-					err = messageModule.HandleMsgExec(index, msgExec, authzIndex, executedMsg, tx)
+					err = i.handleWithPolicy(ctx, module, tx.Height, common.HexToHash(tx.TxHash), nil, func(ctx context.Context) error {
+						return messageModule.HandleMsgExec(ctx, index, msgExec, authzIndex, executedMsg, tx)
+					})
 					if err != nil {
 						log.Errorw("error while handling message", "module", module, "height", tx.Height,
 							"txHash", tx.TxHash, "msg", proto.MessageName(executedMsg), "err", err)
+						return fmt.Errorf("module %s failed to handle authz message %d of tx %s: %s", module.Name(), authzIndex, tx.TxHash, err)
 					}
 				}
 			}
 		}
 	}
+
+	return nil
 }
 
 // HandleEvent accepts the transaction and handles events contained inside the transaction.
-func (i *Impl) HandleEvent(ctx context.Context, block *tmctypes.ResultBlock, txHash common.Hash, event sdk.Event) {
+func (i *Impl) HandleEvent(ctx context.Context, block *tmctypes.ResultBlock, txHash common.Hash, event sdk.Event) error {
 	for _, module := range i.Modules {
 		if eventModule, ok := module.(modules.EventModule); ok {
-			err := eventModule.HandleEvent(ctx, block, txHash, event)
+			err := i.handleWithPolicy(ctx, module, block.Block.Height, txHash, &event, func(ctx context.Context) error {
+				return eventModule.HandleEvent(ctx, block, txHash, event)
+			})
 			if err != nil {
 				log.Errorw("failed to handle event", "module", module.Name(), "event", event, "error", err)
+				return fmt.Errorf("module %s failed to handle event %s: %s", module.Name(), event.Type, err)
 			}
 		}
 	}
+
+	return nil
 }
 
 // Process fetches a block for a given height and associated metadata and export it to a database.
@@ -189,60 +267,205 @@ func (i *Impl) HandleEvent(ctx context.Context, block *tmctypes.ResultBlock, txH
 func (i *Impl) Process(height uint64) error {
 	log.Debugw("processing block", "height", height)
 
+	data, err := i.Fetch(height)
+	if err != nil {
+		return err
+	}
+
+	return i.Apply(data)
+}
+
+// Fetch implements Indexer
+func (i *Impl) Fetch(height uint64) (*BlockData, error) {
 	block, err := i.Node.Block(int64(height))
 	if err != nil {
-		return fmt.Errorf("failed to get block from node: %s", err)
+		return nil, fmt.Errorf("failed to get block from node: %s", err)
 	}
 
 	log.WorkerLatencyHist.Observe(float64(time.Since(block.Block.Time).Milliseconds()))
 
 	blockResults, err := i.Node.BlockResults(int64(height))
 	if err != nil {
-		return fmt.Errorf("failed to get block results from node: %s", err)
+		return nil, fmt.Errorf("failed to get block results from node: %s", err)
 	}
 
 	txs, err := i.Node.Txs(block)
 	if err != nil {
-		return fmt.Errorf("failed to get transactions for block: %s", err)
+		return nil, fmt.Errorf("failed to get transactions for block: %s", err)
+	}
+
+	vals, err := i.Node.Validators(int64(height))
+	if err != nil {
+		return nil, fmt.Errorf("failed to get validators for block: %s", err)
+	}
+
+	var commitVals *tmctypes.ResultValidators
+	if block.Block.LastCommit != nil && block.Block.LastCommit.Height > 0 {
+		commitVals, err = i.Node.Validators(block.Block.LastCommit.Height)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get validators for last commit: %s", err)
+		}
+	}
+
+	return &BlockData{
+		Block:            block,
+		BlockResults:     blockResults,
+		Txs:              txs,
+		Validators:       vals,
+		CommitValidators: commitVals,
+	}, nil
+}
+
+// Apply implements Indexer.
+// Before storing anything, the parent hash of the block is checked against the stored one to detect chain forks.
+func (i *Impl) Apply(data *BlockData) error {
+	block, blockResults, txs := data.Block, data.BlockResults, data.Txs
+
+	err := i.checkParentHash(i.Ctx, block)
+	if err != nil {
+		return err
+	}
+
+	dbTx := i.DB.Begin(i.Ctx)
+	if dbTx.Db.Error != nil {
+		return fmt.Errorf("failed to begin database transaction: %s", dbTx.Db.Error)
+	}
+	buffer := sink.NewBuffer()
+	ctx := sink.WithBuffer(database.WithTx(i.Ctx, dbTx), buffer)
+
+	// the transaction is rolled back on errors and on panics of the module handlers alike
+	committed := false
+	defer func() {
+		if !committed {
+			dbTx.Rollback()
+		}
+	}()
+
+	err = i.ExportValidators(ctx, block, data.Validators)
+	if err != nil {
+		return err
 	}
 
-	err = i.ExportBlock(block, blockResults, txs, nil)
+	err = i.ExportBlock(ctx, block, blockResults, txs, data.Validators)
 	if err != nil {
 		return err
 	}
 
-	err = i.ExportTxs(block, txs)
+	if data.CommitValidators != nil {
+		err = i.ExportCommit(ctx, block, data.CommitValidators)
+		if err != nil {
+			return err
+		}
+
+		err = i.HandleCommit(ctx, block, data.CommitValidators)
+		if err != nil {
+			return err
+		}
+	}
+
+	err = i.ExportTxs(ctx, block, txs)
 	if err != nil {
 		return err
 	}
 
-	err = i.ExportEventsByTxs(i.Ctx, block, txs)
+	err = i.ExportEventsByTxs(ctx, block, txs)
 	if err != nil {
 		return err
 	}
 
+	err = i.DB.UpdateSyncProgress(ctx, uint64(block.Block.Height))
+	if err != nil {
+		return fmt.Errorf("failed to update sync progress: %s", err)
+	}
+
+	committed = true
+	err = dbTx.Commit()
+	if err != nil {
+		return fmt.Errorf("failed to commit block %d: %s", block.Block.Height, err)
+	}
+	flushRecords(i.Ctx, buffer, block.Block.Height)
+
 	log.DBLatencyHist.Observe(float64(time.Since(block.Block.Time).Milliseconds()))
 
 	return nil
 }
 
+// Reprocess implements Indexer
+func (i *Impl) Reprocess(data *BlockData) error {
+	block, blockResults, txs := data.Block, data.BlockResults, data.Txs
+
+	dbTx := i.DB.Begin(i.Ctx)
+	if dbTx.Db.Error != nil {
+		return fmt.Errorf("failed to begin database transaction: %s", dbTx.Db.Error)
+	}
+	buffer := sink.NewBuffer()
+	ctx := sink.WithBuffer(database.WithTx(i.Ctx, dbTx), buffer)
+
+	// the transaction is rolled back on errors and on panics of the module handlers alike
+	committed := false
+	defer func() {
+		if !committed {
+			dbTx.Rollback()
+		}
+	}()
+
+	err := i.HandleBlock(ctx, block, blockResults, txs, data.Validators)
+	if err != nil {
+		return err
+	}
+
+	err = i.HandleCommit(ctx, block, data.CommitValidators)
+	if err != nil {
+		return err
+	}
+
+	for _, tx := range txs {
+		err = i.handleTx(ctx, block, tx)
+		if err != nil {
+			return err
+		}
+	}
+
+	err = i.ExportEventsByTxs(ctx, block, txs)
+	if err != nil {
+		return err
+	}
+
+	committed = true
+	err = dbTx.Commit()
+	if err != nil {
+		return fmt.Errorf("failed to commit block %d: %s", block.Block.Height, err)
+	}
+	flushRecords(i.Ctx, buffer, block.Block.Height)
+
+	return nil
+}
+
+// flushRecords delivers the records emitted to the sinks not backed by the database while processing the given height,
+// once its database transaction is committed. The changes of the height cannot be discarded anymore, so a failed
+// delivery is only logged.
+func flushRecords(ctx context.Context, buffer *sink.Buffer, height int64) {
+	err := buffer.Flush(ctx)
+	if err != nil {
+		log.Errorw("failed to deliver the records of a committed height", "height", height, "err", err)
+	}
+}
+
 // ExportBlock accepts a finalized block and persists then inside the database.
 // An error is returned if write fails.
 func (i *Impl) ExportBlock(
-	block *tmctypes.ResultBlock, events *tmctypes.ResultBlockResults, txs []*types.Tx, vals *tmctypes.ResultValidators,
+	ctx context.Context, block *tmctypes.ResultBlock, events *tmctypes.ResultBlockResults, txs []*types.Tx, vals *tmctypes.ResultValidators,
 ) error {
 	// Save the block
-	err := i.DB.SaveBlock(i.Ctx, models.NewBlockFromTmBlock(block, SumGasTxs(txs)))
+	err := i.DB.SaveBlock(ctx, models.NewBlockFromTmBlock(block, SumGasTxs(txs)))
 	if err != nil {
 		return fmt.Errorf("failed to persist block: %s", err)
 	}
 
-	i.HandleBlock(block, events, txs, vals)
-
-	return nil
+	return i.HandleBlock(ctx, block, events, txs, vals)
 }
 
-func (i *Impl) ExportValidators(block *tmctypes.ResultBlock, vals *tmctypes.ResultValidators) error {
+func (i *Impl) ExportValidators(ctx context.Context, block *tmctypes.ResultBlock, vals *tmctypes.ResultValidators) error {
 	var validators = make([]*models.Validator, len(vals.Validators))
 	for index, val := range vals.Validators {
 		consAddr := sdk.ConsAddress(val.Address).String()
@@ -250,7 +473,7 @@ func (i *Impl) ExportValidators(block *tmctypes.ResultBlock, vals *tmctypes.Resu
 		validators[index] = models.NewValidator(common.HexToAddress(consAddr), models.BytesToPubkey(val.PubKey.Bytes()))
 	}
 
-	err := i.DB.SaveValidators(context.TODO(), validators)
+	err := i.DB.SaveValidators(ctx, validators)
 	if err != nil {
 		return fmt.Errorf("error while saving validators: %s", err)
 	}
@@ -265,16 +488,16 @@ func (i *Impl) ExportValidators(block *tmctypes.ResultBlock, vals *tmctypes.Resu
 	return nil
 }
 
-// ExportCommit accepts a block commitment and a corresponding set of
-// validators for the commitment and persists them to the database. An error is
-// returned if any write fails or if there is any missed aggregated data.
-func (i *Impl) ExportCommit(block *tmctypes.ResultBlock, vals *tmctypes.ResultValidators) error {
+// ExportCommit accepts a block commitment and the validator set of the committed height,
+// and persists the commit signatures to the database. An error is returned if any write fails
+// or if a signature was made by a validator outside the set.
+func (i *Impl) ExportCommit(ctx context.Context, block *tmctypes.ResultBlock, vals *tmctypes.ResultValidators) error {
 	commit := block.Block.LastCommit
 
-	var signatures []*types.CommitSig
+	var signatures []*models.PreCommit
 	for _, commitSig := range commit.Signatures {
 		// Avoid empty commits
-		if commitSig.Signature == nil {
+		if commitSig.Absent() {
 			continue
 		}
 
@@ -284,16 +507,16 @@ func (i *Impl) ExportCommit(block *tmctypes.ResultBlock, vals *tmctypes.ResultVa
 			return fmt.Errorf("failed to find validator by commit validator address %s", valAddr.String())
 		}
 
-		signatures = append(signatures, types.NewCommitSig(
-			types.ConvertValidatorAddressToBech32String(commitSig.ValidatorAddress),
-			val.VotingPower,
-			val.ProposerPriority,
-			commit.Height,
-			commitSig.Timestamp,
-		))
+		signatures = append(signatures, &models.PreCommit{
+			ValidatorAddress: common.BytesToAddress(commitSig.ValidatorAddress),
+			Height:           commit.Height,
+			Timestamp:        commitSig.Timestamp.UTC().Unix(),
+			VotingPower:      val.VotingPower,
+			ProposerPriority: val.ProposerPriority,
+		})
 	}
 
-	err := i.DB.SaveCommitSignatures(context.TODO(), signatures)
+	err := i.DB.SaveCommitSignatures(ctx, signatures)
 	if err != nil {
 		return fmt.Errorf("error while saving commit signatures: %s", err)
 	}
@@ -303,32 +526,48 @@ func (i *Impl) ExportCommit(block *tmctypes.ResultBlock, vals *tmctypes.ResultVa
 
 // ExportTxs accepts a slice of transactions and persists then inside the database.
 // An error is returned if write fails.
-func (i *Impl) ExportTxs(block *tmctypes.ResultBlock, txs []*types.Tx) error {
+func (i *Impl) ExportTxs(ctx context.Context, block *tmctypes.ResultBlock, txs []*types.Tx) error {
 	// handle all transactions inside the block
 	for ind, tx := range txs {
 		// save the transaction
-		err := i.DB.SaveTx(context.TODO(), uint64(block.Block.Time.UTC().UnixNano()), ind, tx)
+		err := i.DB.SaveTx(ctx, uint64(block.Block.Time.UTC().UnixNano()), ind, tx)
 		if err != nil {
 			return fmt.Errorf("error while storing tx with hash %s, %s", tx.TxHash, err)
 		}
 
-		// call the tx handlers
-		i.HandleTx(tx)
+		err = i.handleTx(ctx, block, tx)
+		if err != nil {
+			return err
+		}
+	}
 
-		// handle all messages contained inside the transaction
-		sdkMsgs := make([]sdk.Msg, len(tx.Body.Messages))
-		for ind, msg := range tx.Body.Messages {
-			var stdMsg sdk.Msg
-			err := i.codec.UnpackAny(msg, &stdMsg)
-			if err != nil {
-				return err
-			}
-			sdkMsgs[ind] = stdMsg
+	return nil
+}
+
+// handleTx calls the tx handlers and the msg handlers of all the messages contained inside the given transaction
+func (i *Impl) handleTx(ctx context.Context, block *tmctypes.ResultBlock, tx *types.Tx) error {
+	// call the tx handlers
+	err := i.HandleTx(ctx, tx)
+	if err != nil {
+		return err
+	}
+
+	// handle all messages contained inside the transaction
+	sdkMsgs := make([]sdk.Msg, len(tx.Body.Messages))
+	for ind, msg := range tx.Body.Messages {
+		var stdMsg sdk.Msg
+		err := i.codec.UnpackAny(msg, &stdMsg)
+		if err != nil {
+			return err
 		}
+		sdkMsgs[ind] = stdMsg
+	}
 
-		// call the msg handlers
-		for ind, sdkMsg := range sdkMsgs {
-			i.HandleMessage(block, ind, sdkMsg, tx)
+	// call the msg handlers
+	for ind, sdkMsg := range sdkMsgs {
+		err = i.HandleMessage(ctx, block, ind, sdkMsg, tx)
+		if err != nil {
+			return err
 		}
 	}
 
@@ -340,7 +579,10 @@ func (i *Impl) ExportEvents(ctx context.Context, block *tmctypes.ResultBlock, bl
 
 	for _, tx := range txsResults {
 		for _, event := range tx.Events {
-			i.HandleEvent(ctx, block, common.Hash{}, sdk.Event(event))
+			err := i.HandleEvent(ctx, block, common.Hash{}, sdk.Event(event))
+			if err != nil {
+				return err
+			}
 		}
 	}
 	return nil
@@ -350,7 +592,10 @@ func (i *Impl) ExportEventsByTxs(ctx context.Context, block *tmctypes.ResultBloc
 	for _, tx := range txs {
 		txHash := common.HexToHash(tx.TxHash)
 		for _, event := range tx.Events {
-			i.HandleEvent(ctx, block, txHash, sdk.Event(event))
+			err := i.HandleEvent(ctx, block, txHash, sdk.Event(event))
+			if err != nil {
+				return err
+			}
 		}
 	}
 	return nil
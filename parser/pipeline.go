@@ -0,0 +1,266 @@
+package parser
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/forbole/juno/v4/log"
+	"github.com/forbole/juno/v4/types"
+)
+
+// pipelineJob represents a height flowing through a Pipeline.
+// Once the job is dispatched to the fetchers, done is closed when either data or err is set, unless the height
+// has already been processed.
+type pipelineJob struct {
+	height     uint64
+	data       *BlockData
+	err        error
+	dispatched bool
+	done       chan struct{}
+}
+
+// Pipeline is a job consumer that fetches the data of several heights concurrently, while applying them
+// to the database one at a time, in strict height order, regardless of the order in which they have been enqueued.
+// This allows to keep the throughput of multiple workers without letting the modules handle events out of order.
+type Pipeline struct {
+	worker      *Worker
+	queue       types.HeightQueue
+	fetchers    int
+	startHeight uint64
+}
+
+// NewPipeline allows to create a new Pipeline that fetches the heights using the given number of fetchers.
+// The heights are applied in order starting from the given one. Any height enqueued once the following ones have
+// been applied, including the ones before the start height, cannot be ordered anymore: it is skipped, and an error
+// is logged unless it has already been processed.
+func NewPipeline(ctx *Context, queue types.HeightQueue, fetchers int, startHeight uint64) *Pipeline {
+	if fetchers < 1 {
+		fetchers = 1
+	}
+
+	return &Pipeline{
+		worker:      NewWorker(ctx, 0),
+		queue:       queue,
+		fetchers:    fetchers,
+		startHeight: startHeight,
+	}
+}
+
+func (p *Pipeline) SetIndexer(indexer Indexer) {
+	p.worker.SetIndexer(indexer)
+}
+
+// Start starts the pipeline by listening for new jobs (block heights) from the given queue.
+// The jobs are reordered by height, and a height is only applied once all the previous ones have been processed.
+// Only the heights close to the next one to be applied are fetched ahead, so that the data waiting to be applied
+// is bounded regardless of the order in which the heights are enqueued.
+// Any height that fails to be applied is retried, and the parser is stopped if it keeps failing, since the
+// following heights cannot be applied past it.
+func (p *Pipeline) Start() {
+	log.WorkerCount.Inc()
+	chainID, err := p.worker.node.ChainID()
+	if err != nil {
+		log.Errorw("error while getting chain ID from the node ", "err", err)
+	}
+
+	// window bounds the number of heights fetched ahead of the next one to be applied
+	window := uint64(2 * p.fetchers)
+	toFetch := make(chan *pipelineJob, window)
+	dispatch := func(job *pipelineJob) {
+		if !job.dispatched {
+			job.dispatched = true
+			toFetch <- job
+		}
+	}
+
+	for i := 0; i < p.fetchers; i++ {
+		go func() {
+			for job := range toFetch {
+				p.fetch(job)
+				close(job.done)
+			}
+		}()
+	}
+
+	order := newHeightOrder(p.startHeight, p.synced)
+	for height := range p.queue {
+		job := &pipelineJob{height: height, done: make(chan struct{})}
+		if !order.push(job) {
+			p.skip(job.height)
+			continue
+		}
+
+		jobs, err := order.ready()
+		if err != nil {
+			log.Errorw("error while checking the processed heights", "err", err)
+		}
+
+		for i, job := range jobs {
+			for _, next := range jobs[i:] {
+				if next.height >= job.height+window {
+					break
+				}
+				dispatch(next)
+			}
+			for _, next := range order.fetchable(window) {
+				dispatch(next)
+			}
+			<-job.done
+
+			if err := p.apply(job); err != nil {
+				haltOnFork(err)
+				haltOnGap(job.height, p.worker.retry(job.height, err))
+			}
+			log.WorkerHeight.WithLabelValues(fmt.Sprintf("%d", p.worker.index), chainID).Set(float64(job.height))
+		}
+
+		for _, next := range order.fetchable(window) {
+			dispatch(next)
+		}
+	}
+	close(toFetch)
+}
+
+// skip logs the given height, enqueued after the following ones have been applied, unless it has already been processed
+func (p *Pipeline) skip(height uint64) {
+	exists, err := p.worker.indexer.Processed(p.worker.ctx, height)
+	if err != nil {
+		log.Errorw("error while searching for block", "height", height, "err", err)
+		return
+	}
+	if !exists {
+		log.Errorw("skipping height enqueued after the following ones have been applied", "height", height)
+	}
+}
+
+// synced tells whether all the heights between from and to, both included, have already been processed
+func (p *Pipeline) synced(from, to uint64) (bool, error) {
+	missing, err := p.worker.db.GetMissingHeights(p.worker.ctx, from, to)
+	return len(missing) == 0, err
+}
+
+// haltOnGap stops the whole process if the given height failed with the given error, even after being retried,
+// since the following heights cannot be applied before it without handling their events out of order.
+func haltOnGap(height uint64, err error) {
+	if err != nil {
+		log.Errorw("stopping the parser, the heights following a failed one cannot be applied", "height", height, "err", err)
+		log.Stop()
+		os.Exit(1)
+	}
+}
+
+// fetch gets the data of the given job height, unless it has already been processed
+func (p *Pipeline) fetch(job *pipelineJob) {
+	if job.height == 0 {
+		return
+	}
+
+	exists, err := p.worker.indexer.Processed(p.worker.ctx, job.height)
+	if err != nil {
+		job.err = fmt.Errorf("error while searching for block: %s", err)
+		return
+	}
+	if exists {
+		log.Infow("skipping already exported block", "height", job.height)
+		return
+	}
+
+	job.data, job.err = p.worker.indexer.Fetch(job.height)
+}
+
+// apply stores the data fetched for the given job inside the database
+func (p *Pipeline) apply(job *pipelineJob) error {
+	if job.height == 0 {
+		return p.worker.ProcessIfNotExists(job.height)
+	}
+	if job.err != nil {
+		return job.err
+	}
+	if job.data == nil {
+		return nil
+	}
+
+	log.Infow("processing block", "height", job.height)
+	err := p.worker.indexer.Apply(job.data)
+	if err != nil {
+		return err
+	}
+
+	return p.worker.onProcessed(job.height)
+}
+
+// heightOrder reorders the jobs enqueued in any order, releasing them by increasing height without gaps.
+// The pending jobs hold no data until they are fetched, which only happens once they are close to be released.
+type heightOrder struct {
+	next    uint64
+	pending map[uint64]*pipelineJob
+
+	// synced tells whether all the heights between from and to, both included, have already been processed,
+	// which allows skipping the heights that are never enqueued
+	synced func(from, to uint64) (bool, error)
+}
+
+// newHeightOrder returns a heightOrder releasing the jobs starting from the given height
+func newHeightOrder(next uint64, synced func(from, to uint64) (bool, error)) *heightOrder {
+	return &heightOrder{
+		next:    next,
+		pending: make(map[uint64]*pipelineJob),
+		synced:  synced,
+	}
+}
+
+// push adds the given job to the pending ones. It returns false if the job height comes before the ones that have
+// already been released, in which case the job is not added.
+func (o *heightOrder) push(job *pipelineJob) bool {
+	if job.height < o.next {
+		return false
+	}
+	o.pending[job.height] = job
+	return true
+}
+
+// ready returns the pending jobs that can be applied, sorted by height. A job can be applied when all the heights
+// before it have either been released or already been processed.
+func (o *heightOrder) ready() ([]*pipelineJob, error) {
+	var jobs []*pipelineJob
+	for len(o.pending) > 0 {
+		job, ok := o.pending[o.next]
+		if !ok {
+			lowest := o.lowest()
+			synced, err := o.synced(o.next, lowest-1)
+			if err != nil || !synced {
+				return jobs, err
+			}
+			job = o.pending[lowest]
+		}
+
+		delete(o.pending, job.height)
+		o.next = job.height + 1
+		jobs = append(jobs, job)
+	}
+	return jobs, nil
+}
+
+// fetchable returns the pending jobs whose height is within the given window from the next height to be released,
+// sorted by height
+func (o *heightOrder) fetchable(window uint64) []*pipelineJob {
+	var jobs []*pipelineJob
+	for height := o.next; height < o.next+window; height++ {
+		if job, ok := o.pending[height]; ok {
+			jobs = append(jobs, job)
+		}
+	}
+	return jobs
+}
+
+// lowest returns the lowest pending height
+func (o *heightOrder) lowest() uint64 {
+	first := true
+	var lowest uint64
+	for height := range o.pending {
+		if first || height < lowest {
+			lowest, first = height, false
+		}
+	}
+	return lowest
+}
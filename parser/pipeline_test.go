@@ -0,0 +1,88 @@
+package parser
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestHeightOrder(t *testing.T) {
+	// heights 3 and 4 have been processed by a previous run, so they are never enqueued
+	processed := map[uint64]bool{3: true, 4: true}
+	order := newHeightOrder(1, func(from, to uint64) (bool, error) {
+		for height := from; height <= to; height++ {
+			if !processed[height] {
+				return false, nil
+			}
+		}
+		return true, nil
+	})
+
+	var applied []uint64
+	push := func(heights ...uint64) {
+		for _, height := range heights {
+			require.True(t, order.push(&pipelineJob{height: height}))
+		}
+
+		jobs, err := order.ready()
+		require.NoError(t, err)
+		for _, job := range jobs {
+			applied = append(applied, job.height)
+		}
+	}
+
+	// the new blocks are enqueued before the missing ones
+	push(6, 5)
+	require.Empty(t, applied)
+
+	push(2)
+	require.Empty(t, applied)
+
+	push(1)
+	require.Equal(t, []uint64{1, 2, 5, 6}, applied)
+
+	// a gap is never skipped until it is filled
+	push(9, 8)
+	require.Equal(t, []uint64{1, 2, 5, 6}, applied)
+
+	push(7)
+	require.Equal(t, []uint64{1, 2, 5, 6, 7, 8, 9}, applied)
+
+	// heights coming before the applied ones are refused
+	require.False(t, order.push(&pipelineJob{height: 4}))
+	require.False(t, order.push(&pipelineJob{height: 9}))
+}
+
+func TestHeightOrder_Fetchable(t *testing.T) {
+	order := newHeightOrder(1, func(from, to uint64) (bool, error) {
+		return false, nil
+	})
+
+	heights := func(jobs []*pipelineJob) []uint64 {
+		var heights []uint64
+		for _, job := range jobs {
+			heights = append(heights, job.height)
+		}
+		return heights
+	}
+
+	// the heights far from the next one to be released are not fetched yet
+	for _, height := range []uint64{100, 3, 2, 50} {
+		require.True(t, order.push(&pipelineJob{height: height}))
+	}
+	require.Equal(t, []uint64{2, 3}, heights(order.fetchable(4)))
+
+	require.True(t, order.push(&pipelineJob{height: 1}))
+	jobs, err := order.ready()
+	require.NoError(t, err)
+	require.Equal(t, []uint64{1, 2, 3}, heights(jobs))
+	require.Empty(t, order.fetchable(4))
+
+	// the window moves forward with the released heights
+	for height := uint64(4); height < 50; height++ {
+		require.True(t, order.push(&pipelineJob{height: height}))
+	}
+	_, err = order.ready()
+	require.NoError(t, err)
+	require.Equal(t, []uint64{100}, heights(order.fetchable(60)))
+}
@@ -2,16 +2,18 @@ package parser
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"os"
 	"time"
 
 	"github.com/cosmos/cosmos-sdk/codec"
 
 	"github.com/forbole/juno/v4/database"
 	"github.com/forbole/juno/v4/log"
+	"github.com/forbole/juno/v4/models"
 	"github.com/forbole/juno/v4/modules"
 	"github.com/forbole/juno/v4/node"
-	"github.com/forbole/juno/v4/types"
 	"github.com/forbole/juno/v4/types/config"
 	"github.com/forbole/juno/v4/types/utils"
 )
@@ -23,28 +25,24 @@ type Worker struct {
 
 	index int
 
-	queue   types.HeightQueue
 	codec   codec.Codec
 	modules []modules.Module
 
 	node    node.Node
 	db      database.Database
 	indexer Indexer
-
-	concurrentSync bool
 }
 
 // NewWorker allows to create a new Worker implementation.
-func NewWorker(ctx *Context, queue types.HeightQueue, index int, concurrentSync bool) *Worker {
+func NewWorker(ctx *Context, index int) *Worker {
 	return &Worker{
-		index:          index,
-		codec:          ctx.EncodingConfig.Marshaler,
-		node:           ctx.Node,
-		queue:          queue,
-		db:             ctx.Database,
-		indexer:        DefaultIndexer(ctx.EncodingConfig.Marshaler, ctx.Node, ctx.Database, ctx.Modules),
-		modules:        ctx.Modules,
-		concurrentSync: concurrentSync,
+		ctx:     context.Background(),
+		index:   index,
+		codec:   ctx.EncodingConfig.Marshaler,
+		node:    ctx.Node,
+		db:      ctx.Database,
+		indexer: DefaultIndexer(ctx.EncodingConfig.Marshaler, ctx.Node, ctx.Database, ctx.Modules),
+		modules: ctx.Modules,
 	}
 }
 
@@ -52,35 +50,46 @@ func (w *Worker) SetIndexer(indexer Indexer) {
 	w.indexer = indexer
 }
 
-// Start starts a worker by listening for new jobs (block heights) from the
-// given worker queue. Any failed job is logged and re-enqueued.
-func (w *Worker) Start() {
-	log.WorkerCount.Inc()
-	chainID, err := w.node.ChainID()
-	if err != nil {
-		log.Errorw("error while getting chain ID from the node ", "err", err)
+// retry processes again the given height, which failed with the given error, waiting an exponentially
+// growing delay between each attempt. If the height keeps failing after the max number of attempts,
+// it is stored inside the failed heights table so that it can be retried later, and the last error is returned.
+func (w *Worker) retry(height uint64, err error) error {
+	retryCfg := config.Cfg.Parser.Retry
+	maxAttempts := retryCfg.GetMaxAttempts()
+
+	for attempt := uint(1); attempt < maxAttempts; attempt++ {
+		delay := retryCfg.Delay(attempt)
+		log.Errorw("error while process block, retrying", "height", height, "attempt", attempt, "delay", delay, "err", err)
+		time.Sleep(delay)
+
+		err = w.ProcessIfNotExists(height)
+		if err == nil {
+			return nil
+		}
+		haltOnFork(err)
 	}
 
-	for i := range w.queue {
-		if err := w.ProcessIfNotExists(i); err != nil {
-			if w.concurrentSync {
-				// re-enqueue any failed job after average block time
-				// TODO: Implement exponential backoff or max retries for a block height.
-				go func() {
-					log.Errorw("re-enqueueing failed block", "height", i, "err", err)
-					w.queue <- i
-				}()
-				continue
-			}
-
-			for err != nil {
-				log.Errorw("error while process block", "height", i, "err", err)
-				time.Sleep(config.GetAvgBlockTime())
-				err = w.ProcessIfNotExists(i)
-			}
-		} else {
-			log.WorkerHeight.WithLabelValues(fmt.Sprintf("%d", w.index), chainID).Set(float64(i))
-		}
+	log.Errorw("giving up on block", "height", height, "attempts", maxAttempts, "err", err)
+	saveErr := w.db.SaveFailedHeight(w.ctx, &models.FailedHeight{
+		Height:    height,
+		Attempts:  maxAttempts,
+		LastError: err.Error(),
+		UpdateAt:  time.Now().UTC().Unix(),
+	})
+	if saveErr != nil {
+		log.Errorw("failed to save failed height", "height", height, "err", saveErr)
+	}
+	return err
+}
+
+// haltOnFork stops the whole process if the given error is a *ForkError, since a fork can't be fixed
+// by retrying and requires the operator to roll the database back.
+func haltOnFork(err error) {
+	var forkErr *ForkError
+	if errors.As(err, &forkErr) {
+		log.Errorw("stopping the parser", "err", err)
+		log.Stop()
+		os.Exit(1)
 	}
 }
 
@@ -118,21 +127,27 @@ func (w *Worker) Process(height uint64) error {
 	}
 
 	err := w.indexer.Process(height)
+	if err != nil {
+		return err
+	}
 
-	if err == nil {
-		log.Infow("processed block", "height", height)
+	return w.onProcessed(height)
+}
 
-		totalBlocks := w.indexer.GetBlockRecordNum(context.TODO())
-		log.DBBlockCount.Set(float64(totalBlocks))
+// onProcessed logs the given processed height and updates the database metrics
+func (w *Worker) onProcessed(height uint64) error {
+	log.Infow("processed block", "height", height)
 
-		dbLatestHeight, err := w.indexer.GetLastBlockRecordHeight(context.TODO())
-		if err != nil {
-			return err
-		}
-		log.DBLatestHeight.Set(float64(dbLatestHeight))
+	totalBlocks := w.indexer.GetBlockRecordNum(context.TODO())
+	log.DBBlockCount.Set(float64(totalBlocks))
+
+	dbLatestHeight, err := w.indexer.GetLastBlockRecordHeight(context.TODO())
+	if err != nil {
+		return err
 	}
+	log.DBLatestHeight.Set(float64(dbLatestHeight))
 
-	return err
+	return nil
 }
 
 // ProcessTransactions fetches transactions for a given height and stores them into the database.
@@ -148,7 +163,7 @@ func (w *Worker) ProcessTransactions(height int64) error {
 		return fmt.Errorf("failed to get transactions for block: %s", err)
 	}
 
-	return w.indexer.ExportTxs(block, txs)
+	return w.indexer.ExportTxs(w.ctx, block, txs)
 }
 
 // ProcessEvents fetches events for a given height and stores them into the database.
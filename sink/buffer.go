@@ -0,0 +1,129 @@
+package sink
+
+import (
+	"context"
+	"sync"
+)
+
+var (
+	_ Sink = &BufferedSink{}
+)
+
+type bufferKey struct{}
+
+// WithBuffer returns a copy of ctx carrying the given buffer, to which the BufferedSink instances emit the records
+// instead of delivering them
+func WithBuffer(ctx context.Context, buffer *Buffer) context.Context {
+	return context.WithValue(ctx, bufferKey{}, buffer)
+}
+
+// BufferFromContext returns the buffer carried by ctx, if any
+func BufferFromContext(ctx context.Context) (*Buffer, bool) {
+	buffer, ok := ctx.Value(bufferKey{}).(*Buffer)
+	return buffer, ok
+}
+
+// bufferedEmit represents the records emitted to a sink by a single Emit call
+type bufferedEmit struct {
+	sink    Sink
+	records []*Record
+}
+
+// Buffer holds the records emitted to the sinks that are not backed by the database while a height is processed,
+// so that they are only delivered once the database transaction of the height is committed.
+// The methods of a nil Buffer do nothing.
+type Buffer struct {
+	mu      sync.Mutex
+	emitted []bufferedEmit
+}
+
+// NewBuffer builds a new empty Buffer instance
+func NewBuffer() *Buffer {
+	return &Buffer{}
+}
+
+// add stores the given records, which are to be delivered to the given sink
+func (b *Buffer) add(sink Sink, records []*Record) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.emitted = append(b.emitted, bufferedEmit{sink: sink, records: records})
+}
+
+// Len returns the number of Emit calls held by the buffer, to be given to Truncate when the changes made since
+// then are discarded
+func (b *Buffer) Len() int {
+	if b == nil {
+		return 0
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	return len(b.emitted)
+}
+
+// Truncate discards the records emitted after Len returned the given length, as when the database changes made
+// since then are rolled back to a savepoint
+func (b *Buffer) Truncate(length int) {
+	if b == nil {
+		return
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if length < len(b.emitted) {
+		b.emitted = b.emitted[:length]
+	}
+}
+
+// Flush delivers the held records to their sinks, in order, and empties the buffer.
+// It must be called once the database transaction the records belong to is committed.
+func (b *Buffer) Flush(ctx context.Context) error {
+	if b == nil {
+		return nil
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	emitted := b.emitted
+	b.emitted = nil
+	for _, emit := range emitted {
+		err := emit.sink.Emit(ctx, emit.records...)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ---------------------------------------------------------------------------------------------------------------------
+
+// BufferedSink is a Sink that holds the records emitted with a ctx carrying a Buffer inside it, delivering them to
+// the wrapped sink only when the buffer is flushed. The records emitted without a Buffer are delivered right away.
+type BufferedSink struct {
+	sink Sink
+}
+
+// NewBufferedSink builds a new BufferedSink instance wrapping the given sink
+func NewBufferedSink(sink Sink) *BufferedSink {
+	return &BufferedSink{
+		sink: sink,
+	}
+}
+
+// Emit implements Sink
+func (s *BufferedSink) Emit(ctx context.Context, records ...*Record) error {
+	if buffer, ok := BufferFromContext(ctx); ok {
+		buffer.add(s.sink, records)
+		return nil
+	}
+	return s.sink.Emit(ctx, records...)
+}
+
+// Close implements Sink
+func (s *BufferedSink) Close() error {
+	return s.sink.Close()
+}
@@ -0,0 +1,61 @@
+package sink_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/forbole/juno/v4/common"
+	"github.com/forbole/juno/v4/models"
+	"github.com/forbole/juno/v4/sink"
+)
+
+// recordingSink keeps the heights of the delivered records
+type recordingSink struct {
+	heights []int64
+}
+
+func (s *recordingSink) Emit(_ context.Context, records ...*sink.Record) error {
+	for _, record := range records {
+		s.heights = append(s.heights, record.Height)
+	}
+	return nil
+}
+
+func (s *recordingSink) Close() error {
+	return nil
+}
+
+func TestBufferedSink_Emit(t *testing.T) {
+	recorder := &recordingSink{}
+	bufferedSink := sink.NewBufferedSink(recorder)
+	record := func(height int64) *sink.Record {
+		return sink.NewRecord("payment", height, common.Hash{}, sink.OperationSave, &models.StreamRecord{})
+	}
+
+	// the records emitted without a buffer are delivered right away
+	require.NoError(t, bufferedSink.Emit(context.Background(), record(1)))
+	require.Equal(t, []int64{1}, recorder.heights)
+
+	buffer := sink.NewBuffer()
+	ctx := sink.WithBuffer(context.Background(), buffer)
+	require.NoError(t, bufferedSink.Emit(ctx, record(2)))
+
+	// the records emitted after a savepoint are discarded along with the database changes
+	emitted := buffer.Len()
+	require.NoError(t, bufferedSink.Emit(ctx, record(3), record(4)))
+	buffer.Truncate(emitted)
+	require.NoError(t, bufferedSink.Emit(ctx, record(5)))
+	require.Equal(t, []int64{1}, recorder.heights)
+
+	require.NoError(t, buffer.Flush(context.Background()))
+	require.Equal(t, []int64{1, 2, 5}, recorder.heights)
+	require.Zero(t, buffer.Len())
+
+	// a nil buffer holds nothing
+	var nilBuffer *sink.Buffer
+	nilBuffer.Truncate(0)
+	require.Zero(t, nilBuffer.Len())
+	require.NoError(t, nilBuffer.Flush(context.Background()))
+}
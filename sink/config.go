@@ -0,0 +1,116 @@
+package sink
+
+import (
+	"fmt"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/forbole/juno/v4/database"
+)
+
+const (
+	TypeSQL   = "sql"
+	TypeFile  = "file"
+	TypeKafka = "kafka"
+)
+
+// Config represents the configuration of the sinks to which the modules emit their changes
+type Config struct {
+	Types []string     `yaml:"types"`
+	File  *FileConfig  `yaml:"file,omitempty"`
+	Kafka *KafkaConfig `yaml:"kafka,omitempty"`
+}
+
+// FileConfig represents the configuration of the JSON-lines file sink
+type FileConfig struct {
+	Path string `yaml:"path"`
+}
+
+// KafkaConfig represents the configuration of the Kafka sink
+type KafkaConfig struct {
+	Broker       string `yaml:"broker"`
+	Topic        string `yaml:"topic"`
+	Partition    int32  `yaml:"partition"`
+	RequiredAcks int16  `yaml:"required_acks"`
+	ClientID     string `yaml:"client_id"`
+}
+
+// DefaultConfig returns the default Config instance, which only writes to the database
+func DefaultConfig() *Config {
+	return &Config{
+		Types: []string{TypeSQL},
+	}
+}
+
+// HasType returns true if the sink having the given type is enabled
+func (cfg *Config) HasType(sinkType string) bool {
+	for _, t := range cfg.Types {
+		if t == sinkType {
+			return true
+		}
+	}
+	return false
+}
+
+// ParseConfig allows to parse a byte array as a Config instance.
+// If the sink section is missing, the default configuration is returned.
+func ParseConfig(bz []byte) (*Config, error) {
+	type T struct {
+		Config *Config `yaml:"sink"`
+	}
+	var cfg T
+	err := yaml.Unmarshal(bz, &cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	if cfg.Config == nil || len(cfg.Config.Types) == 0 {
+		return DefaultConfig(), nil
+	}
+	return cfg.Config, nil
+}
+
+// Build builds the Sink described by the given configuration
+func Build(cfg *Config, db database.Database) (Sink, error) {
+	sinks, err := buildSinks(cfg, db)
+	if err != nil {
+		_ = sinks.Close()
+		return nil, err
+	}
+
+	if len(sinks) == 1 {
+		return sinks[0], nil
+	}
+	return sinks, nil
+}
+
+// buildSinks builds each sink listed inside the given configuration, returning the ones built so far on error
+func buildSinks(cfg *Config, db database.Database) (MultiSink, error) {
+	var sinks MultiSink
+	for _, sinkType := range cfg.Types {
+		switch sinkType {
+		case TypeSQL:
+			sinks = append(sinks, NewSQLSink(db))
+
+		case TypeFile:
+			if cfg.File == nil {
+				return sinks, fmt.Errorf("missing file sink configuration")
+			}
+			sink, err := NewFileSink(cfg.File.Path)
+			if err != nil {
+				return sinks, err
+			}
+			sinks = append(sinks, NewBufferedSink(sink))
+
+		case TypeKafka:
+			if cfg.Kafka == nil {
+				return sinks, fmt.Errorf("missing kafka sink configuration")
+			}
+			sinks = append(sinks, NewBufferedSink(NewKafkaSink(cfg.Kafka)))
+
+		default:
+			return sinks, fmt.Errorf("unknown sink type %s", sinkType)
+		}
+	}
+	return sinks, nil
+}
@@ -0,0 +1,52 @@
+package sink
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"sync"
+)
+
+var (
+	_ Sink = &FileSink{}
+)
+
+// FileSink is a Sink that appends each record to a file as a JSON line
+type FileSink struct {
+	mu      sync.Mutex
+	file    *os.File
+	encoder *json.Encoder
+}
+
+// NewFileSink builds a new FileSink instance appending to the file at the given path, creating it if needed
+func NewFileSink(path string) (*FileSink, error) {
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open sink file: %s", err)
+	}
+
+	return &FileSink{
+		file:    file,
+		encoder: json.NewEncoder(file),
+	}, nil
+}
+
+// Emit implements Sink
+func (s *FileSink) Emit(_ context.Context, records ...*Record) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, record := range records {
+		err := s.encoder.Encode(record)
+		if err != nil {
+			return fmt.Errorf("failed to write record to file: %s", err)
+		}
+	}
+	return nil
+}
+
+// Close implements Sink
+func (s *FileSink) Close() error {
+	return s.file.Close()
+}
@@ -0,0 +1,63 @@
+package sink_test
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/forbole/juno/v4/common"
+	"github.com/forbole/juno/v4/models"
+	"github.com/forbole/juno/v4/sink"
+)
+
+func TestFileSink_Emit(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "records.jsonl")
+
+	fileSink, err := sink.NewFileSink(path)
+	require.NoError(t, err)
+
+	err = fileSink.Emit(context.Background(),
+		sink.NewRecord("group", 5, common.HexToHash("0x02"), sink.OperationDelete, &models.Group{GroupName: "group"}),
+		sink.NewRecord("payment", 6, common.Hash{}, sink.OperationSave, &models.StreamRecord{}),
+	)
+	require.NoError(t, err)
+	require.NoError(t, fileSink.Close())
+
+	file, err := os.Open(path)
+	require.NoError(t, err)
+	defer file.Close()
+
+	var entities []string
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		var record struct {
+			Entity    string `json:"entity"`
+			Operation string `json:"operation"`
+		}
+		require.NoError(t, json.Unmarshal(scanner.Bytes(), &record))
+		entities = append(entities, record.Entity+"/"+record.Operation)
+	}
+	require.Equal(t, []string{"groups/delete", "stream_records/save"}, entities)
+}
+
+func TestParseConfig(t *testing.T) {
+	cfg, err := sink.ParseConfig([]byte(`
+sink:
+  types: ["sql", "kafka"]
+  kafka:
+    broker: localhost:9092
+    topic: greenfield
+`))
+	require.NoError(t, err)
+	require.Equal(t, []string{"sql", "kafka"}, cfg.Types)
+	require.Equal(t, "localhost:9092", cfg.Kafka.Broker)
+
+	cfg, err = sink.ParseConfig([]byte(`invalid_field: yes`))
+	require.NoError(t, err)
+	require.Equal(t, []string{sink.TypeSQL}, cfg.Types)
+}
@@ -0,0 +1,309 @@
+package sink
+
+import (
+	"context"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"hash/crc32"
+	"io"
+	"net"
+	"sync"
+	"time"
+)
+
+const (
+	kafkaProduceAPIKey     = 0
+	kafkaProduceAPIVersion = 3
+	kafkaRecordBatchMagic  = 2
+
+	kafkaDefaultClientID = "juno"
+	kafkaDialTimeout     = 10 * time.Second
+	kafkaRequestTimeout  = 30 * time.Second
+
+	// kafkaMaxResponseSize bounds the size of the responses read from the broker
+	kafkaMaxResponseSize = 64 << 20
+)
+
+var (
+	_ Sink = &KafkaSink{}
+
+	crc32c = crc32.MakeTable(crc32.Castagnoli)
+)
+
+// KafkaSink is a Sink that produces each record as a JSON message to a Kafka topic partition.
+// It speaks the Kafka wire protocol directly, sending Produce (v3) requests to the configured broker,
+// which must be the leader of the configured partition. Leadership changes are not followed: the
+// NOT_LEADER_OR_FOLLOWER errors returned by the broker are reported like any other error.
+type KafkaSink struct {
+	cfg *KafkaConfig
+
+	mu            sync.Mutex
+	conn          net.Conn
+	correlationID int32
+}
+
+// NewKafkaSink builds a new KafkaSink instance. The connection to the broker is opened lazily.
+func NewKafkaSink(cfg *KafkaConfig) *KafkaSink {
+	return &KafkaSink{
+		cfg: cfg,
+	}
+}
+
+// Emit implements Sink.
+// All the given records are produced using a single record batch. The record keys are the entity names.
+func (s *KafkaSink) Emit(_ context.Context, records ...*Record) error {
+	if len(records) == 0 {
+		return nil
+	}
+
+	keys := make([][]byte, len(records))
+	values := make([][]byte, len(records))
+	for i, record := range records {
+		value, err := json.Marshal(record)
+		if err != nil {
+			return fmt.Errorf("failed to encode record: %s", err)
+		}
+		keys[i], values[i] = []byte(record.Entity), value
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.correlationID++
+	request := encodeProduceRequest(s.correlationID, s.clientID(), s.cfg.RequiredAcks, s.cfg.Topic, s.cfg.Partition,
+		encodeRecordBatch(time.Now(), keys, values))
+
+	err := s.send(request)
+	if err != nil {
+		s.closeConn()
+		return fmt.Errorf("failed to produce records to kafka: %s", err)
+	}
+	return nil
+}
+
+// send writes the given request to the broker and, unless no acknowledgement is required, checks its response
+func (s *KafkaSink) send(request []byte) error {
+	if s.conn == nil {
+		conn, err := net.DialTimeout("tcp", s.cfg.Broker, kafkaDialTimeout)
+		if err != nil {
+			return err
+		}
+		s.conn = conn
+	}
+
+	err := s.conn.SetDeadline(time.Now().Add(kafkaRequestTimeout))
+	if err != nil {
+		return err
+	}
+
+	_, err = s.conn.Write(request)
+	if err != nil {
+		return err
+	}
+
+	if s.cfg.RequiredAcks == 0 {
+		return nil
+	}
+
+	return readProduceResponse(s.conn, s.correlationID)
+}
+
+func (s *KafkaSink) clientID() string {
+	if s.cfg.ClientID == "" {
+		return kafkaDefaultClientID
+	}
+	return s.cfg.ClientID
+}
+
+func (s *KafkaSink) closeConn() {
+	if s.conn != nil {
+		_ = s.conn.Close()
+		s.conn = nil
+	}
+}
+
+// Close implements Sink
+func (s *KafkaSink) Close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.closeConn()
+	return nil
+}
+
+// ---------------------------------------------------------------------------------------------------------------------
+
+// kafkaEncoder allows to write the primitive types of the Kafka protocol
+type kafkaEncoder struct {
+	buf []byte
+}
+
+func (e *kafkaEncoder) int8(v int8)   { e.buf = append(e.buf, byte(v)) }
+func (e *kafkaEncoder) int16(v int16) { e.buf = binary.BigEndian.AppendUint16(e.buf, uint16(v)) }
+func (e *kafkaEncoder) int32(v int32) { e.buf = binary.BigEndian.AppendUint32(e.buf, uint32(v)) }
+func (e *kafkaEncoder) int64(v int64) { e.buf = binary.BigEndian.AppendUint64(e.buf, uint64(v)) }
+func (e *kafkaEncoder) varint(v int64) {
+	e.buf = binary.AppendVarint(e.buf, v)
+}
+
+func (e *kafkaEncoder) string(v string) {
+	e.int16(int16(len(v)))
+	e.buf = append(e.buf, v...)
+}
+
+func (e *kafkaEncoder) nullableString(v *string) {
+	if v == nil {
+		e.int16(-1)
+		return
+	}
+	e.string(*v)
+}
+
+func (e *kafkaEncoder) bytes(v []byte) {
+	e.int32(int32(len(v)))
+	e.buf = append(e.buf, v...)
+}
+
+func (e *kafkaEncoder) varintBytes(v []byte) {
+	if v == nil {
+		e.varint(-1)
+		return
+	}
+	e.varint(int64(len(v)))
+	e.buf = append(e.buf, v...)
+}
+
+// encodeProduceRequest encodes a size delimited Produce request containing the given record batch
+func encodeProduceRequest(correlationID int32, clientID string, acks int16, topic string, partition int32, batch []byte) []byte {
+	var e kafkaEncoder
+
+	// Header
+	e.int16(kafkaProduceAPIKey)
+	e.int16(kafkaProduceAPIVersion)
+	e.int32(correlationID)
+	e.string(clientID)
+
+	// Body
+	e.nullableString(nil) // transactional id
+	e.int16(acks)
+	e.int32(int32(kafkaRequestTimeout / time.Millisecond))
+	e.int32(1) // topics
+	e.string(topic)
+	e.int32(1) // partitions
+	e.int32(partition)
+	e.bytes(batch)
+
+	var framed kafkaEncoder
+	framed.bytes(e.buf)
+	return framed.buf
+}
+
+// encodeRecordBatch encodes the given keys and values as a (v2) record batch
+func encodeRecordBatch(timestamp time.Time, keys, values [][]byte) []byte {
+	millis := timestamp.UnixMilli()
+
+	// Everything covered by the CRC, starting from the attributes
+	var body kafkaEncoder
+	body.int16(0) // attributes
+	body.int32(int32(len(values) - 1))
+	body.int64(millis) // first timestamp
+	body.int64(millis) // max timestamp
+	body.int64(-1)     // producer id
+	body.int16(-1)     // producer epoch
+	body.int32(-1)     // base sequence
+	body.int32(int32(len(values)))
+	for i := range values {
+		var record kafkaEncoder
+		record.int8(0)          // attributes
+		record.varint(0)        // timestamp delta
+		record.varint(int64(i)) // offset delta
+		record.varintBytes(keys[i])
+		record.varintBytes(values[i])
+		record.varint(0) // headers
+
+		body.varint(int64(len(record.buf)))
+		body.buf = append(body.buf, record.buf...)
+	}
+
+	var e kafkaEncoder
+	e.int64(0) // base offset
+	e.int32(int32(4 + 1 + 4 + len(body.buf)))
+	e.int32(-1) // partition leader epoch
+	e.int8(kafkaRecordBatchMagic)
+	e.buf = binary.BigEndian.AppendUint32(e.buf, crc32.Checksum(body.buf, crc32c))
+	e.buf = append(e.buf, body.buf...)
+	return e.buf
+}
+
+// readProduceResponse reads a (v3) Produce response, returning an error if any partition reports one
+func readProduceResponse(r io.Reader, correlationID int32) error {
+	var size int32
+	err := binary.Read(r, binary.BigEndian, &size)
+	if err != nil {
+		return err
+	}
+
+	if size < 0 || size > kafkaMaxResponseSize {
+		return fmt.Errorf("invalid response size %d", size)
+	}
+
+	bz := make([]byte, size)
+	_, err = io.ReadFull(r, bz)
+	if err != nil {
+		return err
+	}
+
+	d := kafkaDecoder{buf: bz}
+	if id := d.int32(); id != correlationID {
+		return fmt.Errorf("unexpected correlation id %d, expected %d", id, correlationID)
+	}
+
+	topics := d.int32()
+	for i := int32(0); i < topics; i++ {
+		topic := d.string()
+		partitions := d.int32()
+		for j := int32(0); j < partitions; j++ {
+			partition := d.int32()
+			errorCode := d.int16()
+			d.int64() // base offset
+			d.int64() // log append time
+			if errorCode != 0 {
+				return fmt.Errorf("broker returned error code %d for %s/%d", errorCode, topic, partition)
+			}
+		}
+	}
+
+	return d.err
+}
+
+// kafkaDecoder allows to read the primitive types of the Kafka protocol
+type kafkaDecoder struct {
+	buf []byte
+	err error
+}
+
+func (d *kafkaDecoder) next(n int) []byte {
+	if d.err != nil {
+		return make([]byte, n)
+	}
+	if len(d.buf) < n {
+		d.err = io.ErrUnexpectedEOF
+		return make([]byte, n)
+	}
+	bz := d.buf[:n]
+	d.buf = d.buf[n:]
+	return bz
+}
+
+func (d *kafkaDecoder) int16() int16 { return int16(binary.BigEndian.Uint16(d.next(2))) }
+func (d *kafkaDecoder) int32() int32 { return int32(binary.BigEndian.Uint32(d.next(4))) }
+func (d *kafkaDecoder) int64() int64 { return int64(binary.BigEndian.Uint64(d.next(8))) }
+
+func (d *kafkaDecoder) string() string {
+	length := d.int16()
+	if length < 0 {
+		return ""
+	}
+	return string(d.next(int(length)))
+}
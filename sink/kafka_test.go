@@ -0,0 +1,221 @@
+package sink_test
+
+import (
+	"context"
+	"encoding/binary"
+	"encoding/json"
+	"hash/crc32"
+	"io"
+	"net"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/forbole/juno/v4/common"
+	"github.com/forbole/juno/v4/models"
+	"github.com/forbole/juno/v4/sink"
+)
+
+// produceRequest contains the parts of a Produce request checked by the tests
+type produceRequest struct {
+	correlationID int32
+	topic         string
+	partition     int32
+	keys          []string
+	values        [][]byte
+}
+
+// brokerStandIn accepts a single connection and answers each Produce request with the given error code
+func brokerStandIn(t *testing.T, errorCode int16) (string, <-chan *produceRequest) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	t.Cleanup(func() { listener.Close() })
+
+	requests := make(chan *produceRequest, 10)
+	go func() {
+		conn, err := listener.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+
+		for {
+			var size int32
+			if binary.Read(conn, binary.BigEndian, &size) != nil {
+				return
+			}
+			bz := make([]byte, size)
+			if _, err := io.ReadFull(conn, bz); err != nil {
+				return
+			}
+
+			req := decodeProduceRequest(t, bz)
+			requests <- req
+
+			var res []byte
+			res = binary.BigEndian.AppendUint32(res, uint32(req.correlationID))
+			res = binary.BigEndian.AppendUint32(res, 1)
+			res = binary.BigEndian.AppendUint16(res, uint16(len(req.topic)))
+			res = append(res, req.topic...)
+			res = binary.BigEndian.AppendUint32(res, 1)
+			res = binary.BigEndian.AppendUint32(res, uint32(req.partition))
+			res = binary.BigEndian.AppendUint16(res, uint16(errorCode))
+			res = binary.BigEndian.AppendUint64(res, 0)
+			res = binary.BigEndian.AppendUint64(res, 0)
+			res = binary.BigEndian.AppendUint32(res, 0) // throttle time
+
+			framed := binary.BigEndian.AppendUint32(nil, uint32(len(res)))
+			if _, err := conn.Write(append(framed, res...)); err != nil {
+				return
+			}
+		}
+	}()
+
+	return listener.Addr().String(), requests
+}
+
+func decodeProduceRequest(t *testing.T, bz []byte) *produceRequest {
+	d := &decoder{t: t, buf: bz}
+	req := &produceRequest{}
+
+	require.Equal(t, int16(0), d.int16()) // api key
+	require.Equal(t, int16(3), d.int16()) // api version
+	req.correlationID = d.int32()
+	d.string()                             // client id
+	require.Equal(t, int16(-1), d.int16()) // transactional id
+	d.int16()                              // acks
+	d.int32()                              // timeout
+	require.Equal(t, int32(1), d.int32())
+	req.topic = d.string()
+	require.Equal(t, int32(1), d.int32())
+	req.partition = d.int32()
+
+	batch := &decoder{t: t, buf: d.next(int(d.int32()))}
+	batch.int64() // base offset
+	require.Equal(t, len(batch.buf)-4, int(batch.int32()))
+	batch.int32() // partition leader epoch
+	require.Equal(t, byte(2), batch.next(1)[0])
+	crc := uint32(batch.int32())
+	require.Equal(t, crc32.Checksum(batch.buf, crc32.MakeTable(crc32.Castagnoli)), crc)
+
+	batch.next(2 + 4 + 8 + 8 + 8 + 2 + 4)
+	count := batch.int32()
+	for i := int32(0); i < count; i++ {
+		record := &decoder{t: t, buf: batch.next(int(batch.varint()))}
+		record.next(1)  // attributes
+		record.varint() // timestamp delta
+		require.Equal(t, int64(i), record.varint())
+		req.keys = append(req.keys, string(record.next(int(record.varint()))))
+		req.values = append(req.values, record.next(int(record.varint())))
+		require.Equal(t, int64(0), record.varint())
+	}
+
+	return req
+}
+
+type decoder struct {
+	t   *testing.T
+	buf []byte
+}
+
+func (d *decoder) next(n int) []byte {
+	require.GreaterOrEqual(d.t, len(d.buf), n)
+	bz := d.buf[:n]
+	d.buf = d.buf[n:]
+	return bz
+}
+
+func (d *decoder) int16() int16 { return int16(binary.BigEndian.Uint16(d.next(2))) }
+func (d *decoder) int32() int32 { return int32(binary.BigEndian.Uint32(d.next(4))) }
+func (d *decoder) int64() int64 { return int64(binary.BigEndian.Uint64(d.next(8))) }
+func (d *decoder) string() string {
+	return string(d.next(int(d.int16())))
+}
+func (d *decoder) varint() int64 {
+	v, n := binary.Varint(d.buf)
+	require.Greater(d.t, n, 0)
+	d.buf = d.buf[n:]
+	return v
+}
+
+func TestKafkaSink_Emit(t *testing.T) {
+	address, requests := brokerStandIn(t, 0)
+
+	kafkaSink := sink.NewKafkaSink(&sink.KafkaConfig{
+		Broker:       address,
+		Topic:        "greenfield",
+		Partition:    2,
+		RequiredAcks: 1,
+	})
+	defer kafkaSink.Close()
+
+	records := []*sink.Record{
+		sink.NewRecord("bucket", 10, common.HexToHash("0x01"), sink.OperationSave, &models.Bucket{BucketName: "first"}),
+		sink.NewRecord("object", 10, common.HexToHash("0x01"), sink.OperationUpdate, &models.Object{ObjectName: "second"}),
+	}
+	require.NoError(t, kafkaSink.Emit(context.Background(), records...))
+	require.NoError(t, kafkaSink.Emit(context.Background(), records[0]))
+
+	req := <-requests
+	require.Equal(t, int32(1), req.correlationID)
+	require.Equal(t, "greenfield", req.topic)
+	require.Equal(t, int32(2), req.partition)
+	require.Equal(t, []string{"buckets", "objects"}, req.keys)
+
+	var decoded struct {
+		Module    string
+		Operation string
+		Height    int64
+		Data      struct{ ObjectName string }
+	}
+	require.NoError(t, json.Unmarshal(req.values[1], &decoded))
+	require.Equal(t, "object", decoded.Module)
+	require.Equal(t, "update", decoded.Operation)
+	require.Equal(t, int64(10), decoded.Height)
+	require.Equal(t, "second", decoded.Data.ObjectName)
+
+	req = <-requests
+	require.Equal(t, int32(2), req.correlationID)
+	require.Equal(t, []string{"buckets"}, req.keys)
+}
+
+func TestKafkaSink_EmitError(t *testing.T) {
+	address, _ := brokerStandIn(t, 3)
+
+	kafkaSink := sink.NewKafkaSink(&sink.KafkaConfig{Broker: address, Topic: "greenfield", RequiredAcks: 1})
+	defer kafkaSink.Close()
+
+	record := sink.NewRecord("bucket", 1, common.Hash{}, sink.OperationSave, &models.Bucket{})
+	require.Error(t, kafkaSink.Emit(context.Background(), record))
+}
+
+func TestKafkaSink_EmitInvalidResponseSize(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	t.Cleanup(func() { listener.Close() })
+
+	go func() {
+		conn, err := listener.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+
+		var size int32
+		if binary.Read(conn, binary.BigEndian, &size) != nil {
+			return
+		}
+		if _, err := io.CopyN(io.Discard, conn, int64(size)); err != nil {
+			return
+		}
+
+		// a negative size must be refused instead of being used to allocate the response
+		_, _ = conn.Write(binary.BigEndian.AppendUint32(nil, 0xffffffff))
+	}()
+
+	kafkaSink := sink.NewKafkaSink(&sink.KafkaConfig{Broker: listener.Addr().String(), Topic: "greenfield", RequiredAcks: 1})
+	defer kafkaSink.Close()
+
+	record := sink.NewRecord("bucket", 1, common.Hash{}, sink.OperationSave, &models.Bucket{})
+	require.ErrorContains(t, kafkaSink.Emit(context.Background(), record), "invalid response size")
+}
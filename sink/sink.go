@@ -0,0 +1,104 @@
+package sink
+
+import (
+	"context"
+
+	"gorm.io/gorm/schema"
+
+	"github.com/forbole/juno/v4/common"
+)
+
+// Operation represents the kind of change described by a Record
+type Operation string
+
+const (
+	// OperationSave creates the entity, replacing it if it already exists
+	OperationSave Operation = "save"
+
+	// OperationUpdate updates the non-zero fields of an existing entity
+	OperationUpdate Operation = "update"
+
+	// OperationDelete removes the entity
+	OperationDelete Operation = "delete"
+
+	// OperationRollback removes all the entities changed by the module after the height of the record.
+	// It is emitted by the rollback command, along with a Rollback data.
+	OperationRollback Operation = "rollback"
+)
+
+// Record represents a typed change of the indexed state, emitted by a module while handling a block.
+// Data contains the model whose table is named by Entity.
+type Record struct {
+	Module    string        `json:"module"`
+	Entity    string        `json:"entity"`
+	Operation Operation     `json:"operation"`
+	Height    int64         `json:"height"`
+	TxHash    common.Hash   `json:"tx_hash"`
+	Data      schema.Tabler `json:"data"`
+}
+
+// NewRecord allows to build a new Record instance
+func NewRecord(module string, height int64, txHash common.Hash, operation Operation, data schema.Tabler) *Record {
+	return &Record{
+		Module:    module,
+		Entity:    data.TableName(),
+		Operation: operation,
+		Height:    height,
+		TxHash:    txHash,
+		Data:      data,
+	}
+}
+
+// Rollback is the data of the records telling that the changes of a module after their height were removed,
+// after a chain fork was detected
+type Rollback struct{}
+
+func (*Rollback) TableName() string {
+	return "rollback"
+}
+
+// Sink represents a destination to which modules emit the changes they apply.
+// Emit is called within the database transaction of the height being processed, which is carried by ctx.
+// Sinks that are not backed by the database are wrapped inside a BufferedSink, so that the records emitted while
+// processing a height are only delivered once its transaction is committed, the ones discarded along with
+// the database changes never being delivered.
+type Sink interface {
+	// Emit writes the given records, in order.
+	// An error is returned if the operation fails.
+	Emit(ctx context.Context, records ...*Record) error
+
+	// Close releases all the resources held by the sink
+	Close() error
+}
+
+// ---------------------------------------------------------------------------------------------------------------------
+
+var (
+	_ Sink = MultiSink{}
+)
+
+// MultiSink is a Sink that emits every record to all the given sinks, in order
+type MultiSink []Sink
+
+// Emit implements Sink
+func (s MultiSink) Emit(ctx context.Context, records ...*Record) error {
+	for _, sink := range s {
+		err := sink.Emit(ctx, records...)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Close implements Sink
+func (s MultiSink) Close() error {
+	var firstErr error
+	for _, sink := range s {
+		err := sink.Close()
+		if err != nil && firstErr == nil {
+			firstErr = err
+		}
+	}
+	return firstErr
+}
@@ -0,0 +1,308 @@
+package sink
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/forbole/juno/v4/database"
+	"github.com/forbole/juno/v4/models"
+)
+
+var (
+	_ Sink = &SQLSink{}
+)
+
+// SQLSink is a Sink that applies the records to the database tables
+type SQLSink struct {
+	db database.Database
+}
+
+// NewSQLSink builds a new SQLSink instance
+func NewSQLSink(db database.Database) *SQLSink {
+	return &SQLSink{
+		db: db,
+	}
+}
+
+// Emit implements Sink.
+// Consecutive group members, membership changes, statements, stream outflows, validator voting powers, transfers,
+// account balances, account transactions and messages being saved are written using a single query.
+func (s *SQLSink) Emit(ctx context.Context, records ...*Record) error {
+	for i := 0; i < len(records); {
+		// the rows removed by a rollback are deleted by the rollback command itself
+		if records[i].Operation == OperationRollback {
+			i++
+			continue
+		}
+
+		end := i + 1
+		for end < len(records) && canBatch(records[i], records[end]) {
+			end++
+		}
+
+		err := s.emit(ctx, records[i:end])
+		if err != nil {
+			return err
+		}
+		i = end
+	}
+	return nil
+}
+
+// canBatch tells whether the two given records can be written using the same query
+func canBatch(first, second *Record) bool {
+	if first.Operation != OperationSave || second.Operation != OperationSave || first.Entity != second.Entity {
+		return false
+	}
+
+	switch first.Data.(type) {
+	case *models.GroupMember, *models.GroupMemberChange, *models.Statements, *models.StreamOutflow, *models.ValidatorVotingPower,
+		*models.ValidatorUptime, *models.Transfer, *models.AccountBalance, *models.AccountTx, *models.Message:
+		return true
+	default:
+		return false
+	}
+}
+
+// emit writes the given records, which are either a single record or a batch of records that canBatch
+func (s *SQLSink) emit(ctx context.Context, records []*Record) error {
+	record := records[0]
+	switch data := record.Data.(type) {
+	case *models.Bucket:
+		switch record.Operation {
+		case OperationSave:
+			return s.db.SaveBucket(ctx, data)
+		case OperationUpdate:
+			return s.db.UpdateBucket(ctx, data)
+		}
+
+	case *models.BucketQuotaChange:
+		if record.Operation == OperationSave {
+			return s.db.SaveBucketQuotaChange(ctx, data)
+		}
+
+	case *models.Object:
+		switch record.Operation {
+		case OperationSave:
+			return s.db.SaveObject(ctx, data)
+		case OperationUpdate:
+			return s.db.UpdateObject(ctx, data)
+		}
+
+	case *models.Group:
+		switch record.Operation {
+		case OperationSave:
+			return s.db.SaveGroup(ctx, data)
+		case OperationUpdate:
+			return s.db.UpdateGroup(ctx, data)
+		}
+
+	case *models.GroupMember:
+		switch record.Operation {
+		case OperationSave:
+			members := make([]*models.GroupMember, len(records))
+			for i, record := range records {
+				members[i] = record.Data.(*models.GroupMember)
+			}
+			return s.db.SaveGroupMembers(ctx, members)
+		case OperationUpdate:
+			return s.db.UpdateGroupMember(ctx, data)
+		}
+
+	case *models.GroupMemberChange:
+		if record.Operation == OperationSave {
+			changes := make([]*models.GroupMemberChange, len(records))
+			for i, record := range records {
+				changes[i] = record.Data.(*models.GroupMemberChange)
+			}
+			return s.db.SaveGroupMemberChanges(ctx, changes)
+		}
+
+	case *models.Proposal:
+		if record.Operation == OperationSave {
+			return s.db.SaveProposal(ctx, data)
+		}
+
+	case *models.ProposalDeposit:
+		if record.Operation == OperationSave {
+			return s.db.SaveProposalDeposit(ctx, data)
+		}
+
+	case *models.ProposalVote:
+		if record.Operation == OperationSave {
+			return s.db.SaveProposalVote(ctx, data)
+		}
+
+	case *models.CrossChainPackage:
+		if record.Operation == OperationSave {
+			return s.db.SaveCrossChainPackage(ctx, data)
+		}
+
+	case *models.Mirror:
+		switch record.Operation {
+		case OperationSave:
+			return s.db.SaveMirror(ctx, data)
+		case OperationUpdate:
+			return s.db.UpdateMirror(ctx, data)
+		}
+
+	case *models.ValidatorInfo:
+		if record.Operation == OperationSave {
+			return s.db.SaveValidatorInfo(ctx, data)
+		}
+
+	case *models.ValidatorDescription:
+		if record.Operation == OperationSave {
+			return s.db.SaveValidatorDescription(ctx, data)
+		}
+
+	case *models.ValidatorCommission:
+		if record.Operation == OperationSave {
+			return s.db.SaveValidatorCommission(ctx, data)
+		}
+
+	case *models.ValidatorStatus:
+		if record.Operation == OperationSave {
+			return s.db.SaveValidatorStatus(ctx, data)
+		}
+
+	case *models.ValidatorSigningInfo:
+		if record.Operation == OperationSave {
+			return s.db.SaveValidatorSigningInfo(ctx, data)
+		}
+
+	case *models.ValidatorVotingPower:
+		if record.Operation == OperationSave {
+			powers := make([]*models.ValidatorVotingPower, len(records))
+			for i, record := range records {
+				powers[i] = record.Data.(*models.ValidatorVotingPower)
+			}
+			return s.db.SaveValidatorVotingPowers(ctx, powers)
+		}
+
+	case *models.ValidatorUptime:
+		if record.Operation == OperationSave {
+			uptimes := make([]*models.ValidatorUptime, len(records))
+			for i, record := range records {
+				uptimes[i] = record.Data.(*models.ValidatorUptime)
+			}
+			return s.db.SaveValidatorUptimes(ctx, uptimes)
+		}
+
+	case *models.Transfer:
+		switch record.Operation {
+		case OperationSave:
+			transfers := make([]*models.Transfer, len(records))
+			for i, record := range records {
+				transfers[i] = record.Data.(*models.Transfer)
+			}
+			return s.db.SaveTransfers(ctx, transfers)
+		case OperationDelete:
+			_, err := s.db.DeleteAt(ctx, data, "height", data.Height)
+			return err
+		}
+
+	case *models.AccountBalance:
+		switch record.Operation {
+		case OperationSave:
+			balances := make([]*models.AccountBalance, len(records))
+			for i, record := range records {
+				balances[i] = record.Data.(*models.AccountBalance)
+			}
+			return s.db.SaveAccountBalances(ctx, balances)
+		case OperationDelete:
+			_, err := s.db.DeleteAt(ctx, data, "height", data.Height)
+			return err
+		}
+
+	case *models.AccountTx:
+		if record.Operation == OperationSave {
+			accountTxs := make([]*models.AccountTx, len(records))
+			for i, record := range records {
+				accountTxs[i] = record.Data.(*models.AccountTx)
+			}
+			return s.db.SaveAccountTxs(ctx, accountTxs)
+		}
+
+	case *models.Message:
+		if record.Operation == OperationSave {
+			msgs := make([]*models.Message, len(records))
+			for i, record := range records {
+				msgs[i] = record.Data.(*models.Message)
+			}
+			return s.db.SaveMessages(ctx, msgs)
+		}
+
+	case *models.Permission:
+		switch record.Operation {
+		case OperationSave:
+			return s.db.SavePermission(ctx, data)
+		case OperationUpdate:
+			return s.db.UpdatePermission(ctx, data)
+		}
+
+	case *models.Statements:
+		switch record.Operation {
+		case OperationSave:
+			statements := make([]*models.Statements, len(records))
+			for i, record := range records {
+				statements[i] = record.Data.(*models.Statements)
+			}
+			return s.db.MultiSaveStatement(ctx, statements)
+		case OperationDelete:
+			return s.db.RemoveStatements(ctx, data.PolicyID)
+		}
+
+	case *models.PaymentAccount:
+		if record.Operation == OperationSave {
+			return s.db.SavePaymentAccount(ctx, data)
+		}
+
+	case *models.PaymentTransfer:
+		if record.Operation == OperationSave {
+			return s.db.SavePaymentTransfer(ctx, data)
+		}
+
+	case *models.StorageProvider:
+		switch record.Operation {
+		case OperationSave:
+			return s.db.SaveStorageProvider(ctx, data)
+		case OperationUpdate:
+			return s.db.UpdateStorageProvider(ctx, data)
+		}
+
+	case *models.StoragePrice:
+		if record.Operation == OperationSave {
+			return s.db.SaveStoragePrice(ctx, data)
+		}
+
+	case *models.StreamRecord:
+		if record.Operation == OperationSave {
+			return s.db.SaveStreamRecord(ctx, data)
+		}
+
+	case *models.StreamRecordHistory:
+		if record.Operation == OperationSave {
+			return s.db.SaveStreamRecordHistory(ctx, data)
+		}
+
+	case *models.StreamOutflow:
+		switch record.Operation {
+		case OperationSave:
+			outflows := make([]*models.StreamOutflow, len(records))
+			for i, record := range records {
+				outflows[i] = record.Data.(*models.StreamOutflow)
+			}
+			return s.db.SaveStreamOutflows(ctx, outflows)
+		case OperationDelete:
+			return s.db.DeleteStreamOutflows(ctx, data.FromAddress)
+		}
+	}
+
+	return fmt.Errorf("unsupported %s operation on %s", record.Operation, record.Entity)
+}
+
+// Close implements Sink
+func (s *SQLSink) Close() error {
+	return nil
+}
@@ -55,8 +55,9 @@ func (c Config) GetBytes() ([]byte, error) {
 // ---------------------------------------------------------------------------------------------------------------------
 
 type ChainConfig struct {
-	Bech32Prefix string   `yaml:"bech32_prefix"`
-	Modules      []string `yaml:"modules"`
+	Bech32Prefix string                 `yaml:"bech32_prefix"`
+	Modules      []string               `yaml:"modules"`
+	OnError      map[string]ErrorPolicy `yaml:"on_error,omitempty"`
 }
 
 // NewChainConfig returns a new ChainConfig instance
@@ -72,6 +73,18 @@ func DefaultChainConfig() ChainConfig {
 	return NewChainConfig("cosmos", nil)
 }
 
+// GetErrorPolicy returns the policy to be applied when a handler of the given module fails.
+// If no policy is set for the module, ErrorPolicyHalt is returned.
+func (cfg ChainConfig) GetErrorPolicy(moduleName string) ErrorPolicy {
+	for module, policy := range cfg.OnError {
+		if strings.EqualFold(module, moduleName) {
+			return policy
+		}
+	}
+
+	return ErrorPolicyHalt
+}
+
 func (cfg ChainConfig) IsModuleEnabled(moduleName string) bool {
 	for _, module := range cfg.Modules {
 		if strings.EqualFold(module, moduleName) {
@@ -0,0 +1,42 @@
+package config
+
+import (
+	"fmt"
+
+	"gopkg.in/yaml.v3"
+)
+
+// ErrorPolicy tells what to do when a module fails to handle a block, transaction, message or event
+type ErrorPolicy string
+
+const (
+	// ErrorPolicyLog logs the error, discards the changes made by the failed handler and goes on
+	ErrorPolicyLog ErrorPolicy = "log"
+
+	// ErrorPolicyHalt fails the processing of the height, so that it is retried later
+	ErrorPolicyHalt ErrorPolicy = "halt"
+
+	// ErrorPolicyRetry calls the failed handler again a few times before halting
+	ErrorPolicyRetry ErrorPolicy = "retry"
+
+	// ErrorPolicyQuarantine stores the failed event so that it can be replayed later, and goes on.
+	// Failed blocks, transactions and messages cannot be replayed on their own, so they halt instead.
+	ErrorPolicyQuarantine ErrorPolicy = "quarantine"
+)
+
+// UnmarshalYAML implements yaml.Unmarshaler
+func (p *ErrorPolicy) UnmarshalYAML(node *yaml.Node) error {
+	var value string
+	err := node.Decode(&value)
+	if err != nil {
+		return err
+	}
+
+	switch policy := ErrorPolicy(value); policy {
+	case ErrorPolicyLog, ErrorPolicyHalt, ErrorPolicyRetry, ErrorPolicyQuarantine:
+		*p = policy
+		return nil
+	default:
+		return fmt.Errorf("invalid error policy %s, must be one of log, halt, retry or quarantine", value)
+	}
+}
@@ -52,3 +52,22 @@ database:
 	require.Equal(t, "cosmos", cfg.Chain.Bech32Prefix)
 	require.Equal(t, []string{"pruning"}, cfg.Chain.Modules)
 }
+
+func TestDefaultConfigParser_ErrorPolicies(t *testing.T) {
+	cfg, err := DefaultConfigParser([]byte(`
+chain:
+  modules: [bucket, object]
+  on_error:
+    object: quarantine
+`))
+	require.NoError(t, err)
+	require.Equal(t, ErrorPolicyQuarantine, cfg.Chain.GetErrorPolicy("object"))
+	require.Equal(t, ErrorPolicyHalt, cfg.Chain.GetErrorPolicy("bucket"))
+
+	_, err = DefaultConfigParser([]byte(`
+chain:
+  on_error:
+    object: ignore
+`))
+	require.Error(t, err)
+}
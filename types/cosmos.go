@@ -25,6 +25,8 @@ func NewValidator(consAddr string, consPubKey string) *Validator {
 // -------------------------------------------------------------------------------------------------------------------
 
 // CommitSig contains the data of a single validator commit signature
+//
+// Deprecated: the commit signatures are stored as models.PreCommit, which Database.SaveCommitSignatures now takes.
 type CommitSig struct {
 	Height           int64
 	ValidatorAddress string
@@ -34,6 +36,8 @@ type CommitSig struct {
 }
 
 // NewCommitSig allows to build a new CommitSign object
+//
+// Deprecated: build a models.PreCommit instead.
 func NewCommitSig(validatorAddress string, votingPower, proposerPriority, height int64, timestamp time.Time) *CommitSig {
 	return &CommitSig{
 		Height:           height,
@@ -46,8 +50,6 @@ func NewCommitSig(validatorAddress string, votingPower, proposerPriority, height
 
 // -------------------------------------------------------------------------------------------------------------------
 
-// -------------------------------------------------------------------------------------------------------------------
-
 // Tx represents an already existing blockchain transaction
 type Tx struct {
 	*tx.Tx